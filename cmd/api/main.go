@@ -3,7 +3,6 @@ package main
 import (
 	"context"
 	"errors"
-	"fmt"
 	"log"
 	"net/http"
 	"os"
@@ -19,8 +18,10 @@ import (
 	"github.com/glennprays/xyz-fin/internal/app/router"
 	"github.com/glennprays/xyz-fin/internal/app/service"
 	"github.com/glennprays/xyz-fin/internal/app/usecase"
+	"github.com/glennprays/xyz-fin/internal/app/worker"
 	"github.com/glennprays/xyz-fin/pkg/auth"
 	"github.com/glennprays/xyz-fin/pkg/hasher"
+	"github.com/glennprays/xyz-fin/pkg/quote"
 )
 
 func main() {
@@ -31,13 +32,7 @@ func main() {
 
 	log.Println("initializing database connection...")
 	dbConfig := database.Config{
-		DSN: fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable TimeZone=Asia/Jakarta",
-			cfg.DBHost,
-			cfg.DBPort,
-			cfg.DBUser,
-			cfg.DBPassword,
-			cfg.DBName,
-		),
+		DSN:             cfg.DatabaseDSN(),
 		MaxIdleConns:    10,
 		MaxOpenConns:    100,
 		ConnMaxLifetime: time.Hour,
@@ -71,20 +66,148 @@ func main() {
 	consumerRepo := repository.NewConsumerRepository(db)
 	consumerLimitRepo := repository.NewConsumerLimitRepository(db)
 	transactionRepo := repository.NewTransactionRepository(db)
+	staffRepo := repository.NewStaffRepository(db)
+	limitChangeRequestRepo := repository.NewLimitChangeRequestRepository(db)
+	limitHoldRepo := repository.NewLimitHoldRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	rateCardRepo := repository.NewRateCardRepository(db)
+	installmentRepo := repository.NewInstallmentRepository(db)
+	paymentRepo := repository.NewPaymentRepository(db)
+	transitionRepo := repository.NewTransactionStatusTransitionRepository(db)
+	earlySettlementRepo := repository.NewEarlySettlementRepository(db)
+	penaltyChargeRepo := repository.NewPenaltyChargeRepository(db)
+	idempotencyKeyRepo := repository.NewIdempotencyKeyRepository(db)
+	contractCancellationRepo := repository.NewContractCancellationRepository(db)
+	merchantNotificationRepo := repository.NewMerchantNotificationRepository(db)
+	merchantRepo := repository.NewMerchantRepository(db)
+	merchantOutletRepo := repository.NewMerchantOutletRepository(db)
 
 	log.Println("initializing services...")
-	transactionService := service.NewTransactionService()
+	contractNumbers, err := service.NewContractNumberGenerator(cfg.ContractBranchCode)
+	if err != nil {
+		log.Fatalf("Failed to configure contract numbering: %v", err)
+	}
+
+	limitEngine := service.NewLimitEngine(service.LimitRules{
+		Tenors:              cfg.LimitTenors,
+		MinAge:              cfg.LimitMinAge,
+		MaxAgeAtMaturity:    cfg.LimitMaxAgeAtMaturity,
+		MinIncome:           cfg.LimitMinIncome,
+		MaxInstallmentRatio: cfg.LimitMaxInstallmentRatio,
+		InterestLoadRate:    cfg.LimitInterestLoadRate,
+		IncomeMultiplierCap: cfg.LimitIncomeMultiplierCap,
+		RoundingUnit:        cfg.LimitRoundingUnit,
+	})
+
+	limitPolicies, err := service.NewLimitPolicyResolver(cfg.LimitPolicyDefault, cfg.LimitPolicyByProduct)
+	if err != nil {
+		log.Fatalf("Failed to configure limit policies: %v", err)
+	}
+
+	paymentAllocator, err := service.NewPaymentAllocator(cfg.PaymentAllocationOrder)
+	if err != nil {
+		log.Fatalf("Failed to configure payment allocation: %v", err)
+	}
+
+	earlySettlementCalculator, err := service.NewEarlySettlementCalculator(service.EarlySettlementRules{
+		TerminationFeeRate: cfg.EarlySettlementFeeRate,
+		MinTerminationFee:  cfg.EarlySettlementMinFee,
+		RebateRule:         cfg.EarlySettlementRebateRule,
+	})
+	if err != nil {
+		log.Fatalf("Failed to configure early settlement: %v", err)
+	}
+
+	penaltyCalculator, err := service.NewPenaltyCalculator(service.PenaltyLimits{
+		MaxDailyRate: cfg.PenaltyMaxDailyRate,
+		MaxCapRate:   cfg.PenaltyMaxCapRate,
+	})
+	if err != nil {
+		log.Fatalf("Failed to configure penalties: %v", err)
+	}
 
 	log.Println("initializing usecases...")
 	consumerUsecase := usecase.NewConsumerUsecase(consumerRepo, jwtManager, *argonHasher)
 	transactionUsecase := usecase.NewTransactionUsecase(
 		db,
-		transactionService,
+		contractNumbers,
 		transactionRepo,
 		consumerRepo,
 		consumerLimitRepo,
+		limitHoldRepo,
+		productRepo,
+		rateCardRepo,
+		installmentRepo,
+		transitionRepo,
+		idempotencyKeyRepo,
+		paymentRepo,
+		merchantRepo,
+		merchantOutletRepo,
+		limitPolicies,
+		quote.NewSigner(cfg.QuoteSecret, cfg.QuoteTTL),
+	)
+	consumerLimitUsecase := usecase.NewConsumerLimitUsecase(
+		db,
+		consumerRepo,
+		consumerLimitRepo,
+		transactionRepo,
+		limitHoldRepo,
+		limitEngine,
+		limitPolicies,
+	)
+	staffUsecase := usecase.NewStaffUsecase(staffRepo, merchantRepo, jwtManager, *argonHasher)
+	limitChangeRequestUsecase := usecase.NewLimitChangeRequestUsecase(
+		db,
+		limitChangeRequestRepo,
+		consumerRepo,
+		consumerLimitRepo,
+	)
+
+	limitHoldUsecase := usecase.NewLimitHoldUsecase(
+		db,
+		limitHoldRepo,
+		consumerRepo,
+		consumerLimitRepo,
+		transactionRepo,
+		productRepo,
+		limitPolicies,
+		cfg.LimitHoldTTL,
+	)
+
+	limitImportUsecase := usecase.NewLimitImportUsecase(
+		db,
+		consumerRepo,
+		consumerLimitRepo,
+		service.NewLimitImportParser(cfg.LimitTenors),
+		cfg.LimitImportBatchSize,
 	)
-	consumerLimitUsecase := usecase.NewConsumerLimitUsecase(consumerRepo, consumerLimitRepo)
+
+	productUsecase := usecase.NewProductUsecase(productRepo)
+	rateCardUsecase := usecase.NewRateCardUsecase(db, rateCardRepo, productRepo)
+	paymentUsecase := usecase.NewPaymentUsecase(db, transactionRepo, installmentRepo, paymentRepo, transitionRepo, paymentAllocator)
+	contractStatusUsecase := usecase.NewContractStatusUsecase(db, transactionRepo, transitionRepo)
+	earlySettlementUsecase := usecase.NewEarlySettlementUsecase(
+		db,
+		consumerRepo,
+		transactionRepo,
+		installmentRepo,
+		paymentRepo,
+		earlySettlementRepo,
+		transitionRepo,
+		earlySettlementCalculator,
+	)
+	contractCancellationUsecase := usecase.NewContractCancellationUsecase(
+		db,
+		consumerRepo,
+		transactionRepo,
+		installmentRepo,
+		contractCancellationRepo,
+		transitionRepo,
+		merchantNotificationRepo,
+		cfg.CoolingOffPeriod,
+	)
+	merchantUsecase := usecase.NewMerchantUsecase(merchantRepo, merchantOutletRepo)
+	penaltyUsecase := usecase.NewPenaltyUsecase(db, transactionRepo, installmentRepo, productRepo, penaltyChargeRepo, penaltyCalculator)
 
 	log.Println("initializing middleware...")
 	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
@@ -93,6 +216,18 @@ func main() {
 	consumerHandler := handler.NewConsumerHandler(consumerUsecase)
 	transactionHandler := handler.NewTransactionHandler(transactionUsecase)
 	consumerLimitHandler := handler.NewConsumerLimitHandler(consumerLimitUsecase)
+	staffHandler := handler.NewStaffHandler(staffUsecase)
+	limitChangeRequestHandler := handler.NewLimitChangeRequestHandler(limitChangeRequestUsecase)
+	limitHoldHandler := handler.NewLimitHoldHandler(limitHoldUsecase)
+	limitImportHandler := handler.NewLimitImportHandler(limitImportUsecase)
+	productHandler := handler.NewProductHandler(productUsecase)
+	rateCardHandler := handler.NewRateCardHandler(rateCardUsecase)
+	paymentHandler := handler.NewPaymentHandler(paymentUsecase)
+	contractStatusHandler := handler.NewContractStatusHandler(contractStatusUsecase)
+	earlySettlementHandler := handler.NewEarlySettlementHandler(earlySettlementUsecase)
+	penaltyHandler := handler.NewPenaltyHandler(penaltyUsecase)
+	contractCancellationHandler := handler.NewContractCancellationHandler(contractCancellationUsecase)
+	merchantHandler := handler.NewMerchantHandler(merchantUsecase)
 
 	log.Println("setting up router...")
 	routerEngine := router.SetupRouter(
@@ -100,6 +235,18 @@ func main() {
 		consumerHandler,
 		transactionHandler,
 		consumerLimitHandler,
+		staffHandler,
+		limitChangeRequestHandler,
+		limitHoldHandler,
+		limitImportHandler,
+		productHandler,
+		rateCardHandler,
+		paymentHandler,
+		contractStatusHandler,
+		earlySettlementHandler,
+		penaltyHandler,
+		contractCancellationHandler,
+		merchantHandler,
 	)
 
 	log.Println("setting up HTTP server...")
@@ -111,6 +258,12 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	log.Println("starting background workers...")
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	go worker.NewLimitHoldSweeper(limitHoldUsecase, cfg.LimitHoldSweepInterval).Run(workerCtx)
+	go worker.NewPenaltyAccrualJob(penaltyUsecase, cfg.PenaltyAccrualInterval).Run(workerCtx)
+
 	log.Printf("starting server on port %s...", cfg.AppPort)
 
 	go func() {
@@ -124,6 +277,7 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Println("shutdown signal received, initiating graceful shutdown...")
+	stopWorkers()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/glennprays/xyz-fin/config"
+	"github.com/glennprays/xyz-fin/internal/app/database"
+	"github.com/glennprays/xyz-fin/internal/app/repository"
+	"github.com/glennprays/xyz-fin/internal/app/service"
+	"github.com/glennprays/xyz-fin/internal/app/usecase"
+)
+
+func main() {
+	filePath := flag.String("file", "", "path to the limit CSV file (nik,tenor,limit_amount)")
+	outPath := flag.String("out", "", "path of the per-row result file (default <file>.result.csv)")
+	apply := flag.Bool("apply", false, "apply the valid rows; without it the file is only validated")
+	operator := flag.String("operator", "cli", "operator name recorded in the limit version source reference")
+	batchSize := flag.Int("batch-size", 0, "rows per transaction (default LIMIT_IMPORT_BATCH_SIZE)")
+	flag.Parse()
+
+	if *filePath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *outPath == "" {
+		*outPath = *filePath + ".result.csv"
+	}
+
+	cfg := config.LoadConfig()
+	if *batchSize <= 0 {
+		*batchSize = cfg.LimitImportBatchSize
+	}
+
+	db, err := database.NewConnection(database.Config{
+		DSN:             cfg.DatabaseDSN(),
+		MaxIdleConns:    2,
+		MaxOpenConns:    2,
+		ConnMaxLifetime: time.Hour,
+	})
+	if err != nil {
+		log.Fatalf("Failed to connect to the database: %v", err)
+	}
+	defer db.Close()
+
+	limitImportUsecase := usecase.NewLimitImportUsecase(
+		db,
+		repository.NewConsumerRepository(db),
+		repository.NewConsumerLimitRepository(db),
+		service.NewLimitImportParser(cfg.LimitTenors),
+		*batchSize,
+	)
+
+	in, err := os.Open(*filePath)
+	if err != nil {
+		log.Fatalf("Failed to open limit file: %v", err)
+	}
+	defer in.Close()
+
+	report, err := limitImportUsecase.Import(context.Background(), *operator, in, !*apply)
+	if err != nil {
+		log.Fatalf("Limit import failed: %v", err)
+	}
+
+	out, err := os.Create(*outPath)
+	if err != nil {
+		log.Fatalf("Failed to create result file: %v", err)
+	}
+	defer out.Close()
+
+	if err := limitImportUsecase.WriteResult(out, report); err != nil {
+		log.Fatalf("Failed to write result file: %v", err)
+	}
+
+	mode := "dry run"
+	if !report.DryRun {
+		mode = "applied as " + report.Reference
+	}
+	fmt.Printf("%s: %d rows, %d valid, %d invalid, %d applied, %d failed; results in %s\n",
+		mode, report.TotalRows, report.ValidRows, report.InvalidRows, report.AppliedRows, report.FailedRows, *outPath)
+
+	if report.FailedRows > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/glennprays/xyz-fin/config"
+	"github.com/glennprays/xyz-fin/internal/app/database"
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/internal/app/repository"
+	"github.com/glennprays/xyz-fin/internal/app/usecase"
+	"github.com/glennprays/xyz-fin/pkg/hasher"
+)
+
+// staff-create provisions a staff account. The password is read from the
+// first line of standard input so it never shows up in the shell history or
+// the process list.
+func main() {
+	username := flag.String("username", "", "username of the new staff account")
+	fullName := flag.String("full-name", "", "full name of the staff member")
+	role := flag.String("role", "", "admin, credit_analyst or merchant")
+	merchantCode := flag.String("merchant", "", "code of the merchant, for merchant staff only")
+	flag.Parse()
+
+	if *username == "" || *fullName == "" || *role == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	fmt.Fprint(os.Stderr, "Password: ")
+	password, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && password == "" {
+		log.Fatalf("Failed to read password: %v", err)
+	}
+	password = strings.TrimRight(password, "\r\n")
+
+	cfg := config.LoadConfig()
+
+	db, err := database.NewConnection(database.Config{
+		DSN:             cfg.DatabaseDSN(),
+		MaxIdleConns:    1,
+		MaxOpenConns:    1,
+		ConnMaxLifetime: time.Hour,
+	})
+	if err != nil {
+		log.Fatalf("Failed to connect to the database: %v", err)
+	}
+	defer db.Close()
+
+	staffUsecase := usecase.NewStaffUsecase(
+		repository.NewStaffRepository(db),
+		repository.NewMerchantRepository(db),
+		nil,
+		*hasher.NewArgon2IDHasher(),
+	)
+
+	staff, err := staffUsecase.Create(context.Background(), &model.CreateStaffRequest{
+		Username:     *username,
+		Password:     password,
+		FullName:     *fullName,
+		Role:         *role,
+		MerchantCode: *merchantCode,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create staff: %v", err)
+	}
+
+	fmt.Printf("created %s staff %s\n", staff.Role, staff.Username)
+}
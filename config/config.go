@@ -1,11 +1,14 @@
 package config
 
 import (
+	"fmt"
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/glennprays/xyz-fin/pkg/money"
 	"github.com/joho/godotenv"
 )
 
@@ -21,6 +24,30 @@ type Config struct {
 	JWTRefreshSecret               string
 	JWTAccessTokenDurationMinutes  time.Duration
 	JWTRefreshTokenDurationMinutes time.Duration
+	LimitTenors                    []int
+	LimitMinAge                    int
+	LimitMaxAgeAtMaturity          int
+	LimitMinIncome                 money.Amount
+	LimitMaxInstallmentRatio       money.Rate
+	LimitInterestLoadRate          money.Rate
+	LimitIncomeMultiplierCap       money.Rate
+	LimitRoundingUnit              money.Amount
+	LimitPolicyDefault             string
+	LimitPolicyByProduct           map[string]string
+	LimitHoldTTL                   time.Duration
+	LimitHoldSweepInterval         time.Duration
+	LimitImportBatchSize           int
+	QuoteSecret                    string
+	QuoteTTL                       time.Duration
+	PaymentAllocationOrder         []string
+	EarlySettlementFeeRate         money.Rate
+	EarlySettlementMinFee          money.Amount
+	EarlySettlementRebateRule      string
+	PenaltyMaxDailyRate            money.Rate
+	PenaltyMaxCapRate              money.Rate
+	PenaltyAccrualInterval         time.Duration
+	ContractBranchCode             string
+	CoolingOffPeriod               time.Duration
 }
 
 func LoadConfig() *Config {
@@ -58,12 +85,119 @@ func LoadConfig() *Config {
 		JWTRefreshSecret:               getEnv("JWT_REFRESH_SECRET", "supersecret"),
 		JWTAccessTokenDurationMinutes:  jwtAccessTokenDurationTime,
 		JWTRefreshTokenDurationMinutes: jwtRefreshTokenDurationTime,
+		LimitTenors:                    getEnvIntList("LIMIT_TENORS", "1,2,3,6"),
+		LimitMinAge:                    getEnvInt("LIMIT_MIN_AGE", "21"),
+		LimitMaxAgeAtMaturity:          getEnvInt("LIMIT_MAX_AGE_AT_MATURITY", "60"),
+		LimitMinIncome:                 getEnvAmount("LIMIT_MIN_INCOME", "3000000"),
+		LimitMaxInstallmentRatio:       getEnvRate("LIMIT_MAX_INSTALLMENT_RATIO", "0.3"),
+		LimitInterestLoadRate:          getEnvRate("LIMIT_INTEREST_LOAD_RATE", "0.05"),
+		LimitIncomeMultiplierCap:       getEnvRate("LIMIT_INCOME_MULTIPLIER_CAP", "3"),
+		LimitRoundingUnit:              getEnvAmount("LIMIT_ROUNDING_UNIT", "50000"),
+		LimitPolicyDefault:             getEnv("LIMIT_POLICY_DEFAULT", "shared"),
+		LimitPolicyByProduct:           getEnvMap("LIMIT_POLICY_BY_PRODUCT", ""),
+		LimitHoldTTL:                   time.Duration(getEnvInt("LIMIT_HOLD_TTL_MINUTES", "30")) * time.Minute,
+		LimitHoldSweepInterval:         time.Duration(getEnvInt("LIMIT_HOLD_SWEEP_INTERVAL_SECONDS", "60")) * time.Second,
+		LimitImportBatchSize:           getEnvInt("LIMIT_IMPORT_BATCH_SIZE", "500"),
+		QuoteSecret:                    getEnvRequired("QUOTE_SECRET"),
+		QuoteTTL:                       time.Duration(getEnvInt("QUOTE_TTL_MINUTES", "15")) * time.Minute,
+		PaymentAllocationOrder:         getEnvList("PAYMENT_ALLOCATION_ORDER", "PENALTY,ADMIN_FEE,INTEREST,PRINCIPAL"),
+		EarlySettlementFeeRate:         getEnvRate("EARLY_SETTLEMENT_FEE_RATE", "0.02"),
+		EarlySettlementMinFee:          getEnvAmount("EARLY_SETTLEMENT_MIN_FEE", "50000"),
+		EarlySettlementRebateRule:      getEnv("EARLY_SETTLEMENT_REBATE_RULE", "full"),
+		PenaltyMaxDailyRate:            getEnvRate("PENALTY_MAX_DAILY_RATE", "0.002"),
+		PenaltyMaxCapRate:              getEnvRate("PENALTY_MAX_CAP_RATE", "0.2"),
+		PenaltyAccrualInterval:         time.Duration(getEnvInt("PENALTY_ACCRUAL_INTERVAL_MINUTES", "60")) * time.Minute,
+		ContractBranchCode:             getEnv("CONTRACT_BRANCH_CODE", "001"),
+		CoolingOffPeriod:               time.Duration(getEnvInt("COOLING_OFF_PERIOD_HOURS", "48")) * time.Hour,
 	}
 }
 
+// DatabaseDSN builds the Postgres connection string shared by the API and the
+// command line tools.
+func (c *Config) DatabaseDSN() string {
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable TimeZone=Asia/Jakarta",
+		c.DBHost,
+		c.DBPort,
+		c.DBUser,
+		c.DBPassword,
+		c.DBName,
+	)
+}
+
 func getEnv(key, fallback string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return fallback
 }
+
+// getEnvRequired is for secrets, which must not fall back to a value that is
+// published with the code.
+func getEnvRequired(key string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		log.Fatalf("%s must be set", key)
+	}
+	return value
+}
+
+func getEnvInt(key, fallback string) int {
+	value, err := strconv.Atoi(getEnv(key, fallback))
+	if err != nil {
+		log.Fatalf("invalid %s: %v", key, err)
+	}
+	return value
+}
+
+func getEnvAmount(key, fallback string) money.Amount {
+	value, err := money.Parse(getEnv(key, fallback))
+	if err != nil {
+		log.Fatalf("invalid %s: %v", key, err)
+	}
+	return value
+}
+
+func getEnvRate(key, fallback string) money.Rate {
+	value, err := money.ParseRate(getEnv(key, fallback))
+	if err != nil {
+		log.Fatalf("invalid %s: %v", key, err)
+	}
+	return value
+}
+
+func getEnvIntList(key, fallback string) []int {
+	var values []int
+	for _, part := range strings.Split(getEnv(key, fallback), ",") {
+		value, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			log.Fatalf("invalid %s: %v", key, err)
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+func getEnvList(key, fallback string) []string {
+	var values []string
+	for _, part := range strings.Split(getEnv(key, fallback), ",") {
+		values = append(values, strings.TrimSpace(part))
+	}
+	return values
+}
+
+// getEnvMap parses a comma separated list of key:value pairs.
+func getEnvMap(key, fallback string) map[string]string {
+	values := make(map[string]string)
+	raw := getEnv(key, fallback)
+	if raw == "" {
+		return values
+	}
+	for _, part := range strings.Split(raw, ",") {
+		pair := strings.SplitN(strings.TrimSpace(part), ":", 2)
+		if len(pair) != 2 || pair[0] == "" || pair[1] == "" {
+			log.Fatalf("invalid %s: expected key:value pairs, got %q", key, part)
+		}
+		values[pair[0]] = pair[1]
+	}
+	return values
+}
@@ -77,3 +77,28 @@ func (h *ConsumerHandler) GetByNIK(c *gin.Context) {
 
 	c.JSON(http.StatusOK, consumer)
 }
+
+func (h *ConsumerHandler) Search(c *gin.Context) {
+	var req model.ConsumerSearchRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		appErr := model.NewError(model.ErrBadRequest, err)
+		apiErr := httperror.FromError(appErr)
+		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message, Details: err.Error()})
+		return
+	}
+
+	consumers, err := h.consumerUsecase.Search(c.Request.Context(), &req)
+	if err != nil {
+		apiErr := httperror.FromError(err)
+		var details interface{}
+		var modelErr model.Error
+		if errors.As(err, &modelErr) {
+			details = modelErr.AppError().Error()
+		}
+		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message, Details: details})
+		return
+	}
+
+	c.JSON(http.StatusOK, consumers)
+}
@@ -3,6 +3,8 @@ package handler
 import (
 	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/glennprays/xyz-fin/internal/app/httperror"
@@ -37,7 +39,7 @@ func (h *ConsumerLimitHandler) GetLimitsByNIK(c *gin.Context) {
 		return
 	}
 
-	limits, err := h.consumerLimitUsecase.GetLimitsByNIK(c.Request.Context(), phoneNumber, nik)
+	limits, err := h.consumerLimitUsecase.GetLimitsByNIK(c.Request.Context(), phoneNumber, nik, c.Query("product_code"))
 	if err != nil {
 		apiErr := httperror.FromError(err)
 		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message})
@@ -46,3 +48,116 @@ func (h *ConsumerLimitHandler) GetLimitsByNIK(c *gin.Context) {
 
 	c.JSON(http.StatusOK, limits)
 }
+
+func (h *ConsumerLimitHandler) CalculateLimits(c *gin.Context) {
+	nik := c.Param("nik")
+	if nik == "" {
+		appErr := model.NewError(model.ErrBadRequest, errors.New("NIK is required"))
+		apiErr := httperror.FromError(appErr)
+		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message})
+		return
+	}
+
+	dryRun := c.Query("dry_run") == "true"
+
+	principal, err := util.GetPrincipalFromContext(c)
+	if err != nil {
+		apiErr := httperror.FromError(err)
+		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message, Details: err.Error()})
+		return
+	}
+
+	result, err := h.consumerLimitUsecase.CalculateLimits(c.Request.Context(), principal, nik, !dryRun)
+	if err != nil {
+		apiErr := httperror.FromError(err)
+		var details interface{}
+		var modelErr model.Error
+		if errors.As(err, &modelErr) {
+			details = modelErr.AppError().Error()
+		}
+		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message, Details: details})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *ConsumerLimitHandler) GetLimitVersions(c *gin.Context) {
+	nik, tenor, ok := h.parseNIKAndTenor(c)
+	if !ok {
+		return
+	}
+
+	principal, err := util.GetPrincipalFromContext(c)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	versions, err := h.consumerLimitUsecase.GetLimitVersions(c.Request.Context(), principal, nik, tenor)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, versions)
+}
+
+func (h *ConsumerLimitHandler) GetLimitAsOf(c *gin.Context) {
+	nik, tenor, ok := h.parseNIKAndTenor(c)
+	if !ok {
+		return
+	}
+
+	asOf, err := time.Parse(time.RFC3339, c.Query("at"))
+	if err != nil {
+		appErr := model.NewError(model.ErrBadRequest, errors.New("at must be an RFC3339 timestamp"))
+		apiErr := httperror.FromError(appErr)
+		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message})
+		return
+	}
+
+	principal, err := util.GetPrincipalFromContext(c)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	version, err := h.consumerLimitUsecase.GetLimitAsOf(c.Request.Context(), principal, nik, tenor, asOf)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, version)
+}
+
+func (h *ConsumerLimitHandler) GetLimitAtContract(c *gin.Context) {
+	nomorKontrak := c.Param("nomor_kontrak")
+
+	principal, err := util.GetPrincipalFromContext(c)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	result, err := h.consumerLimitUsecase.GetLimitAtContract(c.Request.Context(), principal, nomorKontrak)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *ConsumerLimitHandler) parseNIKAndTenor(c *gin.Context) (string, int, bool) {
+	tenor, err := strconv.Atoi(c.Param("tenor"))
+	if err != nil || tenor <= 0 {
+		appErr := model.NewError(model.ErrBadRequest, errors.New("invalid tenor"))
+		apiErr := httperror.FromError(appErr)
+		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message})
+		return "", 0, false
+	}
+
+	return c.Param("nik"), tenor, true
+}
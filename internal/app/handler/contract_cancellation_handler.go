@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glennprays/xyz-fin/internal/app/httperror"
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/internal/app/usecase"
+	util "github.com/glennprays/xyz-fin/internal/app/utils"
+)
+
+type ContractCancellationHandler struct {
+	contractCancellationUsecase usecase.ContractCancellationUsecase
+}
+
+func NewContractCancellationHandler(contractCancellationUsecase usecase.ContractCancellationUsecase) *ContractCancellationHandler {
+	return &ContractCancellationHandler{
+		contractCancellationUsecase: contractCancellationUsecase,
+	}
+}
+
+func (h *ContractCancellationHandler) Cancel(c *gin.Context) {
+	var req model.ContractCancellationRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := model.NewError(model.ErrBadRequest, err)
+		apiErr := httperror.FromError(appErr)
+		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message, Details: err.Error()})
+		return
+	}
+
+	phoneNumber, err := util.GetUserPhoneNumberFromContext(c)
+	if err != nil {
+		apiErr := httperror.FromError(err)
+		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message, Details: err.Error()})
+		return
+	}
+
+	cancellation, err := h.contractCancellationUsecase.Cancel(c.Request.Context(), phoneNumber, c.Param("nomor_kontrak"), &req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, cancellation)
+}
+
+func (h *ContractCancellationHandler) CancelByStaff(c *gin.Context) {
+	var req model.ContractCancellationRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := model.NewError(model.ErrBadRequest, err)
+		apiErr := httperror.FromError(appErr)
+		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message, Details: err.Error()})
+		return
+	}
+
+	staffUsername, err := util.GetStaffUsernameFromContext(c)
+	if err != nil {
+		apiErr := httperror.FromError(err)
+		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message, Details: err.Error()})
+		return
+	}
+
+	cancellation, err := h.contractCancellationUsecase.CancelByStaff(c.Request.Context(), staffUsername, c.Param("nomor_kontrak"), &req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, cancellation)
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glennprays/xyz-fin/internal/app/httperror"
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/internal/app/usecase"
+	util "github.com/glennprays/xyz-fin/internal/app/utils"
+)
+
+type ContractStatusHandler struct {
+	contractStatusUsecase usecase.ContractStatusUsecase
+}
+
+func NewContractStatusHandler(contractStatusUsecase usecase.ContractStatusUsecase) *ContractStatusHandler {
+	return &ContractStatusHandler{
+		contractStatusUsecase: contractStatusUsecase,
+	}
+}
+
+func (h *ContractStatusHandler) Transition(c *gin.Context) {
+	var req model.TransactionStatusRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := model.NewError(model.ErrBadRequest, err)
+		apiErr := httperror.FromError(appErr)
+		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message, Details: err.Error()})
+		return
+	}
+
+	staffUsername, err := util.GetStaffUsernameFromContext(c)
+	if err != nil {
+		apiErr := httperror.FromError(err)
+		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message, Details: err.Error()})
+		return
+	}
+
+	transition, err := h.contractStatusUsecase.Transition(c.Request.Context(), staffUsername, c.Param("nomor_kontrak"), &req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, transition)
+}
+
+func (h *ContractStatusHandler) List(c *gin.Context) {
+	transitions, err := h.contractStatusUsecase.ListTransitions(c.Request.Context(), c.Param("nomor_kontrak"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transitions)
+}
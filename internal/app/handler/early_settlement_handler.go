@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glennprays/xyz-fin/internal/app/httperror"
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/internal/app/usecase"
+	util "github.com/glennprays/xyz-fin/internal/app/utils"
+)
+
+type EarlySettlementHandler struct {
+	earlySettlementUsecase usecase.EarlySettlementUsecase
+}
+
+func NewEarlySettlementHandler(earlySettlementUsecase usecase.EarlySettlementUsecase) *EarlySettlementHandler {
+	return &EarlySettlementHandler{
+		earlySettlementUsecase: earlySettlementUsecase,
+	}
+}
+
+func (h *EarlySettlementHandler) GetConsumerQuote(c *gin.Context) {
+	phoneNumber, err := util.GetUserPhoneNumberFromContext(c)
+	if err != nil {
+		apiErr := httperror.FromError(err)
+		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message, Details: err.Error()})
+		return
+	}
+
+	quote, err := h.earlySettlementUsecase.QuoteForConsumer(c.Request.Context(), phoneNumber, c.Param("nomor_kontrak"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quote)
+}
+
+func (h *EarlySettlementHandler) GetQuote(c *gin.Context) {
+	quote, err := h.earlySettlementUsecase.Quote(c.Request.Context(), c.Param("nomor_kontrak"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quote)
+}
+
+func (h *EarlySettlementHandler) Execute(c *gin.Context) {
+	var req model.EarlySettlementRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := model.NewError(model.ErrBadRequest, err)
+		apiErr := httperror.FromError(appErr)
+		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message, Details: err.Error()})
+		return
+	}
+
+	staffUsername, err := util.GetStaffUsernameFromContext(c)
+	if err != nil {
+		apiErr := httperror.FromError(err)
+		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message, Details: err.Error()})
+		return
+	}
+
+	settlement, err := h.earlySettlementUsecase.Execute(c.Request.Context(), staffUsername, c.Param("nomor_kontrak"), &req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, settlement)
+}
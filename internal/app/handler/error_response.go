@@ -0,0 +1,19 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glennprays/xyz-fin/internal/app/httperror"
+	"github.com/glennprays/xyz-fin/internal/app/model"
+)
+
+func writeError(c *gin.Context, err error) {
+	apiErr := httperror.FromError(err)
+	var details interface{}
+	var modelErr model.Error
+	if errors.As(err, &modelErr) {
+		details = modelErr.AppError().Error()
+	}
+	c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message, Details: details})
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glennprays/xyz-fin/internal/app/httperror"
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/internal/app/usecase"
+	util "github.com/glennprays/xyz-fin/internal/app/utils"
+)
+
+type LimitChangeRequestHandler struct {
+	limitChangeRequestUsecase usecase.LimitChangeRequestUsecase
+}
+
+func NewLimitChangeRequestHandler(limitChangeRequestUsecase usecase.LimitChangeRequestUsecase) *LimitChangeRequestHandler {
+	return &LimitChangeRequestHandler{
+		limitChangeRequestUsecase: limitChangeRequestUsecase,
+	}
+}
+
+func (h *LimitChangeRequestHandler) Create(c *gin.Context) {
+	var req model.CreateLimitChangeRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := model.NewError(model.ErrBadRequest, err)
+		apiErr := httperror.FromError(appErr)
+		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message, Details: err.Error()})
+		return
+	}
+
+	principal, err := util.GetPrincipalFromContext(c)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	request, err := h.limitChangeRequestUsecase.Create(c.Request.Context(), principal, &req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, request)
+}
+
+func (h *LimitChangeRequestHandler) List(c *gin.Context) {
+	principal, err := util.GetPrincipalFromContext(c)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	requests, err := h.limitChangeRequestUsecase.List(c.Request.Context(), principal, c.Query("status"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, requests)
+}
+
+func (h *LimitChangeRequestHandler) GetByID(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	principal, err := util.GetPrincipalFromContext(c)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	request, err := h.limitChangeRequestUsecase.GetByID(c.Request.Context(), principal, id)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, request)
+}
+
+func (h *LimitChangeRequestHandler) Approve(c *gin.Context) {
+	h.decide(c, h.limitChangeRequestUsecase.Approve)
+}
+
+func (h *LimitChangeRequestHandler) Reject(c *gin.Context) {
+	h.decide(c, h.limitChangeRequestUsecase.Reject)
+}
+
+func (h *LimitChangeRequestHandler) decide(c *gin.Context, decide func(ctx context.Context, principal model.Principal, id int64, note string) (*model.LimitChangeRequest, error)) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	var req model.LimitChangeDecisionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			appErr := model.NewError(model.ErrBadRequest, err)
+			apiErr := httperror.FromError(appErr)
+			c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message, Details: err.Error()})
+			return
+		}
+	}
+
+	principal, err := util.GetPrincipalFromContext(c)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	request, err := decide(c.Request.Context(), principal, id, req.Note)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, request)
+}
+
+func (h *LimitChangeRequestHandler) parseID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		appErr := model.NewError(model.ErrBadRequest, errors.New("invalid limit change request id"))
+		apiErr := httperror.FromError(appErr)
+		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message})
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glennprays/xyz-fin/internal/app/httperror"
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/internal/app/usecase"
+	util "github.com/glennprays/xyz-fin/internal/app/utils"
+)
+
+type LimitHoldHandler struct {
+	limitHoldUsecase usecase.LimitHoldUsecase
+}
+
+func NewLimitHoldHandler(limitHoldUsecase usecase.LimitHoldUsecase) *LimitHoldHandler {
+	return &LimitHoldHandler{
+		limitHoldUsecase: limitHoldUsecase,
+	}
+}
+
+func (h *LimitHoldHandler) Create(c *gin.Context) {
+	var req model.CreateLimitHoldRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := model.NewError(model.ErrBadRequest, err)
+		apiErr := httperror.FromError(appErr)
+		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message, Details: err.Error()})
+		return
+	}
+
+	principal, err := util.GetPrincipalFromContext(c)
+	if err != nil {
+		apiErr := httperror.FromError(err)
+		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message, Details: err.Error()})
+		return
+	}
+
+	hold, err := h.limitHoldUsecase.Create(c.Request.Context(), principal, &req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, hold)
+}
+
+func (h *LimitHoldHandler) GetByID(c *gin.Context) {
+	id, ok := parseHoldID(c)
+	if !ok {
+		return
+	}
+
+	principal, err := util.GetPrincipalFromContext(c)
+	if err != nil {
+		apiErr := httperror.FromError(err)
+		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message, Details: err.Error()})
+		return
+	}
+
+	hold, err := h.limitHoldUsecase.GetByID(c.Request.Context(), principal, id)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, hold)
+}
+
+func (h *LimitHoldHandler) Release(c *gin.Context) {
+	id, ok := parseHoldID(c)
+	if !ok {
+		return
+	}
+
+	principal, err := util.GetPrincipalFromContext(c)
+	if err != nil {
+		apiErr := httperror.FromError(err)
+		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message, Details: err.Error()})
+		return
+	}
+
+	hold, err := h.limitHoldUsecase.Release(c.Request.Context(), principal, id)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, hold)
+}
+
+func parseHoldID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		appErr := model.NewError(model.ErrBadRequest, errors.New("invalid limit hold id"))
+		apiErr := httperror.FromError(appErr)
+		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message})
+		return 0, false
+	}
+	return id, true
+}
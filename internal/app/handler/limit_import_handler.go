@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glennprays/xyz-fin/internal/app/httperror"
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/internal/app/usecase"
+	util "github.com/glennprays/xyz-fin/internal/app/utils"
+)
+
+type LimitImportHandler struct {
+	limitImportUsecase usecase.LimitImportUsecase
+}
+
+func NewLimitImportHandler(limitImportUsecase usecase.LimitImportUsecase) *LimitImportHandler {
+	return &LimitImportHandler{
+		limitImportUsecase: limitImportUsecase,
+	}
+}
+
+// Import validates an uploaded limit file. The file is only applied when
+// dry_run=false is passed explicitly; format=csv returns the per-row result
+// file instead of the JSON report.
+func (h *LimitImportHandler) Import(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		appErr := model.NewError(model.ErrBadRequest, errors.New("file is required"))
+		apiErr := httperror.FromError(appErr)
+		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message, Details: err.Error()})
+		return
+	}
+
+	staffUsername, err := util.GetStaffUsernameFromContext(c)
+	if err != nil {
+		apiErr := httperror.FromError(err)
+		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message, Details: err.Error()})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		appErr := model.NewError(model.ErrBadRequest, err)
+		apiErr := httperror.FromError(appErr)
+		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message, Details: err.Error()})
+		return
+	}
+	defer file.Close()
+
+	dryRun := c.Query("dry_run") != "false"
+
+	report, err := h.limitImportUsecase.Import(c.Request.Context(), staffUsername, file, dryRun)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	if c.Query("format") == "csv" {
+		c.Header("Content-Disposition", `attachment; filename="limit-import-result.csv"`)
+		c.Status(http.StatusOK)
+		c.Writer.Header().Set("Content-Type", "text/csv")
+		if err := h.limitImportUsecase.WriteResult(c.Writer, report); err != nil {
+			c.Error(err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
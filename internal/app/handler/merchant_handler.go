@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glennprays/xyz-fin/internal/app/httperror"
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/internal/app/usecase"
+)
+
+type MerchantHandler struct {
+	merchantUsecase usecase.MerchantUsecase
+}
+
+func NewMerchantHandler(merchantUsecase usecase.MerchantUsecase) *MerchantHandler {
+	return &MerchantHandler{
+		merchantUsecase: merchantUsecase,
+	}
+}
+
+func (h *MerchantHandler) Create(c *gin.Context) {
+	var req model.CreateMerchantRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := model.NewError(model.ErrBadRequest, err)
+		apiErr := httperror.FromError(appErr)
+		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message, Details: err.Error()})
+		return
+	}
+
+	merchant, err := h.merchantUsecase.Create(c.Request.Context(), &req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, merchant)
+}
+
+func (h *MerchantHandler) List(c *gin.Context) {
+	var filter model.MerchantFilter
+
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		appErr := model.NewError(model.ErrBadRequest, err)
+		apiErr := httperror.FromError(appErr)
+		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message, Details: err.Error()})
+		return
+	}
+
+	merchants, err := h.merchantUsecase.List(c.Request.Context(), filter)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, merchants)
+}
+
+func (h *MerchantHandler) GetByID(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "invalid merchant id")
+	if !ok {
+		return
+	}
+
+	merchant, err := h.merchantUsecase.GetByID(c.Request.Context(), id)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, merchant)
+}
+
+func (h *MerchantHandler) UpdateStatus(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "invalid merchant id")
+	if !ok {
+		return
+	}
+
+	var req model.MerchantStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := model.NewError(model.ErrBadRequest, err)
+		apiErr := httperror.FromError(appErr)
+		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message, Details: err.Error()})
+		return
+	}
+
+	merchant, err := h.merchantUsecase.UpdateStatus(c.Request.Context(), id, &req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, merchant)
+}
+
+func (h *MerchantHandler) CreateOutlet(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "invalid merchant id")
+	if !ok {
+		return
+	}
+
+	var req model.CreateMerchantOutletRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := model.NewError(model.ErrBadRequest, err)
+		apiErr := httperror.FromError(appErr)
+		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message, Details: err.Error()})
+		return
+	}
+
+	outlet, err := h.merchantUsecase.CreateOutlet(c.Request.Context(), id, &req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, outlet)
+}
+
+func (h *MerchantHandler) UpdateOutletStatus(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "invalid merchant id")
+	if !ok {
+		return
+	}
+	outletID, ok := parseIDParam(c, "outlet_id", "invalid outlet id")
+	if !ok {
+		return
+	}
+
+	var req model.MerchantStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := model.NewError(model.ErrBadRequest, err)
+		apiErr := httperror.FromError(appErr)
+		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message, Details: err.Error()})
+		return
+	}
+
+	outlet, err := h.merchantUsecase.UpdateOutletStatus(c.Request.Context(), id, outletID, &req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, outlet)
+}
+
+// parseIDParam reads a positive numeric path parameter, writing a 400 and
+// returning false when it is not one.
+func parseIDParam(c *gin.Context, name string, message string) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param(name), 10, 64)
+	if err != nil || id <= 0 {
+		appErr := model.NewError(model.ErrBadRequest, errors.New(message))
+		apiErr := httperror.FromError(appErr)
+		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message})
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glennprays/xyz-fin/internal/app/usecase"
+	util "github.com/glennprays/xyz-fin/internal/app/utils"
+)
+
+type PenaltyHandler struct {
+	penaltyUsecase usecase.PenaltyUsecase
+}
+
+func NewPenaltyHandler(penaltyUsecase usecase.PenaltyUsecase) *PenaltyHandler {
+	return &PenaltyHandler{
+		penaltyUsecase: penaltyUsecase,
+	}
+}
+
+func (h *PenaltyHandler) List(c *gin.Context) {
+	principal, err := util.GetPrincipalFromContext(c)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	charges, err := h.penaltyUsecase.ListByNomorKontrak(c.Request.Context(), principal, c.Param("nomor_kontrak"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, charges)
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glennprays/xyz-fin/internal/app/usecase"
+)
+
+type ProductHandler struct {
+	productUsecase usecase.ProductUsecase
+}
+
+func NewProductHandler(productUsecase usecase.ProductUsecase) *ProductHandler {
+	return &ProductHandler{
+		productUsecase: productUsecase,
+	}
+}
+
+func (h *ProductHandler) List(c *gin.Context) {
+	products, err := h.productUsecase.List(c.Request.Context())
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, products)
+}
+
+func (h *ProductHandler) GetByCode(c *gin.Context) {
+	product, err := h.productUsecase.GetByCode(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, product)
+}
@@ -1,11 +1,9 @@
 package handler
 
 import (
-	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/glennprays/xyz-fin/internal/app/httperror"
 	"github.com/glennprays/xyz-fin/internal/app/model"
 	"github.com/glennprays/xyz-fin/internal/app/usecase"
 	util "github.com/glennprays/xyz-fin/internal/app/utils"
@@ -25,30 +23,149 @@ func (h *TransactionHandler) CreateTransaction(c *gin.Context) {
 	var req model.TransactionRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		appErr := model.NewError(model.ErrBadRequest, err)
-		apiErr := httperror.FromError(appErr)
-		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message, Details: err.Error()})
+		writeError(c, model.NewError(model.ErrBadRequest, err))
 		return
 	}
 
 	phoneNumber, err := util.GetUserPhoneNumberFromContext(c)
 	if err != nil {
-		apiErr := httperror.FromError(err)
-		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message, Details: err.Error()})
+		writeError(c, err)
 		return
 	}
 
-	transaction, err := h.transactionUsecase.CreateTransaction(c.Request.Context(), phoneNumber, &req)
+	transaction, err := h.transactionUsecase.CreateTransaction(c.Request.Context(), phoneNumber, c.GetHeader("Idempotency-Key"), &req)
 	if err != nil {
-		apiErr := httperror.FromError(err)
-		var details interface{}
-		var modelErr model.Error
-		if errors.As(err, &modelErr) {
-			details = modelErr.AppError().Error()
-		}
-		c.JSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message, Details: details})
+		writeError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, transaction)
+	c.JSON(http.StatusCreated, transaction)
+}
+
+func (h *TransactionHandler) CaptureHold(c *gin.Context) {
+	holdID, ok := parseHoldID(c)
+	if !ok {
+		return
+	}
+
+	var req model.CaptureLimitHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, model.NewError(model.ErrBadRequest, err))
+		return
+	}
+
+	principal, err := util.GetPrincipalFromContext(c)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	transaction, err := h.transactionUsecase.CaptureHold(c.Request.Context(), principal, holdID, &req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, transaction)
+}
+
+func (h *TransactionHandler) GetSchedule(c *gin.Context) {
+	phoneNumber, err := util.GetUserPhoneNumberFromContext(c)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	schedule, err := h.transactionUsecase.GetSchedule(c.Request.Context(), phoneNumber, c.Param("nomor_kontrak"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+func (h *TransactionHandler) GetDetail(c *gin.Context) {
+	principal, err := util.GetPrincipalFromContext(c)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	detail, err := h.transactionUsecase.GetDetail(c.Request.Context(), principal, c.Param("nomor_kontrak"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
+
+func (h *TransactionHandler) ListMine(c *gin.Context) {
+	var req model.TransactionHistoryRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		writeError(c, model.NewError(model.ErrBadRequest, err))
+		return
+	}
+
+	phoneNumber, err := util.GetUserPhoneNumberFromContext(c)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	transactions, err := h.transactionUsecase.ListForConsumer(c.Request.Context(), phoneNumber, &req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transactions)
+}
+
+func (h *TransactionHandler) ListForMerchant(c *gin.Context) {
+	var req model.TransactionHistoryRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		writeError(c, model.NewError(model.ErrBadRequest, err))
+		return
+	}
+
+	principal, err := util.GetPrincipalFromContext(c)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	transactions, err := h.transactionUsecase.ListForMerchant(c.Request.Context(), principal, &req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transactions)
+}
+
+func (h *TransactionHandler) Simulate(c *gin.Context) {
+	var req model.QuoteRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, model.NewError(model.ErrBadRequest, err))
+		return
+	}
+
+	phoneNumber, err := util.GetUserPhoneNumberFromContext(c)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	quote, err := h.transactionUsecase.Simulate(c.Request.Context(), phoneNumber, &req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quote)
 }
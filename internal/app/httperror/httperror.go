@@ -32,6 +32,8 @@ func FromError(err error) APIError {
 			apiError.Status = http.StatusForbidden
 		case model.ErrConflict:
 			apiError.Status = http.StatusConflict
+		case model.ErrUnprocessable:
+			apiError.Status = http.StatusUnprocessableEntity
 		}
 	}
 
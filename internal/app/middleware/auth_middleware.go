@@ -12,6 +12,9 @@ import (
 
 const (
 	ContextUserPhoneNumber  = "phoneNumber"
+	ContextUserRole         = "role"
+	ContextStaffUsername    = "staffUsername"
+	ContextMerchantID       = "merchantID"
 	AuthorizationHeaderKey  = "Authorization"
 	AuthorizationTypeBearer = "Bearer"
 )
@@ -57,12 +60,37 @@ func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 			return
 		}
 
-		c.Set(ContextUserPhoneNumber, claims.PhoneNumber)
+		if claims.PhoneNumber != "" {
+			c.Set(ContextUserPhoneNumber, claims.PhoneNumber)
+		}
+		if claims.StaffUsername != "" {
+			c.Set(ContextStaffUsername, claims.StaffUsername)
+		}
+		if claims.MerchantID != 0 {
+			c.Set(ContextMerchantID, claims.MerchantID)
+		}
+		c.Set(ContextUserRole, claims.Role)
 
 		c.Next()
 	}
 }
 
+func (m *AuthMiddleware) RequireRoles(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetString(ContextUserRole)
+		for _, allowed := range roles {
+			if role == allowed {
+				c.Next()
+				return
+			}
+		}
+
+		appErr := model.NewError(model.ErrForbidden, errors.New("role is not allowed to access this resource"))
+		apiErr := httperror.FromError(appErr)
+		c.AbortWithStatusJSON(apiErr.Status, model.ErrorResponse{Message: apiErr.Message})
+	}
+}
+
 func (m *AuthMiddleware) extractBearerToken(c *gin.Context) (string, error) {
 	authHeader := c.GetHeader(AuthorizationHeaderKey)
 	if authHeader == "" {
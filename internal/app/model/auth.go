@@ -5,7 +5,28 @@ type LoginRequest struct {
 	Password    string `json:"password"`
 }
 
+type StaffLoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
 type AuthResponse struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
 }
+
+// Principal is the authenticated caller of a request: a consumer identified
+// by PhoneNumber or a staff member identified by StaffUsername. ConsumerNIK
+// is filled in once the consumer has been looked up; MerchantID is set for
+// the staff of a merchant.
+type Principal struct {
+	PhoneNumber   string
+	ConsumerNIK   string
+	StaffUsername string
+	Role          string
+	MerchantID    int64
+}
+
+func (p Principal) IsStaff() bool {
+	return p.StaffUsername != ""
+}
@@ -1,16 +1,87 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"github.com/glennprays/xyz-fin/pkg/money"
+)
+
+const (
+	LimitSourceEngine    = "ENGINE"
+	LimitSourceManual    = "MANUAL"
+	LimitSourcePromotion = "PROMOTION"
+	LimitSourceImport    = "IMPORT"
+)
 
 type ConsumerLimit struct {
-	ConsumerNIK string    `json:"consumer_nik"`
-	Tenor       int       `json:"tenor"`
-	LimitAmount float64   `json:"limit_amount"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ConsumerNIK string       `json:"consumer_nik"`
+	Tenor       int          `json:"tenor"`
+	LimitAmount money.Amount `json:"limit_amount"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
 }
 
 type ConsumerLimitResponse struct {
-	LimitAmount float64 `json:"limit_amount"`
-	Tenor       int     `json:"tenor"`
+	LimitAmount     money.Amount `json:"limit_amount"`
+	Tenor           int          `json:"tenor"`
+	UsedAmount      money.Amount `json:"used_amount"`
+	PendingAmount   money.Amount `json:"pending_amount"`
+	HeldAmount      money.Amount `json:"held_amount"`
+	AvailableAmount money.Amount `json:"available_amount"`
+}
+
+// TenorUsage is the contract principal and held amount outstanding against a
+// consumer's limits, grouped by tenor.
+type TenorUsage struct {
+	Tenor   int
+	Used    money.Amount
+	Pending money.Amount
+	Held    money.Amount
+}
+
+type LimitUtilization struct {
+	Tenor     int
+	Limit     money.Amount
+	Used      money.Amount
+	Pending   money.Amount
+	Held      money.Amount
+	Available money.Amount
+}
+
+type LimitFactor struct {
+	Name        string `json:"name"`
+	Value       string `json:"value"`
+	Description string `json:"description"`
+	Rejected    bool   `json:"rejected"`
+}
+
+type LimitCalculation struct {
+	ConsumerNIK string        `json:"consumer_nik"`
+	Tenor       int           `json:"tenor"`
+	LimitAmount money.Amount  `json:"limit_amount"`
+	Factors     []LimitFactor `json:"factors"`
+}
+
+type LimitCalculationResponse struct {
+	ConsumerNIK string             `json:"consumer_nik"`
+	Applied     bool               `json:"applied"`
+	Limits      []LimitCalculation `json:"limits"`
+}
+
+type ConsumerLimitVersion struct {
+	ID              int64        `json:"id"`
+	ConsumerNIK     string       `json:"consumer_nik"`
+	Tenor           int          `json:"tenor"`
+	LimitAmount     money.Amount `json:"limit_amount"`
+	Source          string       `json:"source"`
+	SourceReference *string      `json:"source_reference"`
+	ValidFrom       time.Time    `json:"valid_from"`
+	ValidTo         *time.Time   `json:"valid_to"`
+	CreatedAt       time.Time    `json:"created_at"`
+}
+
+type ContractLimitResponse struct {
+	NomorKontrak      string                `json:"nomor_kontrak"`
+	ContractCreatedAt time.Time             `json:"contract_created_at"`
+	Limit             *ConsumerLimitVersion `json:"limit"`
 }
@@ -1,20 +1,49 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"github.com/glennprays/xyz-fin/pkg/money"
+	"github.com/glennprays/xyz-fin/pkg/pagination"
+)
+
+const (
+	KYCStatusPending  = "PENDING"
+	KYCStatusVerified = "VERIFIED"
+	KYCStatusRejected = "REJECTED"
+)
+
+const (
+	RiskGradeA = "A"
+	RiskGradeB = "B"
+	RiskGradeC = "C"
+)
+
+const (
+	ConsumerSortByCreatedAt = "created_at"
+	ConsumerSortByFullName  = "full_name"
+)
+
+const (
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
 
 type Consumer struct {
-	NIK            string    `json:"nik"`
-	PhoneNumber    string    `json:"phone_number"`
-	PasswordHash   string    `json:"-"`
-	FullName       string    `json:"full_name"`
-	LegalName      string    `json:"legal_name"`
-	TempatLahir    string    `json:"tempat_lahir"`
-	TanggalLahir   time.Time `json:"tanggal_lahir"`
-	Gaji           float64   `json:"gaji"`
-	FotoKTPPath    string    `json:"foto_ktp_path"`
-	FotoSelfiePath string    `json:"foto_selfie_path"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	NIK            string       `json:"nik"`
+	PhoneNumber    string       `json:"phone_number"`
+	PasswordHash   string       `json:"-"`
+	FullName       string       `json:"full_name"`
+	LegalName      string       `json:"legal_name"`
+	TempatLahir    string       `json:"tempat_lahir"`
+	TanggalLahir   time.Time    `json:"tanggal_lahir"`
+	Gaji           money.Amount `json:"gaji"`
+	FotoKTPPath    string       `json:"foto_ktp_path"`
+	FotoSelfiePath string       `json:"foto_selfie_path"`
+	KYCStatus      string       `json:"kyc_status"`
+	RiskGrade      string       `json:"risk_grade"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
 }
 
 type ConsumerResponse struct {
@@ -27,3 +56,45 @@ type ConsumerResponse struct {
 	Gaji         string    `json:"gaji"`
 	CreatedAt    time.Time `json:"created_at"`
 }
+
+type ConsumerSearchRequest struct {
+	Name              string     `form:"name"`
+	PhoneNumber       string     `form:"phone_number"`
+	NIK               string     `form:"nik"`
+	KYCStatus         string     `form:"kyc_status"`
+	CreatedFrom       *time.Time `form:"created_from" time_format:"2006-01-02"`
+	CreatedTo         *time.Time `form:"created_to" time_format:"2006-01-02"`
+	HasActiveContract *bool      `form:"has_active_contract"`
+	SortBy            string     `form:"sort_by"`
+	SortOrder         string     `form:"sort_order"`
+	Limit             int        `form:"limit"`
+	Cursor            string     `form:"cursor"`
+}
+
+type ConsumerSearchFilter struct {
+	NamePrefix        string
+	PhoneNumber       string
+	NIK               string
+	KYCStatus         string
+	CreatedFrom       *time.Time
+	CreatedTo         *time.Time
+	HasActiveContract *bool
+	SortBy            string
+	SortOrder         string
+	Limit             int
+	Cursor            *pagination.Cursor
+}
+
+type ConsumerSummary struct {
+	NIK               string    `json:"nik"`
+	PhoneNumber       string    `json:"phone_number"`
+	FullName          string    `json:"full_name"`
+	KYCStatus         string    `json:"kyc_status"`
+	HasActiveContract bool      `json:"has_active_contract"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+type ConsumerSearchResponse struct {
+	Data       []ConsumerSummary `json:"data"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/glennprays/xyz-fin/pkg/money"
+)
+
+// ContractCancellation records a contract cancelled by its consumer within
+// the cooling-off period. The admin fee and interest of the contract are no
+// longer owed and the financed amount is back on the consumer's limit.
+type ContractCancellation struct {
+	NomorKontrak     string       `json:"nomor_kontrak"`
+	Reason           string       `json:"reason"`
+	ReversedAdminFee money.Amount `json:"reversed_admin_fee"`
+	ReversedInterest money.Amount `json:"reversed_interest"`
+	ReleasedLimit    money.Amount `json:"released_limit"`
+	CancelledBy      string       `json:"cancelled_by"`
+	CancelledAt      time.Time    `json:"cancelled_at"`
+}
+
+type ContractCancellationRequest struct {
+	Reason string `json:"reason"`
+}
+
+// Merchant notification events.
+const (
+	MerchantEventContractCancelled = "CONTRACT_CANCELLED"
+)
+
+// MerchantNotification is an event about a contract waiting to be delivered
+// to the merchant that originated it. It is written in the same database
+// transaction as the change it reports.
+type MerchantNotification struct {
+	ID           int64           `json:"id"`
+	NomorKontrak string          `json:"nomor_kontrak"`
+	MerchantID   *int64          `json:"merchant_id"`
+	Event        string          `json:"event"`
+	Payload      json.RawMessage `json:"payload"`
+	CreatedAt    time.Time       `json:"created_at"`
+	DeliveredAt  *time.Time      `json:"delivered_at"`
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+
+	"github.com/glennprays/xyz-fin/pkg/money"
+)
+
+// EarlySettlementQuote is what it takes to pay off a contract on AsOf.
+// TotalPayoff is the remaining principal, penalty and admin fee, the interest
+// accrued so far and the unearned interest less its rebate, plus the early
+// termination fee.
+type EarlySettlementQuote struct {
+	NomorKontrak        string       `json:"nomor_kontrak"`
+	AsOf                time.Time    `json:"as_of"`
+	RemainingPrincipal  money.Amount `json:"remaining_principal"`
+	OutstandingPenalty  money.Amount `json:"outstanding_penalty"`
+	OutstandingAdminFee money.Amount `json:"outstanding_admin_fee"`
+	AccruedInterest     money.Amount `json:"accrued_interest"`
+	UnearnedInterest    money.Amount `json:"unearned_interest"`
+	InterestRebate      money.Amount `json:"interest_rebate"`
+	EarlyTerminationFee money.Amount `json:"early_termination_fee"`
+	TotalPayoff         money.Amount `json:"total_payoff"`
+}
+
+type EarlySettlement struct {
+	EarlySettlementQuote
+	Reference string    `json:"reference"`
+	SettledBy string    `json:"settled_by"`
+	SettledAt time.Time `json:"settled_at"`
+}
+
+type EarlySettlementRequest struct {
+	Reference string       `json:"reference"`
+	Amount    money.Amount `json:"amount"`
+}
@@ -9,6 +9,7 @@ var (
 	ErrUnauthorized    = errors.New("unauthorized")
 	ErrForbidden       = errors.New("forbidden")
 	ErrConflict        = errors.New("conflict")
+	ErrUnprocessable   = errors.New("unprocessable entity")
 )
 
 type Error struct {
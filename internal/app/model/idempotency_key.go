@@ -0,0 +1,17 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// IdempotencyKey remembers a request made with an Idempotency-Key header so
+// that a retry with the same key gets the original response instead of
+// repeating the request. Keys are scoped to the caller that sent them.
+type IdempotencyKey struct {
+	Owner        string
+	Key          string
+	RequestHash  string
+	ResponseBody json.RawMessage
+	CreatedAt    time.Time
+}
@@ -0,0 +1,81 @@
+package model
+
+import (
+	"time"
+
+	"github.com/glennprays/xyz-fin/pkg/money"
+)
+
+const (
+	InstallmentStatusUnpaid  = "UNPAID"
+	InstallmentStatusPartial = "PARTIAL"
+	InstallmentStatusPaid    = "PAID"
+	// InstallmentStatusSettled marks installments closed by an early
+	// settlement rather than paid one by one.
+	InstallmentStatusSettled = "SETTLED"
+	// InstallmentStatusCancelled marks installments of a contract cancelled
+	// before disbursement; nothing is owed on them.
+	InstallmentStatusCancelled = "CANCELLED"
+)
+
+// Installment is one monthly payment of a contract. TotalAmount is always
+// the sum of the principal, interest and admin fee parts; penalties are
+// charged on top of it once the installment is overdue.
+type Installment struct {
+	NomorKontrak      string       `json:"-"`
+	InstallmentNumber int          `json:"installment_number"`
+	DueDate           time.Time    `json:"due_date"`
+	Principal         money.Amount `json:"principal"`
+	Interest          money.Amount `json:"interest"`
+	AdminFee          money.Amount `json:"admin_fee"`
+	TotalAmount       money.Amount `json:"total_amount"`
+	Penalty           money.Amount `json:"penalty"`
+	PaidPrincipal     money.Amount `json:"paid_principal"`
+	PaidInterest      money.Amount `json:"paid_interest"`
+	PaidAdminFee      money.Amount `json:"paid_admin_fee"`
+	PaidPenalty       money.Amount `json:"paid_penalty"`
+	Status            string       `json:"status"`
+	PaidAt            *time.Time   `json:"paid_at"`
+	OverdueSince      *time.Time   `json:"overdue_since"`
+	PenaltyAccruedTo  *time.Time   `json:"-"`
+}
+
+// PenaltyCharge is one accrual of late-payment penalty (denda) on an
+// installment: Days days at DailyRate of the unpaid BaseAmount, capped by the
+// product. The charges of an installment add up to its Penalty.
+type PenaltyCharge struct {
+	ID                int64        `json:"id"`
+	NomorKontrak      string       `json:"nomor_kontrak"`
+	InstallmentNumber int          `json:"installment_number"`
+	ChargeDate        time.Time    `json:"charge_date"`
+	Days              int          `json:"days"`
+	BaseAmount        money.Amount `json:"base_amount"`
+	DailyRate         money.Rate   `json:"daily_rate"`
+	Amount            money.Amount `json:"amount"`
+	CreatedAt         time.Time    `json:"created_at"`
+}
+
+type InstallmentSchedule struct {
+	NomorKontrak   string        `json:"nomor_kontrak"`
+	TotalPrincipal money.Amount  `json:"total_principal"`
+	TotalInterest  money.Amount  `json:"total_interest"`
+	TotalAdminFee  money.Amount  `json:"total_admin_fee"`
+	TotalAmount    money.Amount  `json:"total_amount"`
+	Installments   []Installment `json:"installments"`
+}
+
+// ScheduleSummary condenses an installment schedule. OutstandingAmount is
+// what is still owed on the open installments, penalties included, and
+// OverdueAmount the part of it already past due.
+type ScheduleSummary struct {
+	InstallmentCount  int          `json:"installment_count"`
+	PaidCount         int          `json:"paid_count"`
+	OverdueCount      int          `json:"overdue_count"`
+	TotalAmount       money.Amount `json:"total_amount"`
+	TotalPenalty      money.Amount `json:"total_penalty"`
+	TotalPaid         money.Amount `json:"total_paid"`
+	OutstandingAmount money.Amount `json:"outstanding_amount"`
+	OverdueAmount     money.Amount `json:"overdue_amount"`
+	NextDueDate       *time.Time   `json:"next_due_date"`
+	NextDueAmount     money.Amount `json:"next_due_amount"`
+}
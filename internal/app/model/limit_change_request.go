@@ -0,0 +1,50 @@
+package model
+
+import (
+	"time"
+
+	"github.com/glennprays/xyz-fin/pkg/money"
+)
+
+const (
+	LimitChangeStatusPending  = "PENDING"
+	LimitChangeStatusApproved = "APPROVED"
+	LimitChangeStatusRejected = "REJECTED"
+)
+
+type LimitChangeRequest struct {
+	ID           int64                    `json:"id"`
+	ConsumerNIK  string                   `json:"consumer_nik"`
+	Reason       string                   `json:"reason"`
+	Source       string                   `json:"source"`
+	Status       string                   `json:"status"`
+	RequestedBy  string                   `json:"requested_by"`
+	DecidedBy    *string                  `json:"decided_by"`
+	DecisionNote *string                  `json:"decision_note"`
+	DecidedAt    *time.Time               `json:"decided_at"`
+	Items        []LimitChangeRequestItem `json:"items"`
+	CreatedAt    time.Time                `json:"created_at"`
+	UpdatedAt    time.Time                `json:"updated_at"`
+}
+
+type LimitChangeRequestItem struct {
+	Tenor           int           `json:"tenor"`
+	CurrentAmount   *money.Amount `json:"current_amount"`
+	RequestedAmount money.Amount  `json:"requested_amount"`
+}
+
+type CreateLimitChangeRequest struct {
+	ConsumerNIK string                         `json:"consumer_nik"`
+	Reason      string                         `json:"reason"`
+	Source      string                         `json:"source"`
+	Items       []CreateLimitChangeRequestItem `json:"items"`
+}
+
+type CreateLimitChangeRequestItem struct {
+	Tenor           int          `json:"tenor"`
+	RequestedAmount money.Amount `json:"requested_amount"`
+}
+
+type LimitChangeDecisionRequest struct {
+	Note string `json:"note"`
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+
+	"github.com/glennprays/xyz-fin/pkg/money"
+)
+
+const (
+	LimitHoldStatusActive   = "ACTIVE"
+	LimitHoldStatusCaptured = "CAPTURED"
+	LimitHoldStatusReleased = "RELEASED"
+	LimitHoldStatusExpired  = "EXPIRED"
+)
+
+type LimitHold struct {
+	ID           int64        `json:"id"`
+	ConsumerNIK  string       `json:"consumer_nik"`
+	MerchantID   *int64       `json:"merchant_id"`
+	ProductCode  string       `json:"product_code"`
+	Tenor        int          `json:"tenor"`
+	Amount       money.Amount `json:"amount"`
+	Reference    string       `json:"reference"`
+	Status       string       `json:"status"`
+	NomorKontrak *string      `json:"nomor_kontrak"`
+	ExpiresAt    time.Time    `json:"expires_at"`
+	Expired      bool         `json:"expired"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+}
+
+type CreateLimitHoldRequest struct {
+	ConsumerNIK string       `json:"consumer_nik"`
+	ProductCode string       `json:"product_code"`
+	Tenor       int          `json:"tenor"`
+	Amount      money.Amount `json:"amount"`
+	Reference   string       `json:"reference"`
+}
+
+type CaptureLimitHoldRequest struct {
+	NamaAsset  string `json:"nama_asset"`
+	MerchantID int64  `json:"merchant_id"`
+	OutletID   *int64 `json:"outlet_id"`
+}
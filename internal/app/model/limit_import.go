@@ -0,0 +1,32 @@
+package model
+
+import "github.com/glennprays/xyz-fin/pkg/money"
+
+const (
+	LimitImportRowValid   = "VALID"
+	LimitImportRowInvalid = "INVALID"
+	LimitImportRowApplied = "APPLIED"
+	LimitImportRowFailed  = "FAILED"
+)
+
+// LimitImportRow is one data line of a limit import file together with its
+// validation or apply outcome. Line is the 1-based line number in the file.
+type LimitImportRow struct {
+	Line        int          `json:"line"`
+	ConsumerNIK string       `json:"nik"`
+	Tenor       int          `json:"tenor"`
+	LimitAmount money.Amount `json:"limit_amount"`
+	Status      string       `json:"status"`
+	Error       string       `json:"error,omitempty"`
+}
+
+type LimitImportReport struct {
+	DryRun      bool             `json:"dry_run"`
+	Reference   string           `json:"reference,omitempty"`
+	TotalRows   int              `json:"total_rows"`
+	ValidRows   int              `json:"valid_rows"`
+	InvalidRows int              `json:"invalid_rows"`
+	AppliedRows int              `json:"applied_rows"`
+	FailedRows  int              `json:"failed_rows"`
+	Rows        []LimitImportRow `json:"rows"`
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"time"
+
+	"github.com/glennprays/xyz-fin/pkg/money"
+)
+
+const (
+	MerchantStatusActive     = "ACTIVE"
+	MerchantStatusSuspended  = "SUSPENDED"
+	MerchantStatusTerminated = "TERMINATED"
+)
+
+const (
+	OutletStatusActive = "ACTIVE"
+	OutletStatusClosed = "CLOSED"
+)
+
+// Merchant is a dealer or store where consumers finance purchases. MDRRate
+// is the merchant discount rate withheld from the OTR when the merchant is
+// paid out to its settlement account.
+type Merchant struct {
+	ID                int64             `json:"id"`
+	Code              string            `json:"code"`
+	Name              string            `json:"name"`
+	MDRRate           money.Rate        `json:"mdr_rate"`
+	SettlementAccount SettlementAccount `json:"settlement_account"`
+	Status            string            `json:"status"`
+	CreatedAt         time.Time         `json:"created_at"`
+	UpdatedAt         time.Time         `json:"updated_at"`
+	Outlets           []MerchantOutlet  `json:"outlets,omitempty"`
+}
+
+type SettlementAccount struct {
+	BankCode      string `json:"bank_code"`
+	AccountNumber string `json:"account_number"`
+	AccountName   string `json:"account_name"`
+}
+
+// MerchantOutlet is one physical or online point of sale of a merchant.
+type MerchantOutlet struct {
+	ID         int64     `json:"id"`
+	MerchantID int64     `json:"merchant_id"`
+	Code       string    `json:"code"`
+	Name       string    `json:"name"`
+	Address    string    `json:"address"`
+	Status     string    `json:"status"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type CreateMerchantRequest struct {
+	Code              string            `json:"code"`
+	Name              string            `json:"name"`
+	MDRRate           money.Rate        `json:"mdr_rate"`
+	SettlementAccount SettlementAccount `json:"settlement_account"`
+}
+
+type CreateMerchantOutletRequest struct {
+	Code    string `json:"code"`
+	Name    string `json:"name"`
+	Address string `json:"address"`
+}
+
+type MerchantStatusRequest struct {
+	Status string `json:"status"`
+}
+
+type MerchantFilter struct {
+	Status string `form:"status"`
+}
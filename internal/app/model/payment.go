@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+
+	"github.com/glennprays/xyz-fin/pkg/money"
+)
+
+// Payment components, the parts of an installment a payment can settle.
+const (
+	PaymentComponentPenalty   = "PENALTY"
+	PaymentComponentAdminFee  = "ADMIN_FEE"
+	PaymentComponentInterest  = "INTEREST"
+	PaymentComponentPrincipal = "PRINCIPAL"
+)
+
+// Payment is money received for a contract. AllocatedAmount went to the
+// installments; UnappliedAmount is what was left after the whole schedule
+// was settled and is owed back to the consumer.
+type Payment struct {
+	ID              int64               `json:"id"`
+	NomorKontrak    string              `json:"nomor_kontrak"`
+	Reference       string              `json:"reference"`
+	Amount          money.Amount        `json:"amount"`
+	AllocatedAmount money.Amount        `json:"allocated_amount"`
+	UnappliedAmount money.Amount        `json:"unapplied_amount"`
+	PaidAt          time.Time           `json:"paid_at"`
+	PostedBy        string              `json:"posted_by"`
+	CreatedAt       time.Time           `json:"created_at"`
+	Allocations     []PaymentAllocation `json:"allocations"`
+}
+
+type PaymentAllocation struct {
+	InstallmentNumber int          `json:"installment_number"`
+	Component         string       `json:"component"`
+	Amount            money.Amount `json:"amount"`
+}
+
+type PaymentRequest struct {
+	Reference string       `json:"reference"`
+	Amount    money.Amount `json:"amount"`
+	// PaidAt defaults to the time the payment is posted.
+	PaidAt *time.Time `json:"paid_at"`
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"github.com/glennprays/xyz-fin/pkg/money"
+)
+
+// Product is a financing product from the catalog. It defines which tenors
+// may be chosen, the OTR range, who is eligible to apply and how late
+// payments are penalised.
+type Product struct {
+	Code               string       `json:"code"`
+	Name               string       `json:"name"`
+	ContractPrefix     string       `json:"contract_prefix"`
+	Tenors             []int        `json:"tenors"`
+	MinOTR             money.Amount `json:"min_otr"`
+	MaxOTR             money.Amount `json:"max_otr"`
+	MinAge             int          `json:"min_age"`
+	MinIncome          money.Amount `json:"min_income"`
+	RequireVerifiedKYC bool         `json:"require_verified_kyc"`
+	InterestMethod     string       `json:"interest_method"`
+	GracePeriodDays    int          `json:"grace_period_days"`
+	PenaltyDailyRate   money.Rate   `json:"penalty_daily_rate"`
+	PenaltyCapRate     money.Rate   `json:"penalty_cap_rate"`
+	IsActive           bool         `json:"is_active"`
+	CreatedAt          time.Time    `json:"created_at"`
+	UpdatedAt          time.Time    `json:"updated_at"`
+}
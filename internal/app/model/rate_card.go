@@ -0,0 +1,71 @@
+package model
+
+import (
+	"time"
+
+	"github.com/glennprays/xyz-fin/pkg/loancalc"
+	"github.com/glennprays/xyz-fin/pkg/money"
+)
+
+const (
+	AdminFeeTypeFlat   = "FLAT"
+	AdminFeeTypeTiered = "TIERED"
+)
+
+// RateCard prices contracts for one product, tenor and risk grade. A new
+// version closes the previous one when it becomes effective, so the card
+// used for any contract can be looked up later.
+type RateCard struct {
+	ID            int64          `json:"id"`
+	ProductCode   string         `json:"product_code"`
+	Tenor         int            `json:"tenor"`
+	RiskGrade     string         `json:"risk_grade"`
+	Version       int            `json:"version"`
+	InterestRate  money.Rate     `json:"interest_rate"`
+	AdminFeeType  string         `json:"admin_fee_type"`
+	FeeTiers      []AdminFeeTier `json:"fee_tiers"`
+	EffectiveFrom time.Time      `json:"effective_from"`
+	EffectiveTo   *time.Time     `json:"effective_to"`
+	CreatedBy     *string        `json:"created_by"`
+	CreatedAt     time.Time      `json:"created_at"`
+}
+
+// AdminFeeTier applies to OTR amounts from MinOTR up to the next tier. The
+// fee is FeeAmount plus FeeRate of the OTR. A flat card has a single tier
+// starting at zero.
+type AdminFeeTier struct {
+	MinOTR    money.Amount `json:"min_otr"`
+	FeeAmount money.Amount `json:"fee_amount"`
+	FeeRate   money.Rate   `json:"fee_rate"`
+}
+
+type CreateRateCardRequest struct {
+	ProductCode   string         `json:"product_code"`
+	Tenor         int            `json:"tenor"`
+	RiskGrade     string         `json:"risk_grade"`
+	InterestRate  money.Rate     `json:"interest_rate"`
+	AdminFeeType  string         `json:"admin_fee_type"`
+	FeeTiers      []AdminFeeTier `json:"fee_tiers"`
+	EffectiveFrom *time.Time     `json:"effective_from"`
+}
+
+type RateCardFilter struct {
+	ProductCode string `form:"product_code"`
+	Tenor       int    `form:"tenor"`
+	RiskGrade   string `form:"risk_grade"`
+}
+
+// Pricing is the outcome of applying a rate card to a contract with the
+// product's interest method. Plan holds the repayment of principal and
+// interest; the admin fee is spread over the installments on top of it.
+type Pricing struct {
+	RateCardID      int64         `json:"rate_card_id"`
+	RateCardVersion int           `json:"rate_card_version"`
+	InterestMethod  string        `json:"interest_method"`
+	InterestRate    money.Rate    `json:"interest_rate"`
+	AdminFee        money.Amount  `json:"admin_fee"`
+	JumlahBunga     money.Amount  `json:"jumlah_bunga"`
+	APR             money.Rate    `json:"apr"`
+	EIR             money.Rate    `json:"eir"`
+	Plan            loancalc.Plan `json:"-"`
+}
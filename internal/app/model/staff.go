@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+const (
+	StaffRoleAdmin         = "admin"
+	StaffRoleCreditAnalyst = "credit_analyst"
+	// StaffRoleMerchant is held by the staff of a merchant, who only see the
+	// contracts their merchant originated.
+	StaffRoleMerchant = "merchant"
+)
+
+type Staff struct {
+	ID           int64     `json:"id"`
+	Username     string    `json:"username"`
+	PasswordHash string    `json:"-"`
+	FullName     string    `json:"full_name"`
+	Role         string    `json:"role"`
+	MerchantID   *int64    `json:"merchant_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// CreateStaffRequest provisions a staff account. MerchantCode is required for
+// merchant staff and must be empty for every other role.
+type CreateStaffRequest struct {
+	Username     string
+	Password     string
+	FullName     string
+	Role         string
+	MerchantCode string
+}
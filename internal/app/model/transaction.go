@@ -1,34 +1,175 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"github.com/glennprays/xyz-fin/pkg/money"
+	"github.com/glennprays/xyz-fin/pkg/pagination"
+)
+
+// Contract statuses. Only the transitions allowed by
+// service.ValidateContractTransition may change a contract's status.
+const (
+	TransactionStatusPendingDisbursement = "PENDING_DISBURSEMENT"
+	TransactionStatusActive              = "ACTIVE"
+	TransactionStatusPaidOff             = "PAID_OFF"
+	TransactionStatusCancelled           = "CANCELLED"
+	TransactionStatusDefaulted           = "DEFAULTED"
+	TransactionStatusWrittenOff          = "WRITTEN_OFF"
+	TransactionStatusRestructured        = "RESTRUCTURED"
+)
 
 type Transaction struct {
-	NomorKontrak  string    `json:"nomor_kontrak"`
-	ConsumerNIK   string    `json:"consumer_nik"`
-	OTR           float64   `json:"otr"`
-	AdminFee      float64   `json:"admin_fee"`
-	JumlahCicilan int       `json:"jumlah_cicilan"`
-	JumlahBunga   float64   `json:"jumlah_bunga"`
-	NamaAsset     string    `json:"nama_asset"`
-	Status        string    `json:"status"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	NomorKontrak         string       `json:"nomor_kontrak"`
+	ConsumerNIK          string       `json:"consumer_nik"`
+	OTR                  money.Amount `json:"otr"`
+	AdminFee             money.Amount `json:"admin_fee"`
+	JumlahCicilan        int          `json:"jumlah_cicilan"`
+	JumlahBunga          money.Amount `json:"jumlah_bunga"`
+	NamaAsset            string       `json:"nama_asset"`
+	Status               string       `json:"status"`
+	ProductCode          string       `json:"product_code"`
+	RateCardID           *int64       `json:"rate_card_id"`
+	MerchantID           *int64       `json:"merchant_id"`
+	OutletID             *int64       `json:"outlet_id"`
+	MDRAmount            money.Amount `json:"-"`
+	OutstandingPrincipal money.Amount `json:"outstanding_principal"`
+	TotalPaid            money.Amount `json:"total_paid"`
+	CreatedAt            time.Time    `json:"created_at"`
+	UpdatedAt            time.Time    `json:"updated_at"`
 }
 
+const (
+	TransactionSortByCreatedAt = "created_at"
+	TransactionSortByOTR       = "otr"
+)
+
 type TransactionRequest struct {
-	ConsumerNIK string  `json:"consumer_nik"`
-	OTR         float64 `json:"otr"`
-	Tenor       int     `json:"tenor"`
-	NamaAsset   string  `json:"nama_asset"`
+	ProductCode string       `json:"product_code"`
+	ConsumerNIK string       `json:"consumer_nik"`
+	OTR         money.Amount `json:"otr"`
+	Tenor       int          `json:"tenor"`
+	NamaAsset   string       `json:"nama_asset"`
+	QuoteID     string       `json:"quote_id"`
+	MerchantID  int64        `json:"merchant_id"`
+	OutletID    *int64       `json:"outlet_id"`
 }
 
 type TransactionResponse struct {
-	NomorKontrak  string  `json:"nomor_kontrak"`
-	ConsumerNIK   string  `json:"consumer_nik"`
-	OTR           float64 `json:"otr"`
-	AdminFee      float64 `json:"admin_fee"`
-	JumlahCicilan int     `json:"jumlah_cicilan"`
-	JumlahBunga   float64 `json:"jumlah_bunga"`
-	NamaAsset     string  `json:"nama_asset"`
-	Status        string  `json:"status"`
+	NomorKontrak  string       `json:"nomor_kontrak"`
+	ProductCode   string       `json:"product_code"`
+	ConsumerNIK   string       `json:"consumer_nik"`
+	OTR           money.Amount `json:"otr"`
+	AdminFee      money.Amount `json:"admin_fee"`
+	JumlahCicilan int          `json:"jumlah_cicilan"`
+	JumlahBunga   money.Amount `json:"jumlah_bunga"`
+	NamaAsset     string       `json:"nama_asset"`
+	Status        string       `json:"status"`
+	RateCardID    *int64       `json:"rate_card_id"`
+	MerchantID    *int64       `json:"merchant_id"`
+	OutletID      *int64       `json:"outlet_id"`
+}
+
+type QuoteRequest struct {
+	ProductCode string       `json:"product_code"`
+	OTR         money.Amount `json:"otr"`
+	Tenor       int          `json:"tenor"`
+}
+
+// Quote is the priced offer for a prospective contract. QuoteID can be sent
+// with CreateTransaction until ExpiresAt to get the same pricing.
+type Quote struct {
+	QuoteID            string        `json:"quote_id"`
+	ExpiresAt          time.Time     `json:"expires_at"`
+	ProductCode        string        `json:"product_code"`
+	OTR                money.Amount  `json:"otr"`
+	Tenor              int           `json:"tenor"`
+	RateCardID         int64         `json:"rate_card_id"`
+	InterestMethod     string        `json:"interest_method"`
+	InterestRate       money.Rate    `json:"interest_rate"`
+	AdminFee           money.Amount  `json:"admin_fee"`
+	JumlahBunga        money.Amount  `json:"jumlah_bunga"`
+	MonthlyInstallment money.Amount  `json:"monthly_installment"`
+	TotalPayable       money.Amount  `json:"total_payable"`
+	APR                money.Rate    `json:"apr"`
+	EIR                money.Rate    `json:"eir"`
+	Installments       []Installment `json:"installments"`
+}
+
+// TransactionStatusTransition is one entry of a contract's status log.
+// FromStatus is nil for the entry recorded when the contract was opened.
+type TransactionStatusTransition struct {
+	ID           int64     `json:"id"`
+	NomorKontrak string    `json:"nomor_kontrak"`
+	FromStatus   *string   `json:"from_status"`
+	ToStatus     string    `json:"to_status"`
+	Reason       string    `json:"reason"`
+	ChangedBy    string    `json:"changed_by"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type TransactionStatusRequest struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+type TransactionHistoryRequest struct {
+	Status      string     `form:"status"`
+	CreatedFrom *time.Time `form:"created_from" time_format:"2006-01-02"`
+	CreatedTo   *time.Time `form:"created_to" time_format:"2006-01-02"`
+	Asset       string     `form:"asset"`
+	SortBy      string     `form:"sort_by"`
+	SortOrder   string     `form:"sort_order"`
+	Limit       int        `form:"limit"`
+	Cursor      string     `form:"cursor"`
+}
+
+type TransactionHistoryFilter struct {
+	ConsumerNIK string
+	MerchantID  int64
+	Status      string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Asset       string
+	SortBy      string
+	SortOrder   string
+	Limit       int
+	Cursor      *pagination.Cursor
+}
+
+// TransactionSummary is a contract as listed in a consumer's history or a
+// merchant's contract list.
+// OutstandingBalance is everything still owed on the open installments,
+// penalties included.
+type TransactionSummary struct {
+	NomorKontrak         string       `json:"nomor_kontrak"`
+	ProductCode          string       `json:"product_code"`
+	OTR                  money.Amount `json:"otr"`
+	AdminFee             money.Amount `json:"admin_fee"`
+	JumlahCicilan        int          `json:"jumlah_cicilan"`
+	JumlahBunga          money.Amount `json:"jumlah_bunga"`
+	NamaAsset            string       `json:"nama_asset"`
+	Status               string       `json:"status"`
+	MerchantID           *int64       `json:"merchant_id"`
+	OutletID             *int64       `json:"outlet_id"`
+	OutstandingPrincipal money.Amount `json:"outstanding_principal"`
+	OutstandingBalance   money.Amount `json:"outstanding_balance"`
+	TotalPaid            money.Amount `json:"total_paid"`
+	CreatedAt            time.Time    `json:"created_at"`
+}
+
+type TransactionHistoryResponse struct {
+	Data       []TransactionSummary `json:"data"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+}
+
+// TransactionDetail is a contract with its schedule, payments and status
+// history as of the time it was read. MDRAmount is the merchant's commercial
+// term and is only filled in for staff and merchant users.
+type TransactionDetail struct {
+	Transaction   Transaction                   `json:"transaction"`
+	MDRAmount     *money.Amount                 `json:"mdr_amount,omitempty"`
+	Schedule      ScheduleSummary               `json:"schedule"`
+	Payments      []Payment                     `json:"payments"`
+	StatusHistory []TransactionStatusTransition `json:"status_history"`
 }
@@ -3,13 +3,18 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/glennprays/xyz-fin/internal/app/model"
 )
 
 type ConsumerLimitRepository interface {
 	FindByNIK(ctx context.Context, nik string) ([]model.ConsumerLimit, error)
+	FindByNIKInTx(ctx context.Context, tx *sql.Tx, nik string) ([]model.ConsumerLimit, error)
 	FindByNIKAndTenor(ctx context.Context, tx *sql.Tx, nik string, tenor int) (*model.ConsumerLimit, error)
+	Upsert(ctx context.Context, tx *sql.Tx, consumerLimit *model.ConsumerLimit, source string, sourceReference string) error
+	FindVersions(ctx context.Context, nik string, tenor int) ([]model.ConsumerLimitVersion, error)
+	FindAsOf(ctx context.Context, nik string, tenor int, asOf time.Time) (*model.ConsumerLimitVersion, error)
 }
 
 type consumerLimitRepository struct {
@@ -21,11 +26,22 @@ func NewConsumerLimitRepository(db *sql.DB) ConsumerLimitRepository {
 }
 
 func (r *consumerLimitRepository) FindByNIK(ctx context.Context, nik string) ([]model.ConsumerLimit, error) {
+	return findConsumerLimitsByNIK(ctx, r.db, nik)
+}
+
+// FindByNIKInTx reads the limits inside tx, so a caller holding the consumer
+// lock sees the same limits it checks against and not ones an import or
+// approval is about to replace.
+func (r *consumerLimitRepository) FindByNIKInTx(ctx context.Context, tx *sql.Tx, nik string) ([]model.ConsumerLimit, error) {
+	return findConsumerLimitsByNIK(ctx, tx, nik)
+}
+
+func findConsumerLimitsByNIK(ctx context.Context, q queryer, nik string) ([]model.ConsumerLimit, error) {
 	var consumerLimits []model.ConsumerLimit
 	query := `SELECT consumer_nik, tenor, limit_amount, created_at, updated_at
 			  FROM consumer_limits WHERE consumer_nik = $1 ORDER BY tenor ASC`
 
-	rows, err := r.db.QueryContext(ctx, query, nik)
+	rows, err := q.QueryContext(ctx, query, nik)
 	if err != nil {
 		return nil, err
 	}
@@ -61,3 +77,109 @@ func (r *consumerLimitRepository) FindByNIKAndTenor(ctx context.Context, tx *sql
 
 	return consumerLimit, nil
 }
+
+// Upsert sets the current limit and records it as a new version, closing the
+// previous version at the same instant.
+func (r *consumerLimitRepository) Upsert(ctx context.Context, tx *sql.Tx, consumerLimit *model.ConsumerLimit, source string, sourceReference string) error {
+	query := `
+		INSERT INTO consumer_limits (consumer_nik, tenor, limit_amount)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (consumer_nik, tenor) DO UPDATE SET limit_amount = EXCLUDED.limit_amount
+	`
+
+	_, err := tx.ExecContext(ctx, query,
+		consumerLimit.ConsumerNIK,
+		consumerLimit.Tenor,
+		consumerLimit.LimitAmount,
+	)
+	if err != nil {
+		return err
+	}
+
+	closeQuery := `
+		UPDATE consumer_limit_versions SET valid_to = NOW()
+		WHERE consumer_nik = $1 AND tenor = $2 AND valid_to IS NULL
+	`
+
+	_, err = tx.ExecContext(ctx, closeQuery, consumerLimit.ConsumerNIK, consumerLimit.Tenor)
+	if err != nil {
+		return err
+	}
+
+	versionQuery := `
+		INSERT INTO consumer_limit_versions (consumer_nik, tenor, limit_amount, source, source_reference, valid_from)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), NOW())
+	`
+
+	_, err = tx.ExecContext(ctx, versionQuery,
+		consumerLimit.ConsumerNIK,
+		consumerLimit.Tenor,
+		consumerLimit.LimitAmount,
+		source,
+		sourceReference,
+	)
+	return err
+}
+
+const consumerLimitVersionColumns = `id, consumer_nik, tenor, limit_amount, source, source_reference, valid_from, valid_to, created_at`
+
+func scanConsumerLimitVersion(row rowScanner) (*model.ConsumerLimitVersion, error) {
+	version := &model.ConsumerLimitVersion{}
+	var (
+		sourceReference sql.NullString
+		validTo         sql.NullTime
+	)
+	err := row.Scan(&version.ID, &version.ConsumerNIK, &version.Tenor, &version.LimitAmount, &version.Source, &sourceReference, &version.ValidFrom, &validTo, &version.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if sourceReference.Valid {
+		version.SourceReference = &sourceReference.String
+	}
+	if validTo.Valid {
+		version.ValidTo = &validTo.Time
+	}
+	return version, nil
+}
+
+func (r *consumerLimitRepository) FindVersions(ctx context.Context, nik string, tenor int) ([]model.ConsumerLimitVersion, error) {
+	query := `SELECT ` + consumerLimitVersionColumns + ` FROM consumer_limit_versions
+  WHERE consumer_nik = $1 AND tenor = $2 ORDER BY valid_from DESC, id DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, nik, tenor)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := []model.ConsumerLimitVersion{}
+	for rows.Next() {
+		version, err := scanConsumerLimitVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, *version)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+func (r *consumerLimitRepository) FindAsOf(ctx context.Context, nik string, tenor int, asOf time.Time) (*model.ConsumerLimitVersion, error) {
+	query := `SELECT ` + consumerLimitVersionColumns + ` FROM consumer_limit_versions
+  WHERE consumer_nik = $1 AND tenor = $2 AND valid_from <= $3 AND (valid_to IS NULL OR valid_to > $3)
+  ORDER BY valid_from DESC, id DESC LIMIT 1`
+
+	version, err := scanConsumerLimitVersion(r.db.QueryRowContext(ctx, query, nik, tenor, asOf))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return version, nil
+}
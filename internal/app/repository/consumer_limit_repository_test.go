@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/pkg/money"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -56,13 +58,42 @@ func (s *consumerLimitRepositoryTestSuite) TestFindByNIK() {
 	s.Len(consumerLimits, 3)
 	s.Equal("12345", consumerLimits[0].ConsumerNIK)
 	s.Equal(6, consumerLimits[0].Tenor)
-	s.Equal(500000.00, consumerLimits[0].LimitAmount)
+	s.Equal(money.MustParse("500000.00"), consumerLimits[0].LimitAmount)
 	s.Equal("12345", consumerLimits[1].ConsumerNIK)
 	s.Equal(12, consumerLimits[1].Tenor)
-	s.Equal(1000000.00, consumerLimits[1].LimitAmount)
+	s.Equal(money.MustParse("1000000.00"), consumerLimits[1].LimitAmount)
 	s.Equal("12345", consumerLimits[2].ConsumerNIK)
 	s.Equal(24, consumerLimits[2].Tenor)
-	s.Equal(2000000.00, consumerLimits[2].LimitAmount)
+	s.Equal(money.MustParse("2000000.00"), consumerLimits[2].LimitAmount)
+}
+
+func (s *consumerLimitRepositoryTestSuite) TestFindByNIKInTx() {
+	nik := "12345"
+	dummyTime := time.Now()
+
+	rows := sqlmock.NewRows([]string{
+		"consumer_nik", "tenor", "limit_amount", "created_at", "updated_at",
+	}).AddRow(
+		"12345", 6, 500000.00, dummyTime, dummyTime,
+	).AddRow(
+		"12345", 12, 1000000.00, dummyTime, dummyTime,
+	)
+
+	s.Mock.ExpectBegin()
+	s.Mock.ExpectQuery(`SELECT consumer_nik, tenor, limit_amount, created_at, updated_at FROM consumer_limits WHERE consumer_nik = \$1 ORDER BY tenor ASC`).
+		WithArgs(nik).
+		WillReturnRows(rows)
+
+	ctx := context.Background()
+	tx, err := s.DB.Begin()
+	s.Require().NoError(err)
+
+	consumerLimits, err := s.Repo.FindByNIKInTx(ctx, tx, nik)
+
+	s.Require().NoError(err)
+	s.Len(consumerLimits, 2)
+	s.Equal(6, consumerLimits[0].Tenor)
+	s.Equal(money.MustParse("1000000.00"), consumerLimits[1].LimitAmount)
 }
 
 func (s *consumerLimitRepositoryTestSuite) TestFindByNIKAndTenor_Success() {
@@ -91,7 +122,7 @@ func (s *consumerLimitRepositoryTestSuite) TestFindByNIKAndTenor_Success() {
 	s.Require().NotNil(consumerLimit)
 	s.Equal(nik, consumerLimit.ConsumerNIK)
 	s.Equal(tenor, consumerLimit.Tenor)
-	s.Equal(1000000.00, consumerLimit.LimitAmount)
+	s.Equal(money.MustParse("1000000.00"), consumerLimit.LimitAmount)
 
 	s.Mock.ExpectCommit()
 	err = tx.Commit()
@@ -121,6 +152,94 @@ func (s *consumerLimitRepositoryTestSuite) TestFindByNIKAndTenor_NotFound() {
 	s.Require().NoError(err)
 }
 
+func (s *consumerLimitRepositoryTestSuite) TestUpsert_Success() {
+	s.Mock.ExpectBegin()
+	tx, err := s.DB.Begin()
+	s.Require().NoError(err)
+
+	consumerLimit := &model.ConsumerLimit{
+		ConsumerNIK: "12345",
+		Tenor:       6,
+		LimitAmount: money.MustParse("1500000.00"),
+	}
+
+	s.Mock.ExpectExec(`INSERT INTO consumer_limits \(consumer_nik, tenor, limit_amount\) VALUES \(\$1, \$2, \$3\) ON CONFLICT \(consumer_nik, tenor\) DO UPDATE SET limit_amount = EXCLUDED.limit_amount`).
+		WithArgs(consumerLimit.ConsumerNIK, consumerLimit.Tenor, consumerLimit.LimitAmount).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.Mock.ExpectExec(`UPDATE consumer_limit_versions SET valid_to = NOW\(\) WHERE consumer_nik = \$1 AND tenor = \$2 AND valid_to IS NULL`).
+		WithArgs(consumerLimit.ConsumerNIK, consumerLimit.Tenor).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.Mock.ExpectExec(`INSERT INTO consumer_limit_versions \(consumer_nik, tenor, limit_amount, source, source_reference, valid_from\) VALUES \(\$1, \$2, \$3, \$4, NULLIF\(\$5, ''\), NOW\(\)\)`).
+		WithArgs(consumerLimit.ConsumerNIK, consumerLimit.Tenor, consumerLimit.LimitAmount, model.LimitSourceManual, "limit_change_request:7").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = s.Repo.Upsert(context.Background(), tx, consumerLimit, model.LimitSourceManual, "limit_change_request:7")
+	s.Require().NoError(err)
+
+	s.Mock.ExpectCommit()
+	err = tx.Commit()
+	s.Require().NoError(err)
+}
+
+func (s *consumerLimitRepositoryTestSuite) TestFindVersions() {
+	dummyTime := time.Now()
+	reference := "limit_change_request:7"
+
+	rows := sqlmock.NewRows([]string{
+		"id", "consumer_nik", "tenor", "limit_amount", "source", "source_reference", "valid_from", "valid_to", "created_at",
+	}).AddRow(
+		2, "12345", 6, 1500000.00, model.LimitSourceManual, reference, dummyTime, nil, dummyTime,
+	).AddRow(
+		1, "12345", 6, 700000.00, model.LimitSourceEngine, nil, dummyTime.Add(-time.Hour), dummyTime, dummyTime,
+	)
+
+	s.Mock.ExpectQuery(`SELECT id, consumer_nik, tenor, limit_amount, source, source_reference, valid_from, valid_to, created_at FROM consumer_limit_versions WHERE consumer_nik = \$1 AND tenor = \$2 ORDER BY valid_from DESC, id DESC`).
+		WithArgs("12345", 6).
+		WillReturnRows(rows)
+
+	versions, err := s.Repo.FindVersions(context.Background(), "12345", 6)
+
+	s.Require().NoError(err)
+	s.Require().Len(versions, 2)
+	s.Nil(versions[0].ValidTo)
+	s.Require().NotNil(versions[0].SourceReference)
+	s.Equal(reference, *versions[0].SourceReference)
+	s.Equal(model.LimitSourceEngine, versions[1].Source)
+	s.Nil(versions[1].SourceReference)
+	s.Require().NotNil(versions[1].ValidTo)
+}
+
+func (s *consumerLimitRepositoryTestSuite) TestFindAsOf_Success() {
+	asOf := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s.Mock.ExpectQuery(`SELECT .* FROM consumer_limit_versions WHERE consumer_nik = \$1 AND tenor = \$2 AND valid_from <= \$3 AND \(valid_to IS NULL OR valid_to > \$3\) ORDER BY valid_from DESC, id DESC LIMIT 1`).
+		WithArgs("12345", 6, asOf).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "consumer_nik", "tenor", "limit_amount", "source", "source_reference", "valid_from", "valid_to", "created_at",
+		}).AddRow(
+			1, "12345", 6, 700000.00, model.LimitSourceEngine, nil, asOf.Add(-time.Hour), asOf.Add(time.Hour), asOf,
+		))
+
+	version, err := s.Repo.FindAsOf(context.Background(), "12345", 6, asOf)
+
+	s.Require().NoError(err)
+	s.Require().NotNil(version)
+	s.Equal(money.MustParse("700000.00"), version.LimitAmount)
+}
+
+func (s *consumerLimitRepositoryTestSuite) TestFindAsOf_NotFound() {
+	asOf := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s.Mock.ExpectQuery(`SELECT .* FROM consumer_limit_versions WHERE consumer_nik = \$1`).
+		WithArgs("12345", 6, asOf).
+		WillReturnError(sql.ErrNoRows)
+
+	version, err := s.Repo.FindAsOf(context.Background(), "12345", 6, asOf)
+
+	s.Require().NoError(err)
+	s.Nil(version)
+}
+
 func TestConsumerLimitRepositoryTestSuite(t *testing.T) {
 	suite.Run(t, new(consumerLimitRepositoryTestSuite))
 }
@@ -3,14 +3,19 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
 
 	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/lib/pq"
 )
 
 type ConsumerRepository interface {
 	FindByPhoneNumber(ctx context.Context, phoneNumber string) (*model.Consumer, error)
 	FindByNIK(ctx context.Context, nik string) (*model.Consumer, error)
 	FindAndLockByNIK(ctx context.Context, tx *sql.Tx, nik string) (*model.Consumer, error)
+	Search(ctx context.Context, filter model.ConsumerSearchFilter) ([]model.ConsumerSummary, error)
+	FindExistingNIKs(ctx context.Context, niks []string) (map[string]bool, error)
 }
 
 type consumerRepository struct {
@@ -23,9 +28,9 @@ func NewConsumerRepository(db *sql.DB) ConsumerRepository {
 
 func (r *consumerRepository) FindByPhoneNumber(ctx context.Context, phoneNumber string) (*model.Consumer, error) {
 	consumer := &model.Consumer{}
-	query := `SELECT nik, phone_number, password_hash, full_name, legal_name, tempat_lahir, tanggal_lahir, gaji, foto_ktp_path, foto_selfie_path, created_at, updated_at 
+	query := `SELECT nik, phone_number, password_hash, full_name, legal_name, tempat_lahir, tanggal_lahir, gaji, foto_ktp_path, foto_selfie_path, kyc_status, risk_grade, created_at, updated_at 
   FROM consumers WHERE phone_number = $1`
-	err := r.db.QueryRowContext(ctx, query, phoneNumber).Scan(&consumer.NIK, &consumer.PhoneNumber, &consumer.PasswordHash, &consumer.FullName, &consumer.LegalName, &consumer.TempatLahir, &consumer.TanggalLahir, &consumer.Gaji, &consumer.FotoKTPPath, &consumer.FotoSelfiePath, &consumer.CreatedAt, &consumer.UpdatedAt)
+	err := r.db.QueryRowContext(ctx, query, phoneNumber).Scan(&consumer.NIK, &consumer.PhoneNumber, &consumer.PasswordHash, &consumer.FullName, &consumer.LegalName, &consumer.TempatLahir, &consumer.TanggalLahir, &consumer.Gaji, &consumer.FotoKTPPath, &consumer.FotoSelfiePath, &consumer.KYCStatus, &consumer.RiskGrade, &consumer.CreatedAt, &consumer.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -37,9 +42,9 @@ func (r *consumerRepository) FindByPhoneNumber(ctx context.Context, phoneNumber
 
 func (r *consumerRepository) FindByNIK(ctx context.Context, nik string) (*model.Consumer, error) {
 	consumer := &model.Consumer{}
-	query := `SELECT nik, phone_number, password_hash, full_name, legal_name, tempat_lahir, tanggal_lahir, gaji, foto_ktp_path, foto_selfie_path, created_at, updated_at 
+	query := `SELECT nik, phone_number, password_hash, full_name, legal_name, tempat_lahir, tanggal_lahir, gaji, foto_ktp_path, foto_selfie_path, kyc_status, risk_grade, created_at, updated_at 
   FROM consumers WHERE nik = $1`
-	err := r.db.QueryRowContext(ctx, query, nik).Scan(&consumer.NIK, &consumer.PhoneNumber, &consumer.PasswordHash, &consumer.FullName, &consumer.LegalName, &consumer.TempatLahir, &consumer.TanggalLahir, &consumer.Gaji, &consumer.FotoKTPPath, &consumer.FotoSelfiePath, &consumer.CreatedAt, &consumer.UpdatedAt)
+	err := r.db.QueryRowContext(ctx, query, nik).Scan(&consumer.NIK, &consumer.PhoneNumber, &consumer.PasswordHash, &consumer.FullName, &consumer.LegalName, &consumer.TempatLahir, &consumer.TanggalLahir, &consumer.Gaji, &consumer.FotoKTPPath, &consumer.FotoSelfiePath, &consumer.KYCStatus, &consumer.RiskGrade, &consumer.CreatedAt, &consumer.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -51,9 +56,9 @@ func (r *consumerRepository) FindByNIK(ctx context.Context, nik string) (*model.
 
 func (r *consumerRepository) FindAndLockByNIK(ctx context.Context, tx *sql.Tx, nik string) (*model.Consumer, error) {
 	consumer := &model.Consumer{}
-	query := `SELECT nik, phone_number, password_hash, full_name, legal_name, tempat_lahir, tanggal_lahir, gaji, foto_ktp_path, foto_selfie_path, created_at, updated_at 
+	query := `SELECT nik, phone_number, password_hash, full_name, legal_name, tempat_lahir, tanggal_lahir, gaji, foto_ktp_path, foto_selfie_path, kyc_status, risk_grade, created_at, updated_at 
   FROM consumers WHERE nik = $1 FOR UPDATE`
-	err := tx.QueryRowContext(ctx, query, nik).Scan(&consumer.NIK, &consumer.PhoneNumber, &consumer.PasswordHash, &consumer.FullName, &consumer.LegalName, &consumer.TempatLahir, &consumer.TanggalLahir, &consumer.Gaji, &consumer.FotoKTPPath, &consumer.FotoSelfiePath, &consumer.CreatedAt, &consumer.UpdatedAt)
+	err := tx.QueryRowContext(ctx, query, nik).Scan(&consumer.NIK, &consumer.PhoneNumber, &consumer.PasswordHash, &consumer.FullName, &consumer.LegalName, &consumer.TempatLahir, &consumer.TanggalLahir, &consumer.Gaji, &consumer.FotoKTPPath, &consumer.FotoSelfiePath, &consumer.KYCStatus, &consumer.RiskGrade, &consumer.CreatedAt, &consumer.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -62,3 +67,112 @@ func (r *consumerRepository) FindAndLockByNIK(ctx context.Context, tx *sql.Tx, n
 	}
 	return consumer, nil
 }
+
+func (r *consumerRepository) Search(ctx context.Context, filter model.ConsumerSearchFilter) ([]model.ConsumerSummary, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	addArg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.NamePrefix != "" {
+		conditions = append(conditions, "LOWER(c.full_name) LIKE "+addArg(escapeLike(strings.ToLower(filter.NamePrefix))+"%"))
+	}
+	if filter.PhoneNumber != "" {
+		conditions = append(conditions, "c.phone_number = "+addArg(filter.PhoneNumber))
+	}
+	if filter.NIK != "" {
+		conditions = append(conditions, "c.nik = "+addArg(filter.NIK))
+	}
+	if filter.KYCStatus != "" {
+		conditions = append(conditions, "c.kyc_status = "+addArg(filter.KYCStatus))
+	}
+	if filter.CreatedFrom != nil {
+		conditions = append(conditions, "c.created_at >= "+addArg(*filter.CreatedFrom))
+	}
+	if filter.CreatedTo != nil {
+		conditions = append(conditions, "c.created_at < "+addArg(*filter.CreatedTo))
+	}
+	if filter.HasActiveContract != nil {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM transactions t WHERE t.consumer_nik = c.nik AND t.status = 'ACTIVE') = "+addArg(*filter.HasActiveContract))
+	}
+
+	sortColumn := "c.created_at"
+	cursorCast := "::timestamp"
+	if filter.SortBy == model.ConsumerSortByFullName {
+		sortColumn = "c.full_name"
+		cursorCast = ""
+	}
+	direction, comparator := "ASC", ">"
+	if filter.SortOrder == model.SortOrderDesc {
+		direction, comparator = "DESC", "<"
+	}
+
+	if filter.Cursor != nil {
+		conditions = append(conditions, fmt.Sprintf("(%s, c.nik) %s (%s%s, %s)",
+			sortColumn, comparator, addArg(filter.Cursor.SortValue), cursorCast, addArg(filter.Cursor.Key)))
+	}
+
+	query := `SELECT c.nik, c.phone_number, c.full_name, c.kyc_status, c.created_at,
+  EXISTS (SELECT 1 FROM transactions t WHERE t.consumer_nik = c.nik AND t.status = 'ACTIVE') AS has_active_contract
+  FROM consumers c`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += fmt.Sprintf(" ORDER BY %s %s, c.nik %s LIMIT %s", sortColumn, direction, direction, addArg(filter.Limit))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	consumers := []model.ConsumerSummary{}
+	for rows.Next() {
+		consumer := model.ConsumerSummary{}
+		if err := rows.Scan(&consumer.NIK, &consumer.PhoneNumber, &consumer.FullName, &consumer.KYCStatus, &consumer.CreatedAt, &consumer.HasActiveContract); err != nil {
+			return nil, err
+		}
+		consumers = append(consumers, consumer)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return consumers, nil
+}
+
+func escapeLike(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(value)
+}
+
+// FindExistingNIKs returns the subset of niks that belong to a consumer.
+func (r *consumerRepository) FindExistingNIKs(ctx context.Context, niks []string) (map[string]bool, error) {
+	query := `SELECT nik FROM consumers WHERE nik = ANY($1)`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(niks))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	existing := make(map[string]bool, len(niks))
+	for rows.Next() {
+		var nik string
+		if err := rows.Scan(&nik); err != nil {
+			return nil, err
+		}
+		existing[nik] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return existing, nil
+}
@@ -7,6 +7,10 @@ import (
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/pkg/money"
+	"github.com/glennprays/xyz-fin/pkg/pagination"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -34,12 +38,12 @@ func (s *consumerRepositoryTestSuite) TestFindByPhoneNumber() {
 	dummyTime := time.Now()
 
 	rows := sqlmock.NewRows([]string{
-		"nik", "phone_number", "password_hash", "full_name", "legal_name", "tempat_lahir", "tanggal_lahir", "gaji", "foto_ktp_path", "foto_selfie_path", "created_at", "updated_at",
+		"nik", "phone_number", "password_hash", "full_name", "legal_name", "tempat_lahir", "tanggal_lahir", "gaji", "foto_ktp_path", "foto_selfie_path", "kyc_status", "risk_grade", "created_at", "updated_at",
 	}).AddRow(
-		"12345", "081234567890", "hashed-password", "John Doe", "Legal Name", "City", dummyTime, 5000000.00, "/path/to/ktp.jpg", "/path/to/selfie.jpg", dummyTime, dummyTime,
+		"12345", "081234567890", "hashed-password", "John Doe", "Legal Name", "City", dummyTime, 5000000.00, "/path/to/ktp.jpg", "/path/to/selfie.jpg", "VERIFIED", "B", dummyTime, dummyTime,
 	)
 
-	s.Mock.ExpectQuery(`SELECT nik, phone_number, password_hash, full_name, legal_name, tempat_lahir, tanggal_lahir, gaji, foto_ktp_path, foto_selfie_path, created_at, updated_at FROM consumers WHERE phone_number = \$1`).
+	s.Mock.ExpectQuery(`SELECT nik, phone_number, password_hash, full_name, legal_name, tempat_lahir, tanggal_lahir, gaji, foto_ktp_path, foto_selfie_path, kyc_status, risk_grade, created_at, updated_at FROM consumers WHERE phone_number = \$1`).
 		WithArgs(phone).
 		WillReturnRows(rows)
 
@@ -59,12 +63,12 @@ func (s *consumerRepositoryTestSuite) TestFindByNIK() {
 	dummyTime := time.Now()
 
 	rows := sqlmock.NewRows([]string{
-		"nik", "phone_number", "password_hash", "full_name", "legal_name", "tempat_lahir", "tanggal_lahir", "gaji", "foto_ktp_path", "foto_selfie_path", "created_at", "updated_at",
+		"nik", "phone_number", "password_hash", "full_name", "legal_name", "tempat_lahir", "tanggal_lahir", "gaji", "foto_ktp_path", "foto_selfie_path", "kyc_status", "risk_grade", "created_at", "updated_at",
 	}).AddRow(
-		"12345", "081234567890", "hashed-password", "John Doe", "Legal Name", "City", dummyTime, 5000000.00, "/path/to/ktp.jpg", "/path/to/selfie.jpg", dummyTime, dummyTime,
+		"12345", "081234567890", "hashed-password", "John Doe", "Legal Name", "City", dummyTime, 5000000.00, "/path/to/ktp.jpg", "/path/to/selfie.jpg", "VERIFIED", "B", dummyTime, dummyTime,
 	)
 
-	s.Mock.ExpectQuery(`SELECT nik, phone_number, password_hash, full_name, legal_name, tempat_lahir, tanggal_lahir, gaji, foto_ktp_path, foto_selfie_path, created_at, updated_at FROM consumers WHERE nik = \$1`).
+	s.Mock.ExpectQuery(`SELECT nik, phone_number, password_hash, full_name, legal_name, tempat_lahir, tanggal_lahir, gaji, foto_ktp_path, foto_selfie_path, kyc_status, risk_grade, created_at, updated_at FROM consumers WHERE nik = \$1`).
 		WithArgs(nik).
 		WillReturnRows(rows)
 
@@ -89,12 +93,12 @@ func (s *consumerRepositoryTestSuite) TestFindAndLockByNIK_Success() {
 	s.Require().NoError(err)
 
 	rows := sqlmock.NewRows([]string{
-		"nik", "phone_number", "password_hash", "full_name", "legal_name", "tempat_lahir", "tanggal_lahir", "gaji", "foto_ktp_path", "foto_selfie_path", "created_at", "updated_at",
+		"nik", "phone_number", "password_hash", "full_name", "legal_name", "tempat_lahir", "tanggal_lahir", "gaji", "foto_ktp_path", "foto_selfie_path", "kyc_status", "risk_grade", "created_at", "updated_at",
 	}).AddRow(
-		nik, "08123456789", "hashedpassword", "Full Name", "Legal Name", "Tempat Lahir", dummyTime, 5000000.00, "ktp/path", "selfie/path", dummyTime, dummyTime,
+		nik, "08123456789", "hashedpassword", "Full Name", "Legal Name", "Tempat Lahir", dummyTime, 5000000.00, "ktp/path", "selfie/path", "VERIFIED", "B", dummyTime, dummyTime,
 	)
 
-	s.Mock.ExpectQuery(`SELECT nik, phone_number, password_hash, full_name, legal_name, tempat_lahir, tanggal_lahir, gaji, foto_ktp_path, foto_selfie_path, created_at, updated_at FROM consumers WHERE nik = \$1 FOR UPDATE`).
+	s.Mock.ExpectQuery(`SELECT nik, phone_number, password_hash, full_name, legal_name, tempat_lahir, tanggal_lahir, gaji, foto_ktp_path, foto_selfie_path, kyc_status, risk_grade, created_at, updated_at FROM consumers WHERE nik = \$1 FOR UPDATE`).
 		WithArgs(nik).
 		WillReturnRows(rows)
 
@@ -109,9 +113,10 @@ func (s *consumerRepositoryTestSuite) TestFindAndLockByNIK_Success() {
 	s.Equal("Legal Name", consumer.LegalName)
 	s.Equal("Tempat Lahir", consumer.TempatLahir)
 	s.WithinDuration(dummyTime, consumer.TanggalLahir, time.Second)
-	s.Equal(5000000.00, consumer.Gaji)
+	s.Equal(money.MustParse("5000000.00"), consumer.Gaji)
 	s.Equal("ktp/path", consumer.FotoKTPPath)
 	s.Equal("selfie/path", consumer.FotoSelfiePath)
+	s.Equal("VERIFIED", consumer.KYCStatus)
 	s.WithinDuration(dummyTime, consumer.CreatedAt, time.Second)
 	s.WithinDuration(dummyTime, consumer.UpdatedAt, time.Second)
 
@@ -128,7 +133,7 @@ func (s *consumerRepositoryTestSuite) TestFindAndLockByNIK_NotFound() {
 	tx, err := s.DB.Begin()
 	s.Require().NoError(err)
 
-	s.Mock.ExpectQuery(`SELECT nik, phone_number, password_hash, full_name, legal_name, tempat_lahir, tanggal_lahir, gaji, foto_ktp_path, foto_selfie_path, created_at, updated_at FROM consumers WHERE nik = \$1 FOR UPDATE`).
+	s.Mock.ExpectQuery(`SELECT nik, phone_number, password_hash, full_name, legal_name, tempat_lahir, tanggal_lahir, gaji, foto_ktp_path, foto_selfie_path, kyc_status, risk_grade, created_at, updated_at FROM consumers WHERE nik = \$1 FOR UPDATE`).
 		WithArgs(nik).
 		WillReturnError(sql.ErrNoRows)
 
@@ -142,6 +147,84 @@ func (s *consumerRepositoryTestSuite) TestFindAndLockByNIK_NotFound() {
 	s.Require().NoError(err)
 }
 
+func (s *consumerRepositoryTestSuite) TestSearch_WithFilters() {
+	ctx := context.Background()
+	dummyTime := time.Now()
+	hasActive := true
+
+	rows := sqlmock.NewRows([]string{
+		"nik", "phone_number", "full_name", "kyc_status", "created_at", "has_active_contract",
+	}).AddRow(
+		"1111", "081234567890", "Budi", "VERIFIED", dummyTime, true,
+	)
+
+	s.Mock.ExpectQuery(`SELECT c.nik, c.phone_number, c.full_name, c.kyc_status, c.created_at, EXISTS \(SELECT 1 FROM transactions t WHERE t.consumer_nik = c.nik AND t.status = 'ACTIVE'\) AS has_active_contract FROM consumers c WHERE LOWER\(c.full_name\) LIKE \$1 AND c.kyc_status = \$2 AND EXISTS \(SELECT 1 FROM transactions t WHERE t.consumer_nik = c.nik AND t.status = 'ACTIVE'\) = \$3 ORDER BY c.full_name ASC, c.nik ASC LIMIT \$4`).
+		WithArgs("bu%", "VERIFIED", true, 21).
+		WillReturnRows(rows)
+
+	consumers, err := s.Repo.Search(ctx, model.ConsumerSearchFilter{
+		NamePrefix:        "Bu",
+		KYCStatus:         "VERIFIED",
+		HasActiveContract: &hasActive,
+		SortBy:            model.ConsumerSortByFullName,
+		SortOrder:         model.SortOrderAsc,
+		Limit:             21,
+	})
+
+	s.Require().NoError(err)
+	s.Require().Len(consumers, 1)
+	s.Equal("1111", consumers[0].NIK)
+	s.Equal("VERIFIED", consumers[0].KYCStatus)
+	s.True(consumers[0].HasActiveContract)
+}
+
+func (s *consumerRepositoryTestSuite) TestSearch_WithCursor() {
+	ctx := context.Background()
+
+	s.Mock.ExpectQuery(`SELECT .* FROM consumers c WHERE \(c.created_at, c.nik\) < \(\$1::timestamp, \$2\) ORDER BY c.created_at DESC, c.nik DESC LIMIT \$3`).
+		WithArgs("2025-01-01T10:00:00", "2222", 11).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"nik", "phone_number", "full_name", "kyc_status", "created_at", "has_active_contract",
+		}))
+
+	consumers, err := s.Repo.Search(ctx, model.ConsumerSearchFilter{
+		SortBy:    model.ConsumerSortByCreatedAt,
+		SortOrder: model.SortOrderDesc,
+		Limit:     11,
+		Cursor:    &pagination.Cursor{SortValue: "2025-01-01T10:00:00", Key: "2222"},
+	})
+
+	s.Require().NoError(err)
+	s.Empty(consumers)
+}
+
+func (s *consumerRepositoryTestSuite) TestSearch_EscapesNamePrefix() {
+	ctx := context.Background()
+
+	s.Mock.ExpectQuery(`SELECT .* FROM consumers c WHERE LOWER\(c.full_name\) LIKE \$1`).
+		WithArgs(`100\%\_%`, 20).
+		WillReturnError(sql.ErrConnDone)
+
+	consumers, err := s.Repo.Search(ctx, model.ConsumerSearchFilter{NamePrefix: "100%_", Limit: 20})
+
+	s.Require().Error(err)
+	s.Nil(consumers)
+}
+
+func (s *consumerRepositoryTestSuite) TestFindExistingNIKs() {
+	ctx := context.Background()
+	niks := []string{"1111", "2222", "3333"}
+
+	s.Mock.ExpectQuery(`SELECT nik FROM consumers WHERE nik = ANY\(\$1\)`).
+		WithArgs(pq.Array(niks)).
+		WillReturnRows(sqlmock.NewRows([]string{"nik"}).AddRow("1111").AddRow("3333"))
+
+	existing, err := s.Repo.FindExistingNIKs(ctx, niks)
+
+	s.Require().NoError(err)
+	s.Equal(map[string]bool{"1111": true, "3333": true}, existing)
+}
+
 func TestConsumerRepositoryTestSuite(t *testing.T) {
 	suite.Run(t, new(consumerRepositoryTestSuite))
 }
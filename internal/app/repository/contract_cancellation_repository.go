@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+)
+
+type ContractCancellationRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, cancellation *model.ContractCancellation) error
+}
+
+type contractCancellationRepository struct {
+	db *sql.DB
+}
+
+func NewContractCancellationRepository(db *sql.DB) ContractCancellationRepository {
+	return &contractCancellationRepository{db: db}
+}
+
+func (r *contractCancellationRepository) Create(ctx context.Context, tx *sql.Tx, cancellation *model.ContractCancellation) error {
+	query := `
+		INSERT INTO contract_cancellations (nomor_kontrak, reason, reversed_admin_fee, reversed_interest, released_limit, cancelled_by, cancelled_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := tx.ExecContext(ctx, query,
+		cancellation.NomorKontrak,
+		cancellation.Reason,
+		cancellation.ReversedAdminFee,
+		cancellation.ReversedInterest,
+		cancellation.ReleasedLimit,
+		cancellation.CancelledBy,
+		cancellation.CancelledAt,
+	)
+	return err
+}
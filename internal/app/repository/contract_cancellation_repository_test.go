@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/pkg/money"
+	"github.com/stretchr/testify/suite"
+)
+
+type contractCancellationRepositoryTestSuite struct {
+	suite.Suite
+	DB   *sql.DB
+	Mock sqlmock.Sqlmock
+	Repo ContractCancellationRepository
+}
+
+func (s *contractCancellationRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	s.Require().NoError(err)
+	s.DB = db
+	s.Mock = mock
+	s.Repo = NewContractCancellationRepository(db)
+}
+
+func (s *contractCancellationRepositoryTestSuite) TearDownTest() {
+	s.DB.Close()
+}
+
+func (s *contractCancellationRepositoryTestSuite) TestCreate_Success() {
+	ctx := context.Background()
+	cancelledAt := time.Now()
+
+	s.Mock.ExpectBegin()
+	tx, err := s.DB.Begin()
+	s.Require().NoError(err)
+
+	cancellation := &model.ContractCancellation{
+		NomorKontrak:     "TRX1",
+		Reason:           "bought elsewhere",
+		ReversedAdminFee: money.FromRupiah(150000),
+		ReversedInterest: money.FromRupiah(189735),
+		ReleasedLimit:    money.FromRupiah(3000000),
+		CancelledBy:      "1234567890123456",
+		CancelledAt:      cancelledAt,
+	}
+
+	s.Mock.ExpectExec(`INSERT INTO contract_cancellations \(nomor_kontrak, reason, reversed_admin_fee, reversed_interest, released_limit, cancelled_by, cancelled_at\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7\)`).
+		WithArgs("TRX1", "bought elsewhere", cancellation.ReversedAdminFee, cancellation.ReversedInterest, cancellation.ReleasedLimit, "1234567890123456", cancelledAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = s.Repo.Create(ctx, tx, cancellation)
+
+	s.Require().NoError(err)
+	s.Require().NoError(s.Mock.ExpectationsWereMet())
+}
+
+func (s *contractCancellationRepositoryTestSuite) TestCreate_Error() {
+	s.Mock.ExpectBegin()
+	tx, err := s.DB.Begin()
+	s.Require().NoError(err)
+
+	s.Mock.ExpectExec(`INSERT INTO contract_cancellations`).
+		WillReturnError(sql.ErrConnDone)
+
+	err = s.Repo.Create(context.Background(), tx, &model.ContractCancellation{NomorKontrak: "TRX1"})
+
+	s.Require().Error(err)
+	s.Equal(sql.ErrConnDone, err)
+}
+
+func TestContractCancellationRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(contractCancellationRepositoryTestSuite))
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+)
+
+type EarlySettlementRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, settlement *model.EarlySettlement) error
+	FindByNomorKontrak(ctx context.Context, nomorKontrak string) (*model.EarlySettlement, error)
+}
+
+type earlySettlementRepository struct {
+	db *sql.DB
+}
+
+func NewEarlySettlementRepository(db *sql.DB) EarlySettlementRepository {
+	return &earlySettlementRepository{db: db}
+}
+
+func (r *earlySettlementRepository) Create(ctx context.Context, tx *sql.Tx, settlement *model.EarlySettlement) error {
+	query := `
+		INSERT INTO early_settlements (nomor_kontrak, reference, remaining_principal, outstanding_penalty, outstanding_admin_fee,
+			accrued_interest, unearned_interest, interest_rebate, early_termination_fee, total_payoff, settled_by, settled_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+
+	_, err := tx.ExecContext(ctx, query,
+		settlement.NomorKontrak,
+		settlement.Reference,
+		settlement.RemainingPrincipal,
+		settlement.OutstandingPenalty,
+		settlement.OutstandingAdminFee,
+		settlement.AccruedInterest,
+		settlement.UnearnedInterest,
+		settlement.InterestRebate,
+		settlement.EarlyTerminationFee,
+		settlement.TotalPayoff,
+		settlement.SettledBy,
+		settlement.SettledAt,
+	)
+	return err
+}
+
+func (r *earlySettlementRepository) FindByNomorKontrak(ctx context.Context, nomorKontrak string) (*model.EarlySettlement, error) {
+	settlement := &model.EarlySettlement{}
+	query := `SELECT nomor_kontrak, reference, remaining_principal, outstanding_penalty, outstanding_admin_fee,
+    accrued_interest, unearned_interest, interest_rebate, early_termination_fee, total_payoff, settled_by, settled_at
+  FROM early_settlements WHERE nomor_kontrak = $1`
+
+	err := r.db.QueryRowContext(ctx, query, nomorKontrak).Scan(
+		&settlement.NomorKontrak,
+		&settlement.Reference,
+		&settlement.RemainingPrincipal,
+		&settlement.OutstandingPenalty,
+		&settlement.OutstandingAdminFee,
+		&settlement.AccruedInterest,
+		&settlement.UnearnedInterest,
+		&settlement.InterestRebate,
+		&settlement.EarlyTerminationFee,
+		&settlement.TotalPayoff,
+		&settlement.SettledBy,
+		&settlement.SettledAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	settlement.AsOf = settlement.SettledAt
+	return settlement, nil
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/pkg/money"
+	"github.com/stretchr/testify/suite"
+)
+
+type earlySettlementRepositoryTestSuite struct {
+	suite.Suite
+	DB   *sql.DB
+	Mock sqlmock.Sqlmock
+	Repo EarlySettlementRepository
+}
+
+func (s *earlySettlementRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	s.Require().NoError(err)
+	s.DB = db
+	s.Mock = mock
+	s.Repo = NewEarlySettlementRepository(db)
+}
+
+func (s *earlySettlementRepositoryTestSuite) TearDownTest() {
+	s.DB.Close()
+}
+
+func (s *earlySettlementRepositoryTestSuite) TestCreate_Success() {
+	ctx := context.Background()
+	settledAt := time.Now()
+
+	s.Mock.ExpectBegin()
+	tx, err := s.DB.Begin()
+	s.Require().NoError(err)
+
+	settlement := &model.EarlySettlement{
+		EarlySettlementQuote: model.EarlySettlementQuote{
+			NomorKontrak:        "TRX1",
+			RemainingPrincipal:  money.FromRupiah(666667),
+			OutstandingAdminFee: money.FromRupiah(20000),
+			AccruedInterest:     money.FromRupiah(12500),
+			UnearnedInterest:    money.FromRupiah(37500),
+			InterestRebate:      money.FromRupiah(37500),
+			EarlyTerminationFee: money.FromRupiah(13333),
+			TotalPayoff:         money.FromRupiah(712500),
+		},
+		Reference: "VA-0009",
+		SettledBy: "admin",
+		SettledAt: settledAt,
+	}
+
+	s.Mock.ExpectExec(`INSERT INTO early_settlements \(nomor_kontrak, reference, remaining_principal, outstanding_penalty, outstanding_admin_fee, accrued_interest, unearned_interest, interest_rebate, early_termination_fee, total_payoff, settled_by, settled_at\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8, \$9, \$10, \$11, \$12\)`).
+		WithArgs("TRX1", "VA-0009", settlement.RemainingPrincipal, money.Zero, settlement.OutstandingAdminFee, settlement.AccruedInterest,
+			settlement.UnearnedInterest, settlement.InterestRebate, settlement.EarlyTerminationFee, settlement.TotalPayoff, "admin", settledAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = s.Repo.Create(ctx, tx, settlement)
+
+	s.Require().NoError(err)
+	s.Require().NoError(s.Mock.ExpectationsWereMet())
+}
+
+func (s *earlySettlementRepositoryTestSuite) TestFindByNomorKontrak_Success() {
+	ctx := context.Background()
+	settledAt := time.Now()
+
+	rows := sqlmock.NewRows([]string{
+		"nomor_kontrak", "reference", "remaining_principal", "outstanding_penalty", "outstanding_admin_fee",
+		"accrued_interest", "unearned_interest", "interest_rebate", "early_termination_fee", "total_payoff", "settled_by", "settled_at",
+	}).AddRow("TRX1", "VA-0009", "666667.00", "0.00", "20000.00", "12500.00", "37500.00", "37500.00", "13333.00", "712500.00", "admin", settledAt)
+
+	s.Mock.ExpectQuery(`SELECT .* FROM early_settlements WHERE nomor_kontrak = \$1`).
+		WithArgs("TRX1").
+		WillReturnRows(rows)
+
+	settlement, err := s.Repo.FindByNomorKontrak(ctx, "TRX1")
+
+	s.Require().NoError(err)
+	s.Require().NotNil(settlement)
+	s.Equal(money.FromRupiah(712500), settlement.TotalPayoff)
+	s.Equal(settledAt, settlement.AsOf)
+}
+
+func (s *earlySettlementRepositoryTestSuite) TestFindByNomorKontrak_NotFound() {
+	s.Mock.ExpectQuery(`SELECT .* FROM early_settlements WHERE nomor_kontrak = \$1`).
+		WithArgs("TRX1").
+		WillReturnError(sql.ErrNoRows)
+
+	settlement, err := s.Repo.FindByNomorKontrak(context.Background(), "TRX1")
+
+	s.Require().NoError(err)
+	s.Nil(settlement)
+}
+
+func TestEarlySettlementRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(earlySettlementRepositoryTestSuite))
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+)
+
+type IdempotencyKeyRepository interface {
+	Claim(ctx context.Context, tx *sql.Tx, owner string, key string, requestHash string) (bool, error)
+	FindByKey(ctx context.Context, tx *sql.Tx, owner string, key string) (*model.IdempotencyKey, error)
+	SaveResponse(ctx context.Context, tx *sql.Tx, owner string, key string, responseBody json.RawMessage) error
+}
+
+type idempotencyKeyRepository struct {
+	db *sql.DB
+}
+
+func NewIdempotencyKeyRepository(db *sql.DB) IdempotencyKeyRepository {
+	return &idempotencyKeyRepository{db: db}
+}
+
+// Claim records the key and reports whether it was new. While another
+// transaction holds an uncommitted claim on the same key, Claim waits for it
+// to finish.
+func (r *idempotencyKeyRepository) Claim(ctx context.Context, tx *sql.Tx, owner string, key string, requestHash string) (bool, error) {
+	query := `
+		INSERT INTO idempotency_keys (owner, idempotency_key, request_hash)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (owner, idempotency_key) DO NOTHING
+	`
+
+	result, err := tx.ExecContext(ctx, query, owner, key, requestHash)
+	if err != nil {
+		return false, err
+	}
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return inserted == 1, nil
+}
+
+func (r *idempotencyKeyRepository) FindByKey(ctx context.Context, tx *sql.Tx, owner string, key string) (*model.IdempotencyKey, error) {
+	query := `SELECT owner, idempotency_key, request_hash, response_body, created_at
+  FROM idempotency_keys WHERE owner = $1 AND idempotency_key = $2`
+
+	idempotencyKey := &model.IdempotencyKey{}
+	var responseBody []byte
+	err := tx.QueryRowContext(ctx, query, owner, key).Scan(&idempotencyKey.Owner, &idempotencyKey.Key, &idempotencyKey.RequestHash, &responseBody, &idempotencyKey.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	idempotencyKey.ResponseBody = responseBody
+	return idempotencyKey, nil
+}
+
+func (r *idempotencyKeyRepository) SaveResponse(ctx context.Context, tx *sql.Tx, owner string, key string, responseBody json.RawMessage) error {
+	query := `UPDATE idempotency_keys SET response_body = $1 WHERE owner = $2 AND idempotency_key = $3`
+
+	_, err := tx.ExecContext(ctx, query, []byte(responseBody), owner, key)
+	return err
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+)
+
+type idempotencyKeyRepositoryTestSuite struct {
+	suite.Suite
+	DB   *sql.DB
+	Mock sqlmock.Sqlmock
+	Repo IdempotencyKeyRepository
+}
+
+func (s *idempotencyKeyRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	s.Require().NoError(err)
+	s.DB = db
+	s.Mock = mock
+	s.Repo = NewIdempotencyKeyRepository(db)
+}
+
+func (s *idempotencyKeyRepositoryTestSuite) TearDownTest() {
+	s.DB.Close()
+}
+
+func (s *idempotencyKeyRepositoryTestSuite) begin() *sql.Tx {
+	s.Mock.ExpectBegin()
+	tx, err := s.DB.Begin()
+	s.Require().NoError(err)
+	return tx
+}
+
+func (s *idempotencyKeyRepositoryTestSuite) TestClaim_New() {
+	ctx := context.Background()
+	tx := s.begin()
+
+	s.Mock.ExpectExec(`INSERT INTO idempotency_keys \(owner, idempotency_key, request_hash\) VALUES \(\$1, \$2, \$3\) ON CONFLICT \(owner, idempotency_key\) DO NOTHING`).
+		WithArgs("081234567890", "key-1", "abc").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	claimed, err := s.Repo.Claim(ctx, tx, "081234567890", "key-1", "abc")
+
+	s.Require().NoError(err)
+	s.True(claimed)
+}
+
+func (s *idempotencyKeyRepositoryTestSuite) TestClaim_AlreadyUsed() {
+	ctx := context.Background()
+	tx := s.begin()
+
+	s.Mock.ExpectExec(`INSERT INTO idempotency_keys`).
+		WithArgs("081234567890", "key-1", "abc").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	claimed, err := s.Repo.Claim(ctx, tx, "081234567890", "key-1", "abc")
+
+	s.Require().NoError(err)
+	s.False(claimed)
+}
+
+func (s *idempotencyKeyRepositoryTestSuite) TestFindByKey_Success() {
+	ctx := context.Background()
+	now := time.Now()
+	tx := s.begin()
+
+	s.Mock.ExpectQuery(`SELECT owner, idempotency_key, request_hash, response_body, created_at FROM idempotency_keys WHERE owner = \$1 AND idempotency_key = \$2`).
+		WithArgs("081234567890", "key-1").
+		WillReturnRows(sqlmock.NewRows([]string{"owner", "idempotency_key", "request_hash", "response_body", "created_at"}).
+			AddRow("081234567890", "key-1", "abc", []byte(`{"nomor_kontrak":"TRX1"}`), now))
+
+	idempotencyKey, err := s.Repo.FindByKey(ctx, tx, "081234567890", "key-1")
+
+	s.Require().NoError(err)
+	s.Require().NotNil(idempotencyKey)
+	s.Equal("abc", idempotencyKey.RequestHash)
+	s.JSONEq(`{"nomor_kontrak":"TRX1"}`, string(idempotencyKey.ResponseBody))
+}
+
+func (s *idempotencyKeyRepositoryTestSuite) TestFindByKey_NotFound() {
+	ctx := context.Background()
+	tx := s.begin()
+
+	s.Mock.ExpectQuery(`SELECT .* FROM idempotency_keys`).
+		WithArgs("081234567890", "key-1").
+		WillReturnError(sql.ErrNoRows)
+
+	idempotencyKey, err := s.Repo.FindByKey(ctx, tx, "081234567890", "key-1")
+
+	s.Require().NoError(err)
+	s.Nil(idempotencyKey)
+}
+
+func (s *idempotencyKeyRepositoryTestSuite) TestSaveResponse_Success() {
+	ctx := context.Background()
+	tx := s.begin()
+	body := json.RawMessage(`{"nomor_kontrak":"TRX1"}`)
+
+	s.Mock.ExpectExec(`UPDATE idempotency_keys SET response_body = \$1 WHERE owner = \$2 AND idempotency_key = \$3`).
+		WithArgs([]byte(body), "081234567890", "key-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := s.Repo.SaveResponse(ctx, tx, "081234567890", "key-1", body)
+
+	s.Require().NoError(err)
+	s.Require().NoError(s.Mock.ExpectationsWereMet())
+}
+
+func TestIdempotencyKeyRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(idempotencyKeyRepositoryTestSuite))
+}
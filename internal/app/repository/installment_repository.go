@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+)
+
+type InstallmentRepository interface {
+	SaveAll(ctx context.Context, tx *sql.Tx, installments []model.Installment) error
+	FindByNomorKontrak(ctx context.Context, nomorKontrak string) ([]model.Installment, error)
+	FindAndLockByNomorKontrak(ctx context.Context, tx *sql.Tx, nomorKontrak string) ([]model.Installment, error)
+	UpdatePaid(ctx context.Context, tx *sql.Tx, installment *model.Installment) error
+	SettleOutstanding(ctx context.Context, tx *sql.Tx, nomorKontrak string, settledAt time.Time) error
+	CancelOutstanding(ctx context.Context, tx *sql.Tx, nomorKontrak string) error
+	UpdatePenalty(ctx context.Context, tx *sql.Tx, installment *model.Installment) error
+	FindNomorKontrakWithOverdue(ctx context.Context, dueBefore time.Time) ([]string, error)
+}
+
+type installmentRepository struct {
+	db *sql.DB
+}
+
+func NewInstallmentRepository(db *sql.DB) InstallmentRepository {
+	return &installmentRepository{db: db}
+}
+
+func (r *installmentRepository) SaveAll(ctx context.Context, tx *sql.Tx, installments []model.Installment) error {
+	query := `
+		INSERT INTO installments (nomor_kontrak, installment_number, due_date, principal, interest, admin_fee, total_amount)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	for _, installment := range installments {
+		_, err := tx.ExecContext(ctx, query,
+			installment.NomorKontrak,
+			installment.InstallmentNumber,
+			installment.DueDate,
+			installment.Principal,
+			installment.Interest,
+			installment.AdminFee,
+			installment.TotalAmount,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+const installmentColumns = `nomor_kontrak, installment_number, due_date, principal, interest, admin_fee, total_amount,
+  penalty, paid_principal, paid_interest, paid_admin_fee, paid_penalty, status, paid_at, overdue_since, penalty_accrued_to`
+
+func (r *installmentRepository) FindByNomorKontrak(ctx context.Context, nomorKontrak string) ([]model.Installment, error) {
+	query := `SELECT ` + installmentColumns + ` FROM installments WHERE nomor_kontrak = $1 ORDER BY installment_number ASC`
+
+	return queryInstallments(ctx, r.db, query, nomorKontrak)
+}
+
+// FindAndLockByNomorKontrak locks the whole schedule so payments against the
+// same contract are allocated one after another.
+func (r *installmentRepository) FindAndLockByNomorKontrak(ctx context.Context, tx *sql.Tx, nomorKontrak string) ([]model.Installment, error) {
+	query := `SELECT ` + installmentColumns + ` FROM installments WHERE nomor_kontrak = $1 ORDER BY installment_number ASC FOR UPDATE`
+
+	return queryInstallments(ctx, tx, query, nomorKontrak)
+}
+
+func (r *installmentRepository) UpdatePaid(ctx context.Context, tx *sql.Tx, installment *model.Installment) error {
+	query := `
+		UPDATE installments
+		SET paid_principal = $1, paid_interest = $2, paid_admin_fee = $3, paid_penalty = $4, status = $5, paid_at = $6
+		WHERE nomor_kontrak = $7 AND installment_number = $8
+	`
+
+	_, err := tx.ExecContext(ctx, query,
+		installment.PaidPrincipal,
+		installment.PaidInterest,
+		installment.PaidAdminFee,
+		installment.PaidPenalty,
+		installment.Status,
+		installment.PaidAt,
+		installment.NomorKontrak,
+		installment.InstallmentNumber,
+	)
+	return err
+}
+
+// SettleOutstanding closes every installment not yet paid, as part of an
+// early settlement.
+func (r *installmentRepository) SettleOutstanding(ctx context.Context, tx *sql.Tx, nomorKontrak string, settledAt time.Time) error {
+	query := `UPDATE installments SET status = 'SETTLED', paid_at = $1 WHERE nomor_kontrak = $2 AND status <> 'PAID'`
+
+	_, err := tx.ExecContext(ctx, query, settledAt, nomorKontrak)
+	return err
+}
+
+// CancelOutstanding closes every installment not yet paid, as part of a
+// cancellation.
+func (r *installmentRepository) CancelOutstanding(ctx context.Context, tx *sql.Tx, nomorKontrak string) error {
+	query := `UPDATE installments SET status = 'CANCELLED' WHERE nomor_kontrak = $1 AND status <> 'PAID'`
+
+	_, err := tx.ExecContext(ctx, query, nomorKontrak)
+	return err
+}
+
+func (r *installmentRepository) UpdatePenalty(ctx context.Context, tx *sql.Tx, installment *model.Installment) error {
+	query := `
+		UPDATE installments
+		SET penalty = $1, overdue_since = $2, penalty_accrued_to = $3
+		WHERE nomor_kontrak = $4 AND installment_number = $5
+	`
+
+	_, err := tx.ExecContext(ctx, query,
+		installment.Penalty,
+		installment.OverdueSince,
+		installment.PenaltyAccruedTo,
+		installment.NomorKontrak,
+		installment.InstallmentNumber,
+	)
+	return err
+}
+
+// FindNomorKontrakWithOverdue lists the ACTIVE and DEFAULTED contracts with
+// an open installment due before dueBefore. Grace periods are left to the
+// caller since they differ per product.
+func (r *installmentRepository) FindNomorKontrakWithOverdue(ctx context.Context, dueBefore time.Time) ([]string, error) {
+	query := `
+		SELECT DISTINCT i.nomor_kontrak
+		FROM installments i
+		JOIN transactions t ON t.nomor_kontrak = i.nomor_kontrak
+		WHERE i.status IN ('UNPAID', 'PARTIAL') AND i.due_date < $1 AND t.status IN ('ACTIVE', 'DEFAULTED')
+		ORDER BY i.nomor_kontrak ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, dueBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var nomorKontraks []string
+	for rows.Next() {
+		var nomorKontrak string
+		if err := rows.Scan(&nomorKontrak); err != nil {
+			return nil, err
+		}
+		nomorKontraks = append(nomorKontraks, nomorKontrak)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return nomorKontraks, nil
+}
+
+func queryInstallments(ctx context.Context, q queryer, query string, args ...interface{}) ([]model.Installment, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var installments []model.Installment
+	for rows.Next() {
+		installment := model.Installment{}
+		var paidAt, overdueSince, penaltyAccruedTo sql.NullTime
+		if err := rows.Scan(&installment.NomorKontrak, &installment.InstallmentNumber, &installment.DueDate, &installment.Principal, &installment.Interest, &installment.AdminFee, &installment.TotalAmount,
+			&installment.Penalty, &installment.PaidPrincipal, &installment.PaidInterest, &installment.PaidAdminFee, &installment.PaidPenalty, &installment.Status, &paidAt,
+			&overdueSince, &penaltyAccruedTo); err != nil {
+			return nil, err
+		}
+		if paidAt.Valid {
+			installment.PaidAt = &paidAt.Time
+		}
+		if overdueSince.Valid {
+			installment.OverdueSince = &overdueSince.Time
+		}
+		if penaltyAccruedTo.Valid {
+			installment.PenaltyAccruedTo = &penaltyAccruedTo.Time
+		}
+		installments = append(installments, installment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return installments, nil
+}
@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/pkg/money"
+	"github.com/stretchr/testify/suite"
+)
+
+type installmentRepositoryTestSuite struct {
+	suite.Suite
+	DB   *sql.DB
+	Mock sqlmock.Sqlmock
+	Repo InstallmentRepository
+}
+
+func (s *installmentRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	s.Require().NoError(err)
+	s.DB = db
+	s.Mock = mock
+	s.Repo = NewInstallmentRepository(db)
+}
+
+func (s *installmentRepositoryTestSuite) TearDownTest() {
+	s.DB.Close()
+}
+
+func (s *installmentRepositoryTestSuite) TestSaveAll_Success() {
+	ctx := context.Background()
+	dueDate := time.Date(2025, 4, 10, 0, 0, 0, 0, time.UTC)
+
+	s.Mock.ExpectBegin()
+	tx, err := s.DB.Begin()
+	s.Require().NoError(err)
+
+	installments := []model.Installment{
+		{NomorKontrak: "TRX1", InstallmentNumber: 1, DueDate: dueDate, Principal: money.FromRupiah(500000), Interest: money.FromRupiah(12500), AdminFee: money.FromRupiah(7500), TotalAmount: money.FromRupiah(520000)},
+		{NomorKontrak: "TRX1", InstallmentNumber: 2, DueDate: dueDate.AddDate(0, 1, 0), Principal: money.FromRupiah(500000), Interest: money.FromRupiah(12500), AdminFee: money.FromRupiah(7500), TotalAmount: money.FromRupiah(520000)},
+	}
+
+	query := `INSERT INTO installments \(nomor_kontrak, installment_number, due_date, principal, interest, admin_fee, total_amount\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7\)`
+	for _, installment := range installments {
+		s.Mock.ExpectExec(query).
+			WithArgs("TRX1", installment.InstallmentNumber, installment.DueDate, installment.Principal, installment.Interest, installment.AdminFee, installment.TotalAmount).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+
+	err = s.Repo.SaveAll(ctx, tx, installments)
+
+	s.Require().NoError(err)
+	s.Require().NoError(s.Mock.ExpectationsWereMet())
+}
+
+func (s *installmentRepositoryTestSuite) TestSaveAll_Error() {
+	ctx := context.Background()
+
+	s.Mock.ExpectBegin()
+	tx, err := s.DB.Begin()
+	s.Require().NoError(err)
+
+	s.Mock.ExpectExec(`INSERT INTO installments`).
+		WillReturnError(sql.ErrConnDone)
+
+	err = s.Repo.SaveAll(ctx, tx, []model.Installment{{NomorKontrak: "TRX1", InstallmentNumber: 1}})
+
+	s.Require().Error(err)
+	s.Equal(sql.ErrConnDone, err)
+}
+
+func (s *installmentRepositoryTestSuite) TestFindByNomorKontrak_Success() {
+	ctx := context.Background()
+	dueDate := time.Date(2025, 4, 10, 0, 0, 0, 0, time.UTC)
+	paidAt := dueDate.Add(-time.Hour)
+	overdueSince := dueDate.AddDate(0, 1, 4)
+	accruedTo := dueDate.AddDate(0, 1, 5)
+
+	rows := sqlmock.NewRows([]string{
+		"nomor_kontrak", "installment_number", "due_date", "principal", "interest", "admin_fee", "total_amount",
+		"penalty", "paid_principal", "paid_interest", "paid_admin_fee", "paid_penalty", "status", "paid_at",
+		"overdue_since", "penalty_accrued_to",
+	}).
+		AddRow("TRX1", 1, dueDate, "333333.00", "25000.00", "10000.00", "368333.00", "0.00", "333333.00", "25000.00", "10000.00", "0.00", "PAID", paidAt, nil, nil).
+		AddRow("TRX1", 2, dueDate.AddDate(0, 1, 0), "333334.00", "25000.00", "10000.00", "368334.00", "1500.00", "0.00", "5000.00", "10000.00", "0.00", "PARTIAL", nil, overdueSince, accruedTo)
+
+	s.Mock.ExpectQuery(`SELECT nomor_kontrak, installment_number, due_date, principal, interest, admin_fee, total_amount, penalty, paid_principal, paid_interest, paid_admin_fee, paid_penalty, status, paid_at, overdue_since, penalty_accrued_to FROM installments WHERE nomor_kontrak = \$1 ORDER BY installment_number ASC`).
+		WithArgs("TRX1").
+		WillReturnRows(rows)
+
+	installments, err := s.Repo.FindByNomorKontrak(ctx, "TRX1")
+
+	s.Require().NoError(err)
+	s.Require().Len(installments, 2)
+	s.Equal(money.FromRupiah(333334), installments[1].Principal)
+	s.Equal(money.FromRupiah(368334), installments[1].TotalAmount)
+	s.Require().NotNil(installments[0].PaidAt)
+	s.Equal(paidAt, *installments[0].PaidAt)
+	s.Equal(model.InstallmentStatusPartial, installments[1].Status)
+	s.Equal(money.FromRupiah(5000), installments[1].PaidInterest)
+	s.Nil(installments[1].PaidAt)
+	s.Nil(installments[0].OverdueSince)
+	s.Require().NotNil(installments[1].OverdueSince)
+	s.Equal(overdueSince, *installments[1].OverdueSince)
+	s.Require().NotNil(installments[1].PenaltyAccruedTo)
+	s.Equal(accruedTo, *installments[1].PenaltyAccruedTo)
+	s.Equal(money.FromRupiah(1500), installments[1].Penalty)
+}
+
+func (s *installmentRepositoryTestSuite) TestUpdatePaid_Success() {
+	ctx := context.Background()
+	paidAt := time.Date(2025, 4, 9, 10, 0, 0, 0, time.UTC)
+
+	s.Mock.ExpectBegin()
+	tx, err := s.DB.Begin()
+	s.Require().NoError(err)
+
+	installment := &model.Installment{
+		NomorKontrak:      "TRX1",
+		InstallmentNumber: 1,
+		PaidPrincipal:     money.FromRupiah(333333),
+		PaidInterest:      money.FromRupiah(25000),
+		PaidAdminFee:      money.FromRupiah(10000),
+		Status:            model.InstallmentStatusPaid,
+		PaidAt:            &paidAt,
+	}
+
+	s.Mock.ExpectExec(`UPDATE installments SET paid_principal = \$1, paid_interest = \$2, paid_admin_fee = \$3, paid_penalty = \$4, status = \$5, paid_at = \$6 WHERE nomor_kontrak = \$7 AND installment_number = \$8`).
+		WithArgs(installment.PaidPrincipal, installment.PaidInterest, installment.PaidAdminFee, installment.PaidPenalty, "PAID", installment.PaidAt, "TRX1", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = s.Repo.UpdatePaid(ctx, tx, installment)
+
+	s.Require().NoError(err)
+	s.Require().NoError(s.Mock.ExpectationsWereMet())
+}
+
+func (s *installmentRepositoryTestSuite) TestSettleOutstanding_Success() {
+	ctx := context.Background()
+	settledAt := time.Date(2025, 2, 24, 15, 0, 0, 0, time.UTC)
+
+	s.Mock.ExpectBegin()
+	tx, err := s.DB.Begin()
+	s.Require().NoError(err)
+
+	s.Mock.ExpectExec(`UPDATE installments SET status = 'SETTLED', paid_at = \$1 WHERE nomor_kontrak = \$2 AND status <> 'PAID'`).
+		WithArgs(settledAt, "TRX1").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err = s.Repo.SettleOutstanding(ctx, tx, "TRX1", settledAt)
+
+	s.Require().NoError(err)
+	s.Require().NoError(s.Mock.ExpectationsWereMet())
+}
+
+func (s *installmentRepositoryTestSuite) TestCancelOutstanding_Success() {
+	ctx := context.Background()
+
+	s.Mock.ExpectBegin()
+	tx, err := s.DB.Begin()
+	s.Require().NoError(err)
+
+	s.Mock.ExpectExec(`UPDATE installments SET status = 'CANCELLED' WHERE nomor_kontrak = \$1 AND status <> 'PAID'`).
+		WithArgs("TRX1").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	err = s.Repo.CancelOutstanding(ctx, tx, "TRX1")
+
+	s.Require().NoError(err)
+	s.Require().NoError(s.Mock.ExpectationsWereMet())
+}
+
+func (s *installmentRepositoryTestSuite) TestUpdatePenalty_Success() {
+	ctx := context.Background()
+	overdueSince := time.Date(2025, 4, 14, 0, 0, 0, 0, time.UTC)
+	accruedTo := time.Date(2025, 4, 15, 0, 0, 0, 0, time.UTC)
+
+	s.Mock.ExpectBegin()
+	tx, err := s.DB.Begin()
+	s.Require().NoError(err)
+
+	installment := &model.Installment{
+		NomorKontrak:      "TRX1",
+		InstallmentNumber: 1,
+		Penalty:           money.FromRupiah(1842),
+		OverdueSince:      &overdueSince,
+		PenaltyAccruedTo:  &accruedTo,
+	}
+
+	s.Mock.ExpectExec(`UPDATE installments SET penalty = \$1, overdue_since = \$2, penalty_accrued_to = \$3 WHERE nomor_kontrak = \$4 AND installment_number = \$5`).
+		WithArgs(installment.Penalty, installment.OverdueSince, installment.PenaltyAccruedTo, "TRX1", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = s.Repo.UpdatePenalty(ctx, tx, installment)
+
+	s.Require().NoError(err)
+	s.Require().NoError(s.Mock.ExpectationsWereMet())
+}
+
+func (s *installmentRepositoryTestSuite) TestFindNomorKontrakWithOverdue_Success() {
+	ctx := context.Background()
+	dueBefore := time.Date(2025, 4, 15, 0, 0, 0, 0, time.UTC)
+
+	s.Mock.ExpectQuery(`SELECT DISTINCT i.nomor_kontrak FROM installments i JOIN transactions t ON t.nomor_kontrak = i.nomor_kontrak WHERE i.status IN \('UNPAID', 'PARTIAL'\) AND i.due_date < \$1 AND t.status IN \('ACTIVE', 'DEFAULTED'\)`).
+		WithArgs(dueBefore).
+		WillReturnRows(sqlmock.NewRows([]string{"nomor_kontrak"}).AddRow("TRX1").AddRow("TRX2"))
+
+	nomorKontraks, err := s.Repo.FindNomorKontrakWithOverdue(ctx, dueBefore)
+
+	s.Require().NoError(err)
+	s.Equal([]string{"TRX1", "TRX2"}, nomorKontraks)
+}
+
+func TestInstallmentRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(installmentRepositoryTestSuite))
+}
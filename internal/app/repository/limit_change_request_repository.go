@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/pkg/money"
+)
+
+type LimitChangeRequestRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, request *model.LimitChangeRequest) error
+	FindByID(ctx context.Context, id int64) (*model.LimitChangeRequest, error)
+	FindAndLockByID(ctx context.Context, tx *sql.Tx, id int64) (*model.LimitChangeRequest, error)
+	FindByStatus(ctx context.Context, status string) ([]model.LimitChangeRequest, error)
+	UpdateDecision(ctx context.Context, tx *sql.Tx, request *model.LimitChangeRequest) error
+}
+
+type limitChangeRequestRepository struct {
+	db *sql.DB
+}
+
+func NewLimitChangeRequestRepository(db *sql.DB) LimitChangeRequestRepository {
+	return &limitChangeRequestRepository{db: db}
+}
+
+const limitChangeRequestColumns = `id, consumer_nik, reason, source, status, requested_by, decided_by, decision_note, decided_at, created_at, updated_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanLimitChangeRequest(row rowScanner) (*model.LimitChangeRequest, error) {
+	request := &model.LimitChangeRequest{}
+	var (
+		decidedBy    sql.NullString
+		decisionNote sql.NullString
+		decidedAt    sql.NullTime
+	)
+	err := row.Scan(&request.ID, &request.ConsumerNIK, &request.Reason, &request.Source, &request.Status, &request.RequestedBy, &decidedBy, &decisionNote, &decidedAt, &request.CreatedAt, &request.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if decidedBy.Valid {
+		request.DecidedBy = &decidedBy.String
+	}
+	if decisionNote.Valid {
+		request.DecisionNote = &decisionNote.String
+	}
+	if decidedAt.Valid {
+		request.DecidedAt = &decidedAt.Time
+	}
+	return request, nil
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func findLimitChangeRequestItems(ctx context.Context, q queryer, requestID int64) ([]model.LimitChangeRequestItem, error) {
+	query := `SELECT tenor, current_amount, requested_amount
+  FROM limit_change_request_items WHERE request_id = $1 ORDER BY tenor ASC`
+
+	rows, err := q.QueryContext(ctx, query, requestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []model.LimitChangeRequestItem
+	for rows.Next() {
+		item := model.LimitChangeRequestItem{}
+		var currentAmount sql.Null[money.Amount]
+		if err := rows.Scan(&item.Tenor, &currentAmount, &item.RequestedAmount); err != nil {
+			return nil, err
+		}
+		if currentAmount.Valid {
+			item.CurrentAmount = &currentAmount.V
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (r *limitChangeRequestRepository) Create(ctx context.Context, tx *sql.Tx, request *model.LimitChangeRequest) error {
+	query := `
+		INSERT INTO limit_change_requests (consumer_nik, reason, source, status, requested_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := tx.QueryRowContext(ctx, query,
+		request.ConsumerNIK,
+		request.Reason,
+		request.Source,
+		request.Status,
+		request.RequestedBy,
+	).Scan(&request.ID, &request.CreatedAt, &request.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	itemQuery := `
+		INSERT INTO limit_change_request_items (request_id, tenor, current_amount, requested_amount)
+		VALUES ($1, $2, $3, $4)
+	`
+	for _, item := range request.Items {
+		_, err := tx.ExecContext(ctx, itemQuery, request.ID, item.Tenor, item.CurrentAmount, item.RequestedAmount)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *limitChangeRequestRepository) FindByID(ctx context.Context, id int64) (*model.LimitChangeRequest, error) {
+	query := `SELECT ` + limitChangeRequestColumns + ` FROM limit_change_requests WHERE id = $1`
+
+	request, err := scanLimitChangeRequest(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	request.Items, err = findLimitChangeRequestItems(ctx, r.db, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+func (r *limitChangeRequestRepository) FindAndLockByID(ctx context.Context, tx *sql.Tx, id int64) (*model.LimitChangeRequest, error) {
+	query := `SELECT ` + limitChangeRequestColumns + ` FROM limit_change_requests WHERE id = $1 FOR UPDATE`
+
+	request, err := scanLimitChangeRequest(tx.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	request.Items, err = findLimitChangeRequestItems(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+func (r *limitChangeRequestRepository) FindByStatus(ctx context.Context, status string) ([]model.LimitChangeRequest, error) {
+	query := `SELECT ` + limitChangeRequestColumns + ` FROM limit_change_requests
+  WHERE ($1 = '' OR status = $1) ORDER BY created_at ASC, id ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	requests := []model.LimitChangeRequest{}
+	for rows.Next() {
+		request, err := scanLimitChangeRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, *request)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
+
+func (r *limitChangeRequestRepository) UpdateDecision(ctx context.Context, tx *sql.Tx, request *model.LimitChangeRequest) error {
+	query := `
+		UPDATE limit_change_requests
+		SET status = $1, decided_by = $2, decision_note = $3, decided_at = $4
+		WHERE id = $5
+	`
+
+	_, err := tx.ExecContext(ctx, query,
+		request.Status,
+		request.DecidedBy,
+		request.DecisionNote,
+		request.DecidedAt,
+		request.ID,
+	)
+	return err
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/pkg/money"
+	"github.com/stretchr/testify/suite"
+)
+
+type limitChangeRequestRepositoryTestSuite struct {
+	suite.Suite
+	DB   *sql.DB
+	Mock sqlmock.Sqlmock
+	Repo LimitChangeRequestRepository
+}
+
+func (s *limitChangeRequestRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	s.Require().NoError(err)
+	s.DB = db
+	s.Mock = mock
+	s.Repo = NewLimitChangeRequestRepository(db)
+}
+
+func (s *limitChangeRequestRepositoryTestSuite) TearDownTest() {
+	s.DB.Close()
+}
+
+func (s *limitChangeRequestRepositoryTestSuite) TestCreate_Success() {
+	ctx := context.Background()
+	dummyTime := time.Now()
+	currentAmount := money.MustParse("100000.00")
+
+	s.Mock.ExpectBegin()
+	tx, err := s.DB.Begin()
+	s.Require().NoError(err)
+
+	request := &model.LimitChangeRequest{
+		ConsumerNIK: "1111",
+		Reason:      "salary increase",
+		Source:      model.LimitSourceManual,
+		Status:      model.LimitChangeStatusPending,
+		RequestedBy: "analyst",
+		Items: []model.LimitChangeRequestItem{
+			{Tenor: 1, CurrentAmount: &currentAmount, RequestedAmount: money.MustParse("300000.00")},
+			{Tenor: 12, RequestedAmount: money.MustParse("2000000.00")},
+		},
+	}
+
+	s.Mock.ExpectQuery(`INSERT INTO limit_change_requests \(consumer_nik, reason, source, status, requested_by\) VALUES \(\$1, \$2, \$3, \$4, \$5\) RETURNING id, created_at, updated_at`).
+		WithArgs("1111", "salary increase", model.LimitSourceManual, model.LimitChangeStatusPending, "analyst").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(7, dummyTime, dummyTime))
+	s.Mock.ExpectExec(`INSERT INTO limit_change_request_items \(request_id, tenor, current_amount, requested_amount\) VALUES \(\$1, \$2, \$3, \$4\)`).
+		WithArgs(int64(7), 1, currentAmount, money.MustParse("300000.00")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.Mock.ExpectExec(`INSERT INTO limit_change_request_items \(request_id, tenor, current_amount, requested_amount\) VALUES \(\$1, \$2, \$3, \$4\)`).
+		WithArgs(int64(7), 12, nil, money.MustParse("2000000.00")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = s.Repo.Create(ctx, tx, request)
+	s.Require().NoError(err)
+	s.Equal(int64(7), request.ID)
+
+	s.Mock.ExpectCommit()
+	err = tx.Commit()
+	s.Require().NoError(err)
+}
+
+func (s *limitChangeRequestRepositoryTestSuite) TestFindByID_Success() {
+	ctx := context.Background()
+	dummyTime := time.Now()
+
+	s.Mock.ExpectQuery(`SELECT id, consumer_nik, reason, source, status, requested_by, decided_by, decision_note, decided_at, created_at, updated_at FROM limit_change_requests WHERE id = \$1`).
+		WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "consumer_nik", "reason", "source", "status", "requested_by", "decided_by", "decision_note", "decided_at", "created_at", "updated_at",
+		}).AddRow(
+			7, "1111", "salary increase", "PROMOTION", "APPROVED", "analyst", "admin", "ok", dummyTime, dummyTime, dummyTime,
+		))
+	s.Mock.ExpectQuery(`SELECT tenor, current_amount, requested_amount FROM limit_change_request_items WHERE request_id = \$1 ORDER BY tenor ASC`).
+		WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"tenor", "current_amount", "requested_amount"}).
+			AddRow(1, 100000.00, 300000.00).
+			AddRow(12, nil, 2000000.00))
+
+	request, err := s.Repo.FindByID(ctx, 7)
+
+	s.Require().NoError(err)
+	s.Require().NotNil(request)
+	s.Equal("APPROVED", request.Status)
+	s.Equal(model.LimitSourcePromotion, request.Source)
+	s.Require().NotNil(request.DecidedBy)
+	s.Equal("admin", *request.DecidedBy)
+	s.Require().Len(request.Items, 2)
+	s.Require().NotNil(request.Items[0].CurrentAmount)
+	s.Equal(money.MustParse("100000.00"), *request.Items[0].CurrentAmount)
+	s.Nil(request.Items[1].CurrentAmount)
+}
+
+func (s *limitChangeRequestRepositoryTestSuite) TestFindByID_NotFound() {
+	s.Mock.ExpectQuery(`SELECT .* FROM limit_change_requests WHERE id = \$1`).
+		WithArgs(int64(99)).
+		WillReturnError(sql.ErrNoRows)
+
+	request, err := s.Repo.FindByID(context.Background(), 99)
+
+	s.Require().NoError(err)
+	s.Nil(request)
+}
+
+func (s *limitChangeRequestRepositoryTestSuite) TestUpdateDecision_Success() {
+	ctx := context.Background()
+	decidedBy := "admin"
+	decidedAt := time.Now()
+
+	s.Mock.ExpectBegin()
+	tx, err := s.DB.Begin()
+	s.Require().NoError(err)
+
+	request := &model.LimitChangeRequest{
+		ID:        7,
+		Status:    model.LimitChangeStatusRejected,
+		DecidedBy: &decidedBy,
+		DecidedAt: &decidedAt,
+	}
+
+	s.Mock.ExpectExec(`UPDATE limit_change_requests SET status = \$1, decided_by = \$2, decision_note = \$3, decided_at = \$4 WHERE id = \$5`).
+		WithArgs(model.LimitChangeStatusRejected, decidedBy, nil, decidedAt, int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = s.Repo.UpdateDecision(ctx, tx, request)
+	s.Require().NoError(err)
+
+	s.Mock.ExpectCommit()
+	err = tx.Commit()
+	s.Require().NoError(err)
+}
+
+func TestLimitChangeRequestRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(limitChangeRequestRepositoryTestSuite))
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+)
+
+type LimitHoldRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, hold *model.LimitHold, ttl time.Duration) error
+	FindByID(ctx context.Context, id int64) (*model.LimitHold, error)
+	FindAndLockByID(ctx context.Context, tx *sql.Tx, id int64) (*model.LimitHold, error)
+	UpdateStatus(ctx context.Context, tx *sql.Tx, hold *model.LimitHold) error
+	GetHeldByNIK(ctx context.Context, tx *sql.Tx, nik string) ([]model.TenorUsage, error)
+	ExpireDue(ctx context.Context) (int64, error)
+}
+
+type limitHoldRepository struct {
+	db *sql.DB
+}
+
+func NewLimitHoldRepository(db *sql.DB) LimitHoldRepository {
+	return &limitHoldRepository{db: db}
+}
+
+const limitHoldColumns = `id, consumer_nik, merchant_id, product_code, tenor, amount, reference, status, nomor_kontrak, expires_at,
+  (status = 'ACTIVE' AND expires_at <= NOW()) AS expired, created_at, updated_at`
+
+func scanLimitHold(row rowScanner) (*model.LimitHold, error) {
+	hold := &model.LimitHold{}
+	var merchantID sql.NullInt64
+	var nomorKontrak sql.NullString
+	err := row.Scan(&hold.ID, &hold.ConsumerNIK, &merchantID, &hold.ProductCode, &hold.Tenor, &hold.Amount, &hold.Reference, &hold.Status, &nomorKontrak, &hold.ExpiresAt, &hold.Expired, &hold.CreatedAt, &hold.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if merchantID.Valid {
+		hold.MerchantID = &merchantID.Int64
+	}
+	if nomorKontrak.Valid {
+		hold.NomorKontrak = &nomorKontrak.String
+	}
+	return hold, nil
+}
+
+// Create stores the hold with an expiry computed by the database clock, the
+// same clock the expiry checks compare against.
+func (r *limitHoldRepository) Create(ctx context.Context, tx *sql.Tx, hold *model.LimitHold, ttl time.Duration) error {
+	query := `
+		INSERT INTO limit_holds (consumer_nik, merchant_id, product_code, tenor, amount, reference, status, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW() + make_interval(secs => $8))
+		RETURNING id, expires_at, created_at, updated_at
+	`
+
+	return tx.QueryRowContext(ctx, query,
+		hold.ConsumerNIK,
+		hold.MerchantID,
+		hold.ProductCode,
+		hold.Tenor,
+		hold.Amount,
+		hold.Reference,
+		hold.Status,
+		ttl.Seconds(),
+	).Scan(&hold.ID, &hold.ExpiresAt, &hold.CreatedAt, &hold.UpdatedAt)
+}
+
+func (r *limitHoldRepository) FindByID(ctx context.Context, id int64) (*model.LimitHold, error) {
+	query := `SELECT ` + limitHoldColumns + ` FROM limit_holds WHERE id = $1`
+
+	hold, err := scanLimitHold(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return hold, nil
+}
+
+func (r *limitHoldRepository) FindAndLockByID(ctx context.Context, tx *sql.Tx, id int64) (*model.LimitHold, error) {
+	query := `SELECT ` + limitHoldColumns + ` FROM limit_holds WHERE id = $1 FOR UPDATE`
+
+	hold, err := scanLimitHold(tx.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return hold, nil
+}
+
+func (r *limitHoldRepository) UpdateStatus(ctx context.Context, tx *sql.Tx, hold *model.LimitHold) error {
+	query := `UPDATE limit_holds SET status = $1, nomor_kontrak = $2 WHERE id = $3`
+
+	_, err := tx.ExecContext(ctx, query, hold.Status, hold.NomorKontrak, hold.ID)
+	return err
+}
+
+// GetHeldByNIK sums the unexpired active holds per tenor. Holds past their
+// expiry stop counting even before the sweeper marks them expired.
+func (r *limitHoldRepository) GetHeldByNIK(ctx context.Context, tx *sql.Tx, nik string) ([]model.TenorUsage, error) {
+	query := `
+    SELECT tenor, COALESCE(SUM(amount), 0) FROM limit_holds
+    WHERE consumer_nik = $1 AND status = 'ACTIVE' AND expires_at > NOW()
+    GROUP BY tenor ORDER BY tenor ASC
+  `
+
+	var q queryer = r.db
+	if tx != nil {
+		q = tx
+	}
+
+	rows, err := q.QueryContext(ctx, query, nik)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var usages []model.TenorUsage
+	for rows.Next() {
+		usage := model.TenorUsage{}
+		if err := rows.Scan(&usage.Tenor, &usage.Held); err != nil {
+			return nil, err
+		}
+		usages = append(usages, usage)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return usages, nil
+}
+
+func (r *limitHoldRepository) ExpireDue(ctx context.Context) (int64, error) {
+	query := `UPDATE limit_holds SET status = 'EXPIRED' WHERE status = 'ACTIVE' AND expires_at <= NOW()`
+
+	result, err := r.db.ExecContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/pkg/money"
+	"github.com/stretchr/testify/suite"
+)
+
+type limitHoldRepositoryTestSuite struct {
+	suite.Suite
+	DB   *sql.DB
+	Mock sqlmock.Sqlmock
+	Repo LimitHoldRepository
+}
+
+func (s *limitHoldRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	s.Require().NoError(err)
+	s.DB = db
+	s.Mock = mock
+	s.Repo = NewLimitHoldRepository(db)
+}
+
+func (s *limitHoldRepositoryTestSuite) TearDownTest() {
+	s.DB.Close()
+}
+
+func (s *limitHoldRepositoryTestSuite) TestCreate_Success() {
+	ctx := context.Background()
+	dummyTime := time.Now()
+
+	s.Mock.ExpectBegin()
+	tx, err := s.DB.Begin()
+	s.Require().NoError(err)
+
+	merchantID := int64(3)
+	hold := &model.LimitHold{
+		ConsumerNIK: "1234567890",
+		MerchantID:  &merchantID,
+		ProductCode: "MOTOR",
+		Tenor:       3,
+		Amount:      money.MustParse("1500000.00"),
+		Reference:   "CART-001",
+		Status:      model.LimitHoldStatusActive,
+	}
+
+	s.Mock.ExpectQuery(`INSERT INTO limit_holds \(consumer_nik, merchant_id, product_code, tenor, amount, reference, status, expires_at\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, NOW\(\) \+ make_interval\(secs => \$8\)\) RETURNING id, expires_at, created_at, updated_at`).
+		WithArgs("1234567890", &merchantID, "MOTOR", 3, hold.Amount, "CART-001", model.LimitHoldStatusActive, 1800.0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "expires_at", "created_at", "updated_at"}).
+			AddRow(7, dummyTime.Add(30*time.Minute), dummyTime, dummyTime))
+
+	err = s.Repo.Create(ctx, tx, hold, 30*time.Minute)
+
+	s.Require().NoError(err)
+	s.Equal(int64(7), hold.ID)
+	s.WithinDuration(dummyTime.Add(30*time.Minute), hold.ExpiresAt, time.Second)
+
+	s.Mock.ExpectCommit()
+	s.Require().NoError(tx.Commit())
+}
+
+func (s *limitHoldRepositoryTestSuite) TestFindByID_Success() {
+	ctx := context.Background()
+	dummyTime := time.Now()
+
+	rows := sqlmock.NewRows([]string{
+		"id", "consumer_nik", "merchant_id", "product_code", "tenor", "amount", "reference", "status", "nomor_kontrak", "expires_at", "expired", "created_at", "updated_at",
+	}).AddRow(
+		7, "1234567890", int64(3), "MOTOR", 3, 1500000.0, "CART-001", "CAPTURED", "KONTRAK-001", dummyTime, false, dummyTime, dummyTime,
+	)
+
+	s.Mock.ExpectQuery(`SELECT id, consumer_nik, merchant_id, product_code, tenor, amount, reference, status, nomor_kontrak, expires_at, .* FROM limit_holds WHERE id = \$1`).
+		WithArgs(int64(7)).
+		WillReturnRows(rows)
+
+	hold, err := s.Repo.FindByID(ctx, 7)
+
+	s.Require().NoError(err)
+	s.Require().NotNil(hold)
+	s.Equal(model.LimitHoldStatusCaptured, hold.Status)
+	s.Require().NotNil(hold.NomorKontrak)
+	s.Equal("KONTRAK-001", *hold.NomorKontrak)
+	s.Require().NotNil(hold.MerchantID)
+	s.Equal(int64(3), *hold.MerchantID)
+	s.False(hold.Expired)
+}
+
+func (s *limitHoldRepositoryTestSuite) TestFindAndLockByID_NotFound() {
+	ctx := context.Background()
+
+	s.Mock.ExpectBegin()
+	tx, err := s.DB.Begin()
+	s.Require().NoError(err)
+
+	s.Mock.ExpectQuery(`SELECT .* FROM limit_holds WHERE id = \$1 FOR UPDATE`).
+		WithArgs(int64(99)).
+		WillReturnError(sql.ErrNoRows)
+
+	hold, err := s.Repo.FindAndLockByID(ctx, tx, 99)
+
+	s.Require().NoError(err)
+	s.Nil(hold)
+
+	s.Mock.ExpectCommit()
+	s.Require().NoError(tx.Commit())
+}
+
+func (s *limitHoldRepositoryTestSuite) TestUpdateStatus_Success() {
+	ctx := context.Background()
+	nomorKontrak := "KONTRAK-001"
+
+	s.Mock.ExpectBegin()
+	tx, err := s.DB.Begin()
+	s.Require().NoError(err)
+
+	s.Mock.ExpectExec(`UPDATE limit_holds SET status = \$1, nomor_kontrak = \$2 WHERE id = \$3`).
+		WithArgs(model.LimitHoldStatusCaptured, &nomorKontrak, int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = s.Repo.UpdateStatus(ctx, tx, &model.LimitHold{ID: 7, Status: model.LimitHoldStatusCaptured, NomorKontrak: &nomorKontrak})
+	s.Require().NoError(err)
+
+	s.Mock.ExpectCommit()
+	s.Require().NoError(tx.Commit())
+}
+
+func (s *limitHoldRepositoryTestSuite) TestGetHeldByNIK_Success() {
+	ctx := context.Background()
+	nik := "1234567890"
+
+	rows := sqlmock.NewRows([]string{"tenor", "held"}).
+		AddRow(3, 1500000.0).
+		AddRow(6, 500000.0)
+
+	s.Mock.ExpectQuery(`SELECT tenor, COALESCE\(SUM\(amount\), 0\) FROM limit_holds WHERE consumer_nik = \$1 AND status = 'ACTIVE' AND expires_at > NOW\(\) GROUP BY tenor ORDER BY tenor ASC`).
+		WithArgs(nik).
+		WillReturnRows(rows)
+
+	usages, err := s.Repo.GetHeldByNIK(ctx, nil, nik)
+
+	s.Require().NoError(err)
+	s.Require().Len(usages, 2)
+	s.Equal(3, usages[0].Tenor)
+	s.Equal(money.MustParse("1500000.00"), usages[0].Held)
+	s.Equal(money.Zero, usages[0].Used)
+}
+
+func (s *limitHoldRepositoryTestSuite) TestExpireDue_Success() {
+	ctx := context.Background()
+
+	s.Mock.ExpectExec(`UPDATE limit_holds SET status = 'EXPIRED' WHERE status = 'ACTIVE' AND expires_at <= NOW\(\)`).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+
+	expired, err := s.Repo.ExpireDue(ctx)
+
+	s.Require().NoError(err)
+	s.Equal(int64(4), expired)
+}
+
+func TestLimitHoldRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(limitHoldRepositoryTestSuite))
+}
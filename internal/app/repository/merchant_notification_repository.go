@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+)
+
+type MerchantNotificationRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, notification *model.MerchantNotification) error
+}
+
+type merchantNotificationRepository struct {
+	db *sql.DB
+}
+
+func NewMerchantNotificationRepository(db *sql.DB) MerchantNotificationRepository {
+	return &merchantNotificationRepository{db: db}
+}
+
+// Create queues a notification within tx, so it is only delivered if the
+// change it reports is committed.
+func (r *merchantNotificationRepository) Create(ctx context.Context, tx *sql.Tx, notification *model.MerchantNotification) error {
+	query := `
+		INSERT INTO merchant_notifications (nomor_kontrak, merchant_id, event, payload)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+
+	return tx.QueryRowContext(ctx, query,
+		notification.NomorKontrak,
+		notification.MerchantID,
+		notification.Event,
+		[]byte(notification.Payload),
+	).Scan(&notification.ID, &notification.CreatedAt)
+}
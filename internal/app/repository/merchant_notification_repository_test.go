@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/stretchr/testify/suite"
+)
+
+type merchantNotificationRepositoryTestSuite struct {
+	suite.Suite
+	DB   *sql.DB
+	Mock sqlmock.Sqlmock
+	Repo MerchantNotificationRepository
+}
+
+func (s *merchantNotificationRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	s.Require().NoError(err)
+	s.DB = db
+	s.Mock = mock
+	s.Repo = NewMerchantNotificationRepository(db)
+}
+
+func (s *merchantNotificationRepositoryTestSuite) TearDownTest() {
+	s.DB.Close()
+}
+
+func (s *merchantNotificationRepositoryTestSuite) TestCreate_Success() {
+	ctx := context.Background()
+	now := time.Now()
+
+	s.Mock.ExpectBegin()
+	tx, err := s.DB.Begin()
+	s.Require().NoError(err)
+
+	payload := json.RawMessage(`{"nomor_kontrak":"TRX1","reason":"bought elsewhere"}`)
+	merchantID := int64(3)
+	notification := &model.MerchantNotification{
+		NomorKontrak: "TRX1",
+		MerchantID:   &merchantID,
+		Event:        model.MerchantEventContractCancelled,
+		Payload:      payload,
+	}
+
+	s.Mock.ExpectQuery(`INSERT INTO merchant_notifications \(nomor_kontrak, merchant_id, event, payload\) VALUES \(\$1, \$2, \$3, \$4\) RETURNING id, created_at`).
+		WithArgs("TRX1", &merchantID, "CONTRACT_CANCELLED", []byte(payload)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(int64(7), now))
+
+	err = s.Repo.Create(ctx, tx, notification)
+
+	s.Require().NoError(err)
+	s.Equal(int64(7), notification.ID)
+	s.Equal(now, notification.CreatedAt)
+}
+
+func TestMerchantNotificationRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(merchantNotificationRepositoryTestSuite))
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+)
+
+type MerchantOutletRepository interface {
+	Create(ctx context.Context, outlet *model.MerchantOutlet) error
+	FindByID(ctx context.Context, id int64) (*model.MerchantOutlet, error)
+	FindAndShareLockByID(ctx context.Context, tx *sql.Tx, id int64) (*model.MerchantOutlet, error)
+	FindByMerchantID(ctx context.Context, merchantID int64) ([]model.MerchantOutlet, error)
+	UpdateStatus(ctx context.Context, id int64, status string) error
+}
+
+type merchantOutletRepository struct {
+	db *sql.DB
+}
+
+func NewMerchantOutletRepository(db *sql.DB) MerchantOutletRepository {
+	return &merchantOutletRepository{db: db}
+}
+
+const merchantOutletColumns = `id, merchant_id, code, name, address, status, created_at, updated_at`
+
+func scanMerchantOutlet(row rowScanner) (*model.MerchantOutlet, error) {
+	outlet := &model.MerchantOutlet{}
+	err := row.Scan(&outlet.ID, &outlet.MerchantID, &outlet.Code, &outlet.Name, &outlet.Address, &outlet.Status, &outlet.CreatedAt, &outlet.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return outlet, nil
+}
+
+func (r *merchantOutletRepository) Create(ctx context.Context, outlet *model.MerchantOutlet) error {
+	query := `
+		INSERT INTO merchant_outlets (merchant_id, code, name, address, status)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.db.QueryRowContext(ctx, query,
+		outlet.MerchantID,
+		outlet.Code,
+		outlet.Name,
+		outlet.Address,
+		outlet.Status,
+	).Scan(&outlet.ID, &outlet.CreatedAt, &outlet.UpdatedAt)
+}
+
+func (r *merchantOutletRepository) FindByID(ctx context.Context, id int64) (*model.MerchantOutlet, error) {
+	query := `SELECT ` + merchantOutletColumns + ` FROM merchant_outlets WHERE id = $1`
+
+	outlet, err := scanMerchantOutlet(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return outlet, nil
+}
+
+// FindAndShareLockByID reads an outlet in tx and keeps its status from
+// changing until tx ends.
+func (r *merchantOutletRepository) FindAndShareLockByID(ctx context.Context, tx *sql.Tx, id int64) (*model.MerchantOutlet, error) {
+	query := `SELECT ` + merchantOutletColumns + ` FROM merchant_outlets WHERE id = $1 FOR SHARE`
+
+	outlet, err := scanMerchantOutlet(tx.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return outlet, nil
+}
+
+func (r *merchantOutletRepository) FindByMerchantID(ctx context.Context, merchantID int64) ([]model.MerchantOutlet, error) {
+	query := `SELECT ` + merchantOutletColumns + ` FROM merchant_outlets WHERE merchant_id = $1 ORDER BY code ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, merchantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	outlets := []model.MerchantOutlet{}
+	for rows.Next() {
+		outlet, err := scanMerchantOutlet(rows)
+		if err != nil {
+			return nil, err
+		}
+		outlets = append(outlets, *outlet)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return outlets, nil
+}
+
+func (r *merchantOutletRepository) UpdateStatus(ctx context.Context, id int64, status string) error {
+	query := `UPDATE merchant_outlets SET status = $2 WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, id, status)
+	return err
+}
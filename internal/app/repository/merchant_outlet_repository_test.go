@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/stretchr/testify/suite"
+)
+
+type merchantOutletRepositoryTestSuite struct {
+	suite.Suite
+	DB   *sql.DB
+	Mock sqlmock.Sqlmock
+	Repo MerchantOutletRepository
+}
+
+func (s *merchantOutletRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	s.Require().NoError(err)
+	s.DB = db
+	s.Mock = mock
+	s.Repo = NewMerchantOutletRepository(db)
+}
+
+func (s *merchantOutletRepositoryTestSuite) TearDownTest() {
+	s.DB.Close()
+}
+
+func merchantOutletRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "merchant_id", "code", "name", "address", "status", "created_at", "updated_at"})
+}
+
+func (s *merchantOutletRepositoryTestSuite) TestCreate_Success() {
+	ctx := context.Background()
+	now := time.Now()
+
+	outlet := &model.MerchantOutlet{
+		MerchantID: 3,
+		Code:       "JKT-01",
+		Name:       "Elektro Jaya Kelapa Gading",
+		Address:    "Jl. Boulevard Raya",
+		Status:     model.OutletStatusActive,
+	}
+
+	s.Mock.ExpectQuery(`INSERT INTO merchant_outlets \(merchant_id, code, name, address, status\) VALUES \(\$1, \$2, \$3, \$4, \$5\) RETURNING id, created_at, updated_at`).
+		WithArgs(int64(3), "JKT-01", "Elektro Jaya Kelapa Gading", "Jl. Boulevard Raya", "ACTIVE").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(int64(9), now, now))
+
+	err := s.Repo.Create(ctx, outlet)
+
+	s.Require().NoError(err)
+	s.Equal(int64(9), outlet.ID)
+	s.Require().NoError(s.Mock.ExpectationsWereMet())
+}
+
+func (s *merchantOutletRepositoryTestSuite) TestFindByID_NotFound() {
+	ctx := context.Background()
+
+	s.Mock.ExpectQuery(`SELECT id, merchant_id, code, name, address, status, created_at, updated_at FROM merchant_outlets WHERE id = \$1`).
+		WithArgs(int64(99)).
+		WillReturnError(sql.ErrNoRows)
+
+	outlet, err := s.Repo.FindByID(ctx, 99)
+
+	s.Require().NoError(err)
+	s.Nil(outlet)
+}
+
+func (s *merchantOutletRepositoryTestSuite) TestFindAndShareLockByID_Success() {
+	ctx := context.Background()
+	now := time.Now()
+
+	s.Mock.ExpectBegin()
+	tx, err := s.DB.Begin()
+	s.Require().NoError(err)
+
+	s.Mock.ExpectQuery(`SELECT .* FROM merchant_outlets WHERE id = \$1 FOR SHARE`).
+		WithArgs(int64(9)).
+		WillReturnRows(merchantOutletRows().AddRow(int64(9), int64(3), "JKT-01", "Kelapa Gading", "Jl. Boulevard Raya", "CLOSED", now, now))
+
+	outlet, err := s.Repo.FindAndShareLockByID(ctx, tx, 9)
+
+	s.Require().NoError(err)
+	s.Require().NotNil(outlet)
+	s.Equal(model.OutletStatusClosed, outlet.Status)
+
+	s.Mock.ExpectCommit()
+	s.Require().NoError(tx.Commit())
+}
+
+func (s *merchantOutletRepositoryTestSuite) TestFindByMerchantID_Success() {
+	ctx := context.Background()
+	now := time.Now()
+
+	s.Mock.ExpectQuery(`SELECT .* FROM merchant_outlets WHERE merchant_id = \$1 ORDER BY code ASC`).
+		WithArgs(int64(3)).
+		WillReturnRows(merchantOutletRows().
+			AddRow(int64(9), int64(3), "JKT-01", "Kelapa Gading", "Jl. Boulevard Raya", "ACTIVE", now, now).
+			AddRow(int64(10), int64(3), "JKT-02", "Cempaka Putih", "", "CLOSED", now, now))
+
+	outlets, err := s.Repo.FindByMerchantID(ctx, 3)
+
+	s.Require().NoError(err)
+	s.Require().Len(outlets, 2)
+	s.Equal("JKT-01", outlets[0].Code)
+	s.Equal(model.OutletStatusClosed, outlets[1].Status)
+}
+
+func (s *merchantOutletRepositoryTestSuite) TestUpdateStatus_Success() {
+	ctx := context.Background()
+
+	s.Mock.ExpectExec(`UPDATE merchant_outlets SET status = \$2 WHERE id = \$1`).
+		WithArgs(int64(9), "CLOSED").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := s.Repo.UpdateStatus(ctx, 9, model.OutletStatusClosed)
+	s.Require().NoError(err)
+}
+
+func TestMerchantOutletRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(merchantOutletRepositoryTestSuite))
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+)
+
+type MerchantRepository interface {
+	Create(ctx context.Context, merchant *model.Merchant) error
+	FindByID(ctx context.Context, id int64) (*model.Merchant, error)
+	FindAndShareLockByID(ctx context.Context, tx *sql.Tx, id int64) (*model.Merchant, error)
+	FindByCode(ctx context.Context, code string) (*model.Merchant, error)
+	List(ctx context.Context, filter model.MerchantFilter) ([]model.Merchant, error)
+	UpdateStatus(ctx context.Context, id int64, status string) error
+}
+
+type merchantRepository struct {
+	db *sql.DB
+}
+
+func NewMerchantRepository(db *sql.DB) MerchantRepository {
+	return &merchantRepository{db: db}
+}
+
+const merchantColumns = `id, code, name, mdr_rate, settlement_bank_code, settlement_account_number, settlement_account_name, status, created_at, updated_at`
+
+func scanMerchant(row rowScanner) (*model.Merchant, error) {
+	merchant := &model.Merchant{}
+	err := row.Scan(&merchant.ID, &merchant.Code, &merchant.Name, &merchant.MDRRate, &merchant.SettlementAccount.BankCode, &merchant.SettlementAccount.AccountNumber, &merchant.SettlementAccount.AccountName, &merchant.Status, &merchant.CreatedAt, &merchant.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return merchant, nil
+}
+
+func (r *merchantRepository) Create(ctx context.Context, merchant *model.Merchant) error {
+	query := `
+		INSERT INTO merchants (code, name, mdr_rate, settlement_bank_code, settlement_account_number, settlement_account_name, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.db.QueryRowContext(ctx, query,
+		merchant.Code,
+		merchant.Name,
+		merchant.MDRRate,
+		merchant.SettlementAccount.BankCode,
+		merchant.SettlementAccount.AccountNumber,
+		merchant.SettlementAccount.AccountName,
+		merchant.Status,
+	).Scan(&merchant.ID, &merchant.CreatedAt, &merchant.UpdatedAt)
+}
+
+func (r *merchantRepository) findOne(ctx context.Context, q queryer, query string, args ...interface{}) (*model.Merchant, error) {
+	merchant, err := scanMerchant(q.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return merchant, nil
+}
+
+func (r *merchantRepository) FindByID(ctx context.Context, id int64) (*model.Merchant, error) {
+	query := `SELECT ` + merchantColumns + ` FROM merchants WHERE id = $1`
+	return r.findOne(ctx, r.db, query, id)
+}
+
+// FindAndShareLockByID reads a merchant in tx and keeps its status from
+// changing until tx ends, so a contract is never opened at a merchant that is
+// being suspended at the same time.
+func (r *merchantRepository) FindAndShareLockByID(ctx context.Context, tx *sql.Tx, id int64) (*model.Merchant, error) {
+	query := `SELECT ` + merchantColumns + ` FROM merchants WHERE id = $1 FOR SHARE`
+	return r.findOne(ctx, tx, query, id)
+}
+
+func (r *merchantRepository) FindByCode(ctx context.Context, code string) (*model.Merchant, error) {
+	query := `SELECT ` + merchantColumns + ` FROM merchants WHERE code = $1`
+	return r.findOne(ctx, r.db, query, code)
+}
+
+func (r *merchantRepository) List(ctx context.Context, filter model.MerchantFilter) ([]model.Merchant, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	query := `SELECT ` + merchantColumns + ` FROM merchants`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY code ASC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	merchants := []model.Merchant{}
+	for rows.Next() {
+		merchant, err := scanMerchant(rows)
+		if err != nil {
+			return nil, err
+		}
+		merchants = append(merchants, *merchant)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return merchants, nil
+}
+
+func (r *merchantRepository) UpdateStatus(ctx context.Context, id int64, status string) error {
+	query := `UPDATE merchants SET status = $2 WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, id, status)
+	return err
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/pkg/money"
+	"github.com/stretchr/testify/suite"
+)
+
+type merchantRepositoryTestSuite struct {
+	suite.Suite
+	DB   *sql.DB
+	Mock sqlmock.Sqlmock
+	Repo MerchantRepository
+}
+
+func (s *merchantRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	s.Require().NoError(err)
+	s.DB = db
+	s.Mock = mock
+	s.Repo = NewMerchantRepository(db)
+}
+
+func (s *merchantRepositoryTestSuite) TearDownTest() {
+	s.DB.Close()
+}
+
+func merchantRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{
+		"id", "code", "name", "mdr_rate", "settlement_bank_code", "settlement_account_number", "settlement_account_name", "status", "created_at", "updated_at",
+	})
+}
+
+func (s *merchantRepositoryTestSuite) TestCreate_Success() {
+	ctx := context.Background()
+	now := time.Now()
+
+	merchant := &model.Merchant{
+		Code:    "ELEKTRO",
+		Name:    "Toko Elektro Jaya",
+		MDRRate: money.MustParseRate("0.015"),
+		SettlementAccount: model.SettlementAccount{
+			BankCode:      "014",
+			AccountNumber: "1234567890",
+			AccountName:   "PT Elektro Jaya",
+		},
+		Status: model.MerchantStatusActive,
+	}
+
+	s.Mock.ExpectQuery(`INSERT INTO merchants \(code, name, mdr_rate, settlement_bank_code, settlement_account_number, settlement_account_name, status\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7\) RETURNING id, created_at, updated_at`).
+		WithArgs("ELEKTRO", "Toko Elektro Jaya", merchant.MDRRate, "014", "1234567890", "PT Elektro Jaya", "ACTIVE").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(int64(3), now, now))
+
+	err := s.Repo.Create(ctx, merchant)
+
+	s.Require().NoError(err)
+	s.Equal(int64(3), merchant.ID)
+	s.Require().NoError(s.Mock.ExpectationsWereMet())
+}
+
+func (s *merchantRepositoryTestSuite) TestFindByID_Success() {
+	ctx := context.Background()
+	now := time.Now()
+
+	s.Mock.ExpectQuery(`SELECT id, code, name, mdr_rate, settlement_bank_code, settlement_account_number, settlement_account_name, status, created_at, updated_at FROM merchants WHERE id = \$1`).
+		WithArgs(int64(3)).
+		WillReturnRows(merchantRows().AddRow(int64(3), "ELEKTRO", "Toko Elektro Jaya", "0.015000", "014", "1234567890", "PT Elektro Jaya", "ACTIVE", now, now))
+
+	merchant, err := s.Repo.FindByID(ctx, 3)
+
+	s.Require().NoError(err)
+	s.Require().NotNil(merchant)
+	s.Equal("ELEKTRO", merchant.Code)
+	s.Equal(money.MustParseRate("0.015"), merchant.MDRRate)
+	s.Equal("1234567890", merchant.SettlementAccount.AccountNumber)
+}
+
+func (s *merchantRepositoryTestSuite) TestFindAndShareLockByID_NotFound() {
+	ctx := context.Background()
+
+	s.Mock.ExpectBegin()
+	tx, err := s.DB.Begin()
+	s.Require().NoError(err)
+
+	s.Mock.ExpectQuery(`SELECT .* FROM merchants WHERE id = \$1 FOR SHARE`).
+		WithArgs(int64(99)).
+		WillReturnError(sql.ErrNoRows)
+
+	merchant, err := s.Repo.FindAndShareLockByID(ctx, tx, 99)
+
+	s.Require().NoError(err)
+	s.Nil(merchant)
+
+	s.Mock.ExpectCommit()
+	s.Require().NoError(tx.Commit())
+}
+
+func (s *merchantRepositoryTestSuite) TestFindByCode_NotFound() {
+	ctx := context.Background()
+
+	s.Mock.ExpectQuery(`SELECT .* FROM merchants WHERE code = \$1`).
+		WithArgs("GHOST").
+		WillReturnError(sql.ErrNoRows)
+
+	merchant, err := s.Repo.FindByCode(ctx, "GHOST")
+
+	s.Require().NoError(err)
+	s.Nil(merchant)
+}
+
+func (s *merchantRepositoryTestSuite) TestList_WithFilter() {
+	ctx := context.Background()
+	now := time.Now()
+
+	s.Mock.ExpectQuery(`SELECT .* FROM merchants WHERE status = \$1 ORDER BY code ASC`).
+		WithArgs("SUSPENDED").
+		WillReturnRows(merchantRows().
+			AddRow(int64(4), "GADGET", "Gadget Store", "0.020000", "008", "555", "PT Gadget", "SUSPENDED", now, now))
+
+	merchants, err := s.Repo.List(ctx, model.MerchantFilter{Status: model.MerchantStatusSuspended})
+
+	s.Require().NoError(err)
+	s.Require().Len(merchants, 1)
+	s.Equal("GADGET", merchants[0].Code)
+}
+
+func (s *merchantRepositoryTestSuite) TestUpdateStatus_Success() {
+	ctx := context.Background()
+
+	s.Mock.ExpectExec(`UPDATE merchants SET status = \$2 WHERE id = \$1`).
+		WithArgs(int64(3), "SUSPENDED").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := s.Repo.UpdateStatus(ctx, 3, model.MerchantStatusSuspended)
+	s.Require().NoError(err)
+}
+
+func TestMerchantRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(merchantRepositoryTestSuite))
+}
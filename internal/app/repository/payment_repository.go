@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/lib/pq"
+)
+
+type PaymentRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, payment *model.Payment) error
+	FindByReference(ctx context.Context, tx *sql.Tx, nomorKontrak string, reference string) (*model.Payment, error)
+	FindByNomorKontrak(ctx context.Context, nomorKontrak string) ([]model.Payment, error)
+}
+
+type paymentRepository struct {
+	db *sql.DB
+}
+
+func NewPaymentRepository(db *sql.DB) PaymentRepository {
+	return &paymentRepository{db: db}
+}
+
+const paymentColumns = `id, nomor_kontrak, reference, amount, allocated_amount, unapplied_amount, paid_at, posted_by, created_at`
+
+func scanPayment(row rowScanner) (*model.Payment, error) {
+	payment := &model.Payment{}
+	err := row.Scan(&payment.ID, &payment.NomorKontrak, &payment.Reference, &payment.Amount, &payment.AllocatedAmount, &payment.UnappliedAmount, &payment.PaidAt, &payment.PostedBy, &payment.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+// loadPaymentAllocations fills in the allocations of every payment with a
+// single query.
+func loadPaymentAllocations(ctx context.Context, q queryer, payments []model.Payment) error {
+	if len(payments) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(payments))
+	index := make(map[int64]int, len(payments))
+	for i := range payments {
+		ids = append(ids, payments[i].ID)
+		index[payments[i].ID] = i
+		payments[i].Allocations = []model.PaymentAllocation{}
+	}
+
+	query := `SELECT payment_id, installment_number, component, amount
+  FROM payment_allocations WHERE payment_id = ANY($1)
+  ORDER BY payment_id ASC, installment_number ASC,
+    array_position(ARRAY['PENALTY', 'ADMIN_FEE', 'INTEREST', 'PRINCIPAL']::VARCHAR[], component) ASC`
+
+	rows, err := q.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			paymentID  int64
+			allocation model.PaymentAllocation
+		)
+		if err := rows.Scan(&paymentID, &allocation.InstallmentNumber, &allocation.Component, &allocation.Amount); err != nil {
+			return err
+		}
+		if i, ok := index[paymentID]; ok {
+			payments[i].Allocations = append(payments[i].Allocations, allocation)
+		}
+	}
+
+	return rows.Err()
+}
+
+func (r *paymentRepository) Create(ctx context.Context, tx *sql.Tx, payment *model.Payment) error {
+	query := `
+		INSERT INTO payments (nomor_kontrak, reference, amount, allocated_amount, unapplied_amount, paid_at, posted_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+
+	err := tx.QueryRowContext(ctx, query,
+		payment.NomorKontrak,
+		payment.Reference,
+		payment.Amount,
+		payment.AllocatedAmount,
+		payment.UnappliedAmount,
+		payment.PaidAt,
+		payment.PostedBy,
+	).Scan(&payment.ID, &payment.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	allocationQuery := `
+		INSERT INTO payment_allocations (payment_id, installment_number, component, amount)
+		VALUES ($1, $2, $3, $4)
+	`
+	for _, allocation := range payment.Allocations {
+		_, err := tx.ExecContext(ctx, allocationQuery, payment.ID, allocation.InstallmentNumber, allocation.Component, allocation.Amount)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *paymentRepository) FindByReference(ctx context.Context, tx *sql.Tx, nomorKontrak string, reference string) (*model.Payment, error) {
+	query := `SELECT ` + paymentColumns + ` FROM payments WHERE nomor_kontrak = $1 AND reference = $2`
+
+	payment, err := scanPayment(tx.QueryRowContext(ctx, query, nomorKontrak, reference))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	payments := []model.Payment{*payment}
+	if err := loadPaymentAllocations(ctx, tx, payments); err != nil {
+		return nil, err
+	}
+	return &payments[0], nil
+}
+
+func (r *paymentRepository) FindByNomorKontrak(ctx context.Context, nomorKontrak string) ([]model.Payment, error) {
+	query := `SELECT ` + paymentColumns + ` FROM payments WHERE nomor_kontrak = $1 ORDER BY paid_at ASC, id ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, nomorKontrak)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	payments := []model.Payment{}
+	for rows.Next() {
+		payment, err := scanPayment(rows)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, *payment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := loadPaymentAllocations(ctx, r.db, payments); err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/pkg/money"
+	"github.com/stretchr/testify/suite"
+)
+
+type paymentRepositoryTestSuite struct {
+	suite.Suite
+	DB   *sql.DB
+	Mock sqlmock.Sqlmock
+	Repo PaymentRepository
+}
+
+func (s *paymentRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	s.Require().NoError(err)
+	s.DB = db
+	s.Mock = mock
+	s.Repo = NewPaymentRepository(db)
+}
+
+func (s *paymentRepositoryTestSuite) TearDownTest() {
+	s.DB.Close()
+}
+
+func paymentRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{
+		"id", "nomor_kontrak", "reference", "amount", "allocated_amount", "unapplied_amount", "paid_at", "posted_by", "created_at",
+	})
+}
+
+func paymentAllocationRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"payment_id", "installment_number", "component", "amount"})
+}
+
+func (s *paymentRepositoryTestSuite) TestCreate_Success() {
+	ctx := context.Background()
+	paidAt := time.Now()
+
+	s.Mock.ExpectBegin()
+	tx, err := s.DB.Begin()
+	s.Require().NoError(err)
+
+	payment := &model.Payment{
+		NomorKontrak:    "TRX1",
+		Reference:       "VA-0001",
+		Amount:          money.FromRupiah(40000),
+		AllocatedAmount: money.FromRupiah(40000),
+		PaidAt:          paidAt,
+		PostedBy:        "admin",
+		Allocations: []model.PaymentAllocation{
+			{InstallmentNumber: 1, Component: model.PaymentComponentAdminFee, Amount: money.FromRupiah(10000)},
+			{InstallmentNumber: 1, Component: model.PaymentComponentInterest, Amount: money.FromRupiah(30000)},
+		},
+	}
+
+	s.Mock.ExpectQuery(`INSERT INTO payments \(nomor_kontrak, reference, amount, allocated_amount, unapplied_amount, paid_at, posted_by\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7\) RETURNING id, created_at`).
+		WithArgs("TRX1", "VA-0001", payment.Amount, payment.AllocatedAmount, money.Zero, paidAt, "admin").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(int64(5), paidAt))
+	s.Mock.ExpectExec(`INSERT INTO payment_allocations \(payment_id, installment_number, component, amount\) VALUES \(\$1, \$2, \$3, \$4\)`).
+		WithArgs(int64(5), 1, "ADMIN_FEE", money.FromRupiah(10000)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.Mock.ExpectExec(`INSERT INTO payment_allocations`).
+		WithArgs(int64(5), 1, "INTEREST", money.FromRupiah(30000)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = s.Repo.Create(ctx, tx, payment)
+
+	s.Require().NoError(err)
+	s.Equal(int64(5), payment.ID)
+	s.Require().NoError(s.Mock.ExpectationsWereMet())
+}
+
+func (s *paymentRepositoryTestSuite) TestFindByReference_NotFound() {
+	ctx := context.Background()
+
+	s.Mock.ExpectBegin()
+	tx, err := s.DB.Begin()
+	s.Require().NoError(err)
+
+	s.Mock.ExpectQuery(`SELECT .* FROM payments WHERE nomor_kontrak = \$1 AND reference = \$2`).
+		WithArgs("TRX1", "VA-0001").
+		WillReturnError(sql.ErrNoRows)
+
+	payment, err := s.Repo.FindByReference(ctx, tx, "TRX1", "VA-0001")
+
+	s.Require().NoError(err)
+	s.Nil(payment)
+}
+
+func (s *paymentRepositoryTestSuite) TestFindByNomorKontrak_Success() {
+	ctx := context.Background()
+	now := time.Now()
+
+	s.Mock.ExpectQuery(`SELECT id, nomor_kontrak, reference, amount, allocated_amount, unapplied_amount, paid_at, posted_by, created_at FROM payments WHERE nomor_kontrak = \$1 ORDER BY paid_at ASC, id ASC`).
+		WithArgs("TRX1").
+		WillReturnRows(paymentRows().
+			AddRow(int64(5), "TRX1", "VA-0001", "40000.00", "40000.00", "0.00", now, "admin", now).
+			AddRow(int64(6), "TRX1", "VA-0002", "400000.00", "368333.00", "31667.00", now, "admin", now))
+	s.Mock.ExpectQuery(`SELECT payment_id, installment_number, component, amount FROM payment_allocations WHERE payment_id = ANY\(\$1\)`).
+		WillReturnRows(paymentAllocationRows().
+			AddRow(int64(5), 1, "ADMIN_FEE", "10000.00").
+			AddRow(int64(5), 1, "INTEREST", "30000.00").
+			AddRow(int64(6), 1, "PRINCIPAL", "368333.00"))
+
+	payments, err := s.Repo.FindByNomorKontrak(ctx, "TRX1")
+
+	s.Require().NoError(err)
+	s.Require().Len(payments, 2)
+	s.Len(payments[0].Allocations, 2)
+	s.Equal(money.FromRupiah(31667), payments[1].UnappliedAmount)
+	s.Require().Len(payments[1].Allocations, 1)
+	s.Equal(model.PaymentComponentPrincipal, payments[1].Allocations[0].Component)
+}
+
+func (s *paymentRepositoryTestSuite) TestFindByNomorKontrak_Empty() {
+	ctx := context.Background()
+
+	s.Mock.ExpectQuery(`SELECT .* FROM payments WHERE nomor_kontrak = \$1`).
+		WithArgs("TRX1").
+		WillReturnRows(paymentRows())
+
+	payments, err := s.Repo.FindByNomorKontrak(ctx, "TRX1")
+
+	s.Require().NoError(err)
+	s.Empty(payments)
+	s.Require().NoError(s.Mock.ExpectationsWereMet())
+}
+
+func TestPaymentRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(paymentRepositoryTestSuite))
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+)
+
+type PenaltyChargeRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, charge *model.PenaltyCharge) error
+	FindByNomorKontrak(ctx context.Context, nomorKontrak string) ([]model.PenaltyCharge, error)
+}
+
+type penaltyChargeRepository struct {
+	db *sql.DB
+}
+
+func NewPenaltyChargeRepository(db *sql.DB) PenaltyChargeRepository {
+	return &penaltyChargeRepository{db: db}
+}
+
+func (r *penaltyChargeRepository) Create(ctx context.Context, tx *sql.Tx, charge *model.PenaltyCharge) error {
+	query := `
+		INSERT INTO penalty_charges (nomor_kontrak, installment_number, charge_date, days, base_amount, daily_rate, amount)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+
+	return tx.QueryRowContext(ctx, query,
+		charge.NomorKontrak,
+		charge.InstallmentNumber,
+		charge.ChargeDate,
+		charge.Days,
+		charge.BaseAmount,
+		charge.DailyRate,
+		charge.Amount,
+	).Scan(&charge.ID, &charge.CreatedAt)
+}
+
+func (r *penaltyChargeRepository) FindByNomorKontrak(ctx context.Context, nomorKontrak string) ([]model.PenaltyCharge, error) {
+	query := `SELECT id, nomor_kontrak, installment_number, charge_date, days, base_amount, daily_rate, amount, created_at
+  FROM penalty_charges WHERE nomor_kontrak = $1 ORDER BY charge_date ASC, installment_number ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, nomorKontrak)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	charges := []model.PenaltyCharge{}
+	for rows.Next() {
+		charge := model.PenaltyCharge{}
+		if err := rows.Scan(&charge.ID, &charge.NomorKontrak, &charge.InstallmentNumber, &charge.ChargeDate, &charge.Days, &charge.BaseAmount, &charge.DailyRate, &charge.Amount, &charge.CreatedAt); err != nil {
+			return nil, err
+		}
+		charges = append(charges, charge)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return charges, nil
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/pkg/money"
+	"github.com/stretchr/testify/suite"
+)
+
+type penaltyChargeRepositoryTestSuite struct {
+	suite.Suite
+	DB   *sql.DB
+	Mock sqlmock.Sqlmock
+	Repo PenaltyChargeRepository
+}
+
+func (s *penaltyChargeRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	s.Require().NoError(err)
+	s.DB = db
+	s.Mock = mock
+	s.Repo = NewPenaltyChargeRepository(db)
+}
+
+func (s *penaltyChargeRepositoryTestSuite) TearDownTest() {
+	s.DB.Close()
+}
+
+func (s *penaltyChargeRepositoryTestSuite) TestCreate_Success() {
+	ctx := context.Background()
+	now := time.Now()
+	chargeDate := time.Date(2025, 4, 15, 0, 0, 0, 0, time.UTC)
+
+	s.Mock.ExpectBegin()
+	tx, err := s.DB.Begin()
+	s.Require().NoError(err)
+
+	charge := &model.PenaltyCharge{
+		NomorKontrak:      "TRX1",
+		InstallmentNumber: 1,
+		ChargeDate:        chargeDate,
+		Days:              5,
+		BaseAmount:        money.FromRupiah(368333),
+		DailyRate:         money.MustParseRate("0.001"),
+		Amount:            money.FromRupiah(1842),
+	}
+
+	s.Mock.ExpectQuery(`INSERT INTO penalty_charges \(nomor_kontrak, installment_number, charge_date, days, base_amount, daily_rate, amount\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7\) RETURNING id, created_at`).
+		WithArgs("TRX1", 1, chargeDate, 5, charge.BaseAmount, charge.DailyRate, charge.Amount).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(int64(7), now))
+
+	err = s.Repo.Create(ctx, tx, charge)
+
+	s.Require().NoError(err)
+	s.Equal(int64(7), charge.ID)
+	s.Equal(now, charge.CreatedAt)
+}
+
+func (s *penaltyChargeRepositoryTestSuite) TestFindByNomorKontrak_Success() {
+	ctx := context.Background()
+	now := time.Now()
+	chargeDate := time.Date(2025, 4, 15, 0, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{"id", "nomor_kontrak", "installment_number", "charge_date", "days", "base_amount", "daily_rate", "amount", "created_at"}).
+		AddRow(int64(7), "TRX1", 1, chargeDate, 5, "368333.00", "0.001000", "1842.00", now).
+		AddRow(int64(9), "TRX1", 1, chargeDate.AddDate(0, 0, 1), 1, "368333.00", "0.001000", "368.00", now)
+
+	s.Mock.ExpectQuery(`SELECT id, nomor_kontrak, installment_number, charge_date, days, base_amount, daily_rate, amount, created_at FROM penalty_charges WHERE nomor_kontrak = \$1 ORDER BY charge_date ASC, installment_number ASC`).
+		WithArgs("TRX1").
+		WillReturnRows(rows)
+
+	charges, err := s.Repo.FindByNomorKontrak(ctx, "TRX1")
+
+	s.Require().NoError(err)
+	s.Require().Len(charges, 2)
+	s.Equal(5, charges[0].Days)
+	s.Equal(money.MustParseRate("0.001"), charges[0].DailyRate)
+	s.Equal(money.FromRupiah(368), charges[1].Amount)
+}
+
+func (s *penaltyChargeRepositoryTestSuite) TestFindByNomorKontrak_Empty() {
+	ctx := context.Background()
+
+	s.Mock.ExpectQuery(`SELECT .* FROM penalty_charges WHERE nomor_kontrak = \$1`).
+		WithArgs("TRX1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "nomor_kontrak", "installment_number", "charge_date", "days", "base_amount", "daily_rate", "amount", "created_at"}))
+
+	charges, err := s.Repo.FindByNomorKontrak(ctx, "TRX1")
+
+	s.Require().NoError(err)
+	s.Empty(charges)
+	s.NotNil(charges)
+}
+
+func TestPenaltyChargeRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(penaltyChargeRepositoryTestSuite))
+}
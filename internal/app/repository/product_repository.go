@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/lib/pq"
+)
+
+type ProductRepository interface {
+	FindByCode(ctx context.Context, code string) (*model.Product, error)
+	FindActive(ctx context.Context) ([]model.Product, error)
+}
+
+type productRepository struct {
+	db *sql.DB
+}
+
+func NewProductRepository(db *sql.DB) ProductRepository {
+	return &productRepository{db: db}
+}
+
+const productColumns = `code, name, contract_prefix, tenors, min_otr, max_otr, min_age, min_income, require_verified_kyc, interest_method, grace_period_days, penalty_daily_rate, penalty_cap_rate, is_active, created_at, updated_at`
+
+func scanProduct(row rowScanner) (*model.Product, error) {
+	product := &model.Product{}
+	var tenors pq.Int64Array
+	err := row.Scan(&product.Code, &product.Name, &product.ContractPrefix, &tenors, &product.MinOTR, &product.MaxOTR, &product.MinAge, &product.MinIncome, &product.RequireVerifiedKYC, &product.InterestMethod, &product.GracePeriodDays, &product.PenaltyDailyRate, &product.PenaltyCapRate, &product.IsActive, &product.CreatedAt, &product.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	product.Tenors = make([]int, 0, len(tenors))
+	for _, tenor := range tenors {
+		product.Tenors = append(product.Tenors, int(tenor))
+	}
+	return product, nil
+}
+
+func (r *productRepository) FindByCode(ctx context.Context, code string) (*model.Product, error) {
+	query := `SELECT ` + productColumns + ` FROM products WHERE code = $1`
+
+	product, err := scanProduct(r.db.QueryRowContext(ctx, query, code))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return product, nil
+}
+
+func (r *productRepository) FindActive(ctx context.Context) ([]model.Product, error) {
+	query := `SELECT ` + productColumns + ` FROM products WHERE is_active ORDER BY code ASC`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var products []model.Product
+	for rows.Next() {
+		product, err := scanProduct(rows)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, *product)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/glennprays/xyz-fin/pkg/money"
+	"github.com/stretchr/testify/suite"
+)
+
+type productRepositoryTestSuite struct {
+	suite.Suite
+	DB   *sql.DB
+	Mock sqlmock.Sqlmock
+	Repo ProductRepository
+}
+
+func (s *productRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	s.Require().NoError(err)
+	s.DB = db
+	s.Mock = mock
+	s.Repo = NewProductRepository(db)
+}
+
+func (s *productRepositoryTestSuite) TearDownTest() {
+	s.DB.Close()
+}
+
+func productRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{
+		"code", "name", "contract_prefix", "tenors", "min_otr", "max_otr", "min_age", "min_income", "require_verified_kyc", "interest_method", "grace_period_days", "penalty_daily_rate", "penalty_cap_rate", "is_active", "created_at", "updated_at",
+	})
+}
+
+func (s *productRepositoryTestSuite) TestFindByCode_Success() {
+	ctx := context.Background()
+	dummyTime := time.Now()
+
+	s.Mock.ExpectQuery(`SELECT code, name, contract_prefix, tenors, min_otr, max_otr, min_age, min_income, require_verified_kyc, interest_method, grace_period_days, penalty_daily_rate, penalty_cap_rate, is_active, created_at, updated_at FROM products WHERE code = \$1`).
+		WithArgs("white_goods").
+		WillReturnRows(productRows().AddRow("white_goods", "White Goods", "WG", "{3,6}", 1000000.0, 25000000.0, 21, 3000000.0, true, "ANNUITY", 3, "0.001000", "0.100000", true, dummyTime, dummyTime))
+
+	product, err := s.Repo.FindByCode(ctx, "white_goods")
+
+	s.Require().NoError(err)
+	s.Require().NotNil(product)
+	s.Equal("White Goods", product.Name)
+	s.Equal("WG", product.ContractPrefix)
+	s.Equal([]int{3, 6}, product.Tenors)
+	s.Equal(money.MustParse("1000000.00"), product.MinOTR)
+	s.Equal(money.MustParse("25000000.00"), product.MaxOTR)
+	s.True(product.RequireVerifiedKYC)
+	s.Equal("ANNUITY", product.InterestMethod)
+	s.Equal(3, product.GracePeriodDays)
+	s.Equal(money.MustParseRate("0.001"), product.PenaltyDailyRate)
+	s.Equal(money.MustParseRate("0.1"), product.PenaltyCapRate)
+}
+
+func (s *productRepositoryTestSuite) TestFindByCode_NotFound() {
+	ctx := context.Background()
+
+	s.Mock.ExpectQuery(`SELECT .* FROM products WHERE code = \$1`).
+		WithArgs("unknown").
+		WillReturnError(sql.ErrNoRows)
+
+	product, err := s.Repo.FindByCode(ctx, "unknown")
+
+	s.Require().NoError(err)
+	s.Nil(product)
+}
+
+func (s *productRepositoryTestSuite) TestFindActive_Success() {
+	ctx := context.Background()
+	dummyTime := time.Now()
+
+	s.Mock.ExpectQuery(`SELECT .* FROM products WHERE is_active ORDER BY code ASC`).
+		WillReturnRows(productRows().
+			AddRow("cash_loan", "Cash Loan", "CL", "{1,2,3}", 500000.0, 10000000.0, 21, 5000000.0, true, "DECLINING_BALANCE", 3, "0.001000", "0.100000", true, dummyTime, dummyTime).
+			AddRow("paylater", "PayLater", "PL", "{1,2,3,6}", 50000.0, 5000000.0, 21, 3000000.0, true, "FLAT", 0, "0.001500", "0.100000", true, dummyTime, dummyTime))
+
+	products, err := s.Repo.FindActive(ctx)
+
+	s.Require().NoError(err)
+	s.Require().Len(products, 2)
+	s.Equal("cash_loan", products[0].Code)
+	s.Equal([]int{1, 2, 3, 6}, products[1].Tenors)
+}
+
+func TestProductRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(productRepositoryTestSuite))
+}
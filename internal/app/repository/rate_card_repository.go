@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/lib/pq"
+)
+
+type RateCardRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, card *model.RateCard) error
+	FindByID(ctx context.Context, id int64) (*model.RateCard, error)
+	FindLatestAndLock(ctx context.Context, tx *sql.Tx, productCode string, tenor int, riskGrade string) (*model.RateCard, error)
+	FindEffective(ctx context.Context, tx *sql.Tx, productCode string, tenor int, riskGrade string, at time.Time) (*model.RateCard, error)
+	Close(ctx context.Context, tx *sql.Tx, id int64, effectiveTo time.Time) error
+	List(ctx context.Context, filter model.RateCardFilter) ([]model.RateCard, error)
+}
+
+type rateCardRepository struct {
+	db *sql.DB
+}
+
+func NewRateCardRepository(db *sql.DB) RateCardRepository {
+	return &rateCardRepository{db: db}
+}
+
+const rateCardColumns = `id, product_code, tenor, risk_grade, version, interest_rate, admin_fee_type, effective_from, effective_to, created_by, created_at`
+
+func scanRateCard(row rowScanner) (*model.RateCard, error) {
+	card := &model.RateCard{}
+	var (
+		effectiveTo sql.NullTime
+		createdBy   sql.NullString
+	)
+	err := row.Scan(&card.ID, &card.ProductCode, &card.Tenor, &card.RiskGrade, &card.Version, &card.InterestRate, &card.AdminFeeType, &card.EffectiveFrom, &effectiveTo, &createdBy, &card.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if effectiveTo.Valid {
+		card.EffectiveTo = &effectiveTo.Time
+	}
+	if createdBy.Valid {
+		card.CreatedBy = &createdBy.String
+	}
+	return card, nil
+}
+
+// loadFeeTiers fills in the fee tiers of every card with a single query.
+func loadFeeTiers(ctx context.Context, q queryer, cards []model.RateCard) error {
+	if len(cards) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(cards))
+	index := make(map[int64]int, len(cards))
+	for i := range cards {
+		ids = append(ids, cards[i].ID)
+		index[cards[i].ID] = i
+		cards[i].FeeTiers = []model.AdminFeeTier{}
+	}
+
+	query := `SELECT rate_card_id, min_otr, fee_amount, fee_rate
+  FROM rate_card_fee_tiers WHERE rate_card_id = ANY($1) ORDER BY rate_card_id ASC, min_otr ASC`
+
+	rows, err := q.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cardID int64
+			tier   model.AdminFeeTier
+		)
+		if err := rows.Scan(&cardID, &tier.MinOTR, &tier.FeeAmount, &tier.FeeRate); err != nil {
+			return err
+		}
+		if i, ok := index[cardID]; ok {
+			cards[i].FeeTiers = append(cards[i].FeeTiers, tier)
+		}
+	}
+
+	return rows.Err()
+}
+
+func (r *rateCardRepository) findOne(ctx context.Context, q queryer, query string, args ...interface{}) (*model.RateCard, error) {
+	card, err := scanRateCard(q.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	cards := []model.RateCard{*card}
+	if err := loadFeeTiers(ctx, q, cards); err != nil {
+		return nil, err
+	}
+	return &cards[0], nil
+}
+
+func (r *rateCardRepository) Create(ctx context.Context, tx *sql.Tx, card *model.RateCard) error {
+	query := `
+		INSERT INTO rate_cards (product_code, tenor, risk_grade, version, interest_rate, admin_fee_type, effective_from, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''))
+		RETURNING id, created_at
+	`
+
+	var createdBy string
+	if card.CreatedBy != nil {
+		createdBy = *card.CreatedBy
+	}
+
+	err := tx.QueryRowContext(ctx, query,
+		card.ProductCode,
+		card.Tenor,
+		card.RiskGrade,
+		card.Version,
+		card.InterestRate,
+		card.AdminFeeType,
+		card.EffectiveFrom,
+		createdBy,
+	).Scan(&card.ID, &card.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	tierQuery := `
+		INSERT INTO rate_card_fee_tiers (rate_card_id, min_otr, fee_amount, fee_rate)
+		VALUES ($1, $2, $3, $4)
+	`
+	for _, tier := range card.FeeTiers {
+		_, err := tx.ExecContext(ctx, tierQuery, card.ID, tier.MinOTR, tier.FeeAmount, tier.FeeRate)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *rateCardRepository) FindByID(ctx context.Context, id int64) (*model.RateCard, error) {
+	query := `SELECT ` + rateCardColumns + ` FROM rate_cards WHERE id = $1`
+	return r.findOne(ctx, r.db, query, id)
+}
+
+// FindLatestAndLock returns the highest version for the product, tenor and
+// grade and locks it so concurrent writers queue behind each other.
+func (r *rateCardRepository) FindLatestAndLock(ctx context.Context, tx *sql.Tx, productCode string, tenor int, riskGrade string) (*model.RateCard, error) {
+	query := `SELECT ` + rateCardColumns + ` FROM rate_cards
+  WHERE product_code = $1 AND tenor = $2 AND risk_grade = $3
+  ORDER BY version DESC LIMIT 1 FOR UPDATE`
+	return r.findOne(ctx, tx, query, productCode, tenor, riskGrade)
+}
+
+// FindEffective returns the card in force at the given time. It reads through
+// tx when one is given.
+func (r *rateCardRepository) FindEffective(ctx context.Context, tx *sql.Tx, productCode string, tenor int, riskGrade string, at time.Time) (*model.RateCard, error) {
+	query := `SELECT ` + rateCardColumns + ` FROM rate_cards
+  WHERE product_code = $1 AND tenor = $2 AND risk_grade = $3 AND effective_from <= $4 AND (effective_to IS NULL OR effective_to > $4)
+  ORDER BY effective_from DESC, version DESC LIMIT 1`
+
+	var q queryer = r.db
+	if tx != nil {
+		q = tx
+	}
+
+	return r.findOne(ctx, q, query, productCode, tenor, riskGrade, at)
+}
+
+func (r *rateCardRepository) Close(ctx context.Context, tx *sql.Tx, id int64, effectiveTo time.Time) error {
+	query := `UPDATE rate_cards SET effective_to = $2 WHERE id = $1`
+
+	_, err := tx.ExecContext(ctx, query, id, effectiveTo)
+	return err
+}
+
+func (r *rateCardRepository) List(ctx context.Context, filter model.RateCardFilter) ([]model.RateCard, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	if filter.ProductCode != "" {
+		args = append(args, filter.ProductCode)
+		conditions = append(conditions, fmt.Sprintf("product_code = $%d", len(args)))
+	}
+	if filter.Tenor != 0 {
+		args = append(args, filter.Tenor)
+		conditions = append(conditions, fmt.Sprintf("tenor = $%d", len(args)))
+	}
+	if filter.RiskGrade != "" {
+		args = append(args, filter.RiskGrade)
+		conditions = append(conditions, fmt.Sprintf("risk_grade = $%d", len(args)))
+	}
+
+	query := `SELECT ` + rateCardColumns + ` FROM rate_cards`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY product_code ASC, tenor ASC, risk_grade ASC, version DESC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cards := []model.RateCard{}
+	for rows.Next() {
+		card, err := scanRateCard(rows)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, *card)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	if err := loadFeeTiers(ctx, r.db, cards); err != nil {
+		return nil, err
+	}
+
+	return cards, nil
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/pkg/money"
+	"github.com/stretchr/testify/suite"
+)
+
+type rateCardRepositoryTestSuite struct {
+	suite.Suite
+	DB   *sql.DB
+	Mock sqlmock.Sqlmock
+	Repo RateCardRepository
+}
+
+func (s *rateCardRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	s.Require().NoError(err)
+	s.DB = db
+	s.Mock = mock
+	s.Repo = NewRateCardRepository(db)
+}
+
+func (s *rateCardRepositoryTestSuite) TearDownTest() {
+	s.DB.Close()
+}
+
+func rateCardRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{
+		"id", "product_code", "tenor", "risk_grade", "version", "interest_rate", "admin_fee_type", "effective_from", "effective_to", "created_by", "created_at",
+	})
+}
+
+func feeTierRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"rate_card_id", "min_otr", "fee_amount", "fee_rate"})
+}
+
+func (s *rateCardRepositoryTestSuite) TestCreate_Success() {
+	ctx := context.Background()
+	effectiveFrom := time.Now()
+	createdBy := "admin"
+
+	s.Mock.ExpectBegin()
+	tx, err := s.DB.Begin()
+	s.Require().NoError(err)
+
+	card := &model.RateCard{
+		ProductCode:   "white_goods",
+		Tenor:         6,
+		RiskGrade:     model.RiskGradeB,
+		Version:       2,
+		InterestRate:  money.MustParseRate("0.0175"),
+		AdminFeeType:  model.AdminFeeTypeTiered,
+		EffectiveFrom: effectiveFrom,
+		CreatedBy:     &createdBy,
+		FeeTiers: []model.AdminFeeTier{
+			{MinOTR: money.Zero, FeeAmount: money.FromRupiah(150000)},
+			{MinOTR: money.FromRupiah(5000000), FeeRate: money.MustParseRate("0.02")},
+		},
+	}
+
+	s.Mock.ExpectQuery(`INSERT INTO rate_cards \(product_code, tenor, risk_grade, version, interest_rate, admin_fee_type, effective_from, created_by\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, NULLIF\(\$8, ''\)\) RETURNING id, created_at`).
+		WithArgs("white_goods", 6, "B", 2, card.InterestRate, "TIERED", effectiveFrom, "admin").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(int64(12), effectiveFrom))
+	s.Mock.ExpectExec(`INSERT INTO rate_card_fee_tiers \(rate_card_id, min_otr, fee_amount, fee_rate\) VALUES \(\$1, \$2, \$3, \$4\)`).
+		WithArgs(int64(12), money.Zero, money.FromRupiah(150000), money.Rate(0)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.Mock.ExpectExec(`INSERT INTO rate_card_fee_tiers \(rate_card_id, min_otr, fee_amount, fee_rate\) VALUES \(\$1, \$2, \$3, \$4\)`).
+		WithArgs(int64(12), money.FromRupiah(5000000), money.Zero, money.MustParseRate("0.02")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = s.Repo.Create(ctx, tx, card)
+
+	s.Require().NoError(err)
+	s.Equal(int64(12), card.ID)
+	s.Require().NoError(s.Mock.ExpectationsWereMet())
+}
+
+func (s *rateCardRepositoryTestSuite) TestFindEffective_Success() {
+	ctx := context.Background()
+	at := time.Now()
+
+	s.Mock.ExpectQuery(`SELECT id, product_code, tenor, risk_grade, version, interest_rate, admin_fee_type, effective_from, effective_to, created_by, created_at FROM rate_cards WHERE product_code = \$1 AND tenor = \$2 AND risk_grade = \$3 AND effective_from <= \$4 AND \(effective_to IS NULL OR effective_to > \$4\) ORDER BY effective_from DESC, version DESC LIMIT 1`).
+		WithArgs("white_goods", 6, "B", at).
+		WillReturnRows(rateCardRows().AddRow(int64(12), "white_goods", 6, "B", 2, "0.017500", "TIERED", at, nil, "admin", at))
+	s.Mock.ExpectQuery(`SELECT rate_card_id, min_otr, fee_amount, fee_rate FROM rate_card_fee_tiers WHERE rate_card_id = ANY\(\$1\) ORDER BY rate_card_id ASC, min_otr ASC`).
+		WillReturnRows(feeTierRows().
+			AddRow(int64(12), "0.00", "150000.00", "0.000000").
+			AddRow(int64(12), "5000000.00", "0.00", "0.020000"))
+
+	card, err := s.Repo.FindEffective(ctx, nil, "white_goods", 6, "B", at)
+
+	s.Require().NoError(err)
+	s.Require().NotNil(card)
+	s.Equal(2, card.Version)
+	s.Equal(money.MustParseRate("0.0175"), card.InterestRate)
+	s.Nil(card.EffectiveTo)
+	s.Require().Len(card.FeeTiers, 2)
+	s.Equal(money.FromRupiah(150000), card.FeeTiers[0].FeeAmount)
+	s.Equal(money.MustParseRate("0.02"), card.FeeTiers[1].FeeRate)
+}
+
+func (s *rateCardRepositoryTestSuite) TestFindEffective_NotFound() {
+	ctx := context.Background()
+	at := time.Now()
+
+	s.Mock.ExpectQuery(`SELECT .* FROM rate_cards WHERE product_code = \$1`).
+		WithArgs("paylater", 12, "A", at).
+		WillReturnError(sql.ErrNoRows)
+
+	card, err := s.Repo.FindEffective(ctx, nil, "paylater", 12, "A", at)
+
+	s.Require().NoError(err)
+	s.Nil(card)
+}
+
+func (s *rateCardRepositoryTestSuite) TestFindLatestAndLock_Success() {
+	ctx := context.Background()
+	now := time.Now()
+
+	s.Mock.ExpectBegin()
+	tx, err := s.DB.Begin()
+	s.Require().NoError(err)
+
+	s.Mock.ExpectQuery(`SELECT .* FROM rate_cards WHERE product_code = \$1 AND tenor = \$2 AND risk_grade = \$3 ORDER BY version DESC LIMIT 1 FOR UPDATE`).
+		WithArgs("paylater", 3, "A").
+		WillReturnRows(rateCardRows().AddRow(int64(4), "paylater", 3, "A", 1, "0.022500", "FLAT", now, nil, nil, now))
+	s.Mock.ExpectQuery(`SELECT rate_card_id, min_otr, fee_amount, fee_rate FROM rate_card_fee_tiers`).
+		WillReturnRows(feeTierRows().AddRow(int64(4), "0.00", "0.00", "0.030000"))
+
+	card, err := s.Repo.FindLatestAndLock(ctx, tx, "paylater", 3, "A")
+
+	s.Require().NoError(err)
+	s.Require().NotNil(card)
+	s.Equal(int64(4), card.ID)
+	s.Nil(card.CreatedBy)
+	s.Len(card.FeeTiers, 1)
+}
+
+func (s *rateCardRepositoryTestSuite) TestClose_Success() {
+	ctx := context.Background()
+	effectiveTo := time.Now()
+
+	s.Mock.ExpectBegin()
+	tx, err := s.DB.Begin()
+	s.Require().NoError(err)
+
+	s.Mock.ExpectExec(`UPDATE rate_cards SET effective_to = \$2 WHERE id = \$1`).
+		WithArgs(int64(4), effectiveTo).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = s.Repo.Close(ctx, tx, 4, effectiveTo)
+	s.Require().NoError(err)
+}
+
+func (s *rateCardRepositoryTestSuite) TestList_WithFilter() {
+	ctx := context.Background()
+	now := time.Now()
+
+	s.Mock.ExpectQuery(`SELECT .* FROM rate_cards WHERE product_code = \$1 AND tenor = \$2 ORDER BY product_code ASC, tenor ASC, risk_grade ASC, version DESC`).
+		WithArgs("paylater", 3).
+		WillReturnRows(rateCardRows().
+			AddRow(int64(5), "paylater", 3, "A", 2, "0.020000", "FLAT", now, nil, "admin", now).
+			AddRow(int64(4), "paylater", 3, "A", 1, "0.022500", "FLAT", now.Add(-time.Hour), now, nil, now))
+	s.Mock.ExpectQuery(`SELECT rate_card_id, min_otr, fee_amount, fee_rate FROM rate_card_fee_tiers`).
+		WillReturnRows(feeTierRows().
+			AddRow(int64(4), "0.00", "0.00", "0.030000").
+			AddRow(int64(5), "0.00", "0.00", "0.025000"))
+
+	cards, err := s.Repo.List(ctx, model.RateCardFilter{ProductCode: "paylater", Tenor: 3})
+
+	s.Require().NoError(err)
+	s.Require().Len(cards, 2)
+	s.Equal(2, cards[0].Version)
+	s.Equal(money.MustParseRate("0.025"), cards[0].FeeTiers[0].FeeRate)
+	s.NotNil(cards[1].EffectiveTo)
+	s.Equal(money.MustParseRate("0.03"), cards[1].FeeTiers[0].FeeRate)
+}
+
+func TestRateCardRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(rateCardRepositoryTestSuite))
+}
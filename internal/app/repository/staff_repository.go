@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+)
+
+type StaffRepository interface {
+	Create(ctx context.Context, staff *model.Staff) error
+	FindByUsername(ctx context.Context, username string) (*model.Staff, error)
+}
+
+type staffRepository struct {
+	db *sql.DB
+}
+
+func NewStaffRepository(db *sql.DB) StaffRepository {
+	return &staffRepository{db: db}
+}
+
+func (r *staffRepository) Create(ctx context.Context, staff *model.Staff) error {
+	query := `
+		INSERT INTO staffs (username, password_hash, full_name, role, merchant_id)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.db.QueryRowContext(ctx, query,
+		staff.Username,
+		staff.PasswordHash,
+		staff.FullName,
+		staff.Role,
+		staff.MerchantID,
+	).Scan(&staff.ID, &staff.CreatedAt, &staff.UpdatedAt)
+}
+
+func (r *staffRepository) FindByUsername(ctx context.Context, username string) (*model.Staff, error) {
+	staff := &model.Staff{}
+	var merchantID sql.NullInt64
+	query := `SELECT id, username, password_hash, full_name, role, merchant_id, created_at, updated_at
+  FROM staffs WHERE username = $1`
+	err := r.db.QueryRowContext(ctx, query, username).Scan(&staff.ID, &staff.Username, &staff.PasswordHash, &staff.FullName, &staff.Role, &merchantID, &staff.CreatedAt, &staff.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if merchantID.Valid {
+		staff.MerchantID = &merchantID.Int64
+	}
+	return staff, nil
+}
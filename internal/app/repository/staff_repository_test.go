@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/stretchr/testify/suite"
+)
+
+type staffRepositoryTestSuite struct {
+	suite.Suite
+	DB   *sql.DB
+	Mock sqlmock.Sqlmock
+	Repo StaffRepository
+}
+
+func (s *staffRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	s.Require().NoError(err)
+	s.DB = db
+	s.Mock = mock
+	s.Repo = NewStaffRepository(db)
+}
+
+func (s *staffRepositoryTestSuite) TearDownTest() {
+	s.DB.Close()
+}
+
+func (s *staffRepositoryTestSuite) TestCreate_Success() {
+	dummyTime := time.Now()
+	merchantID := int64(3)
+	staff := &model.Staff{
+		Username:     "cashier",
+		PasswordHash: "hashed-password",
+		FullName:     "Elektro Jaya Cashier",
+		Role:         model.StaffRoleMerchant,
+		MerchantID:   &merchantID,
+	}
+
+	s.Mock.ExpectQuery(`INSERT INTO staffs \(username, password_hash, full_name, role, merchant_id\) VALUES \(\$1, \$2, \$3, \$4, \$5\) RETURNING id, created_at, updated_at`).
+		WithArgs("cashier", "hashed-password", "Elektro Jaya Cashier", model.StaffRoleMerchant, &merchantID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(int64(4), dummyTime, dummyTime))
+
+	err := s.Repo.Create(context.Background(), staff)
+
+	s.Require().NoError(err)
+	s.Equal(int64(4), staff.ID)
+	s.NoError(s.Mock.ExpectationsWereMet())
+}
+
+func (s *staffRepositoryTestSuite) TestFindByUsername_Success() {
+	dummyTime := time.Now()
+
+	rows := sqlmock.NewRows([]string{
+		"id", "username", "password_hash", "full_name", "role", "merchant_id", "created_at", "updated_at",
+	}).AddRow(
+		1, "admin", "hashed-password", "Administrator", "admin", nil, dummyTime, dummyTime,
+	)
+
+	s.Mock.ExpectQuery(`SELECT id, username, password_hash, full_name, role, merchant_id, created_at, updated_at FROM staffs WHERE username = \$1`).
+		WithArgs("admin").
+		WillReturnRows(rows)
+
+	staff, err := s.Repo.FindByUsername(context.Background(), "admin")
+
+	s.Require().NoError(err)
+	s.Require().NotNil(staff)
+	s.Equal(int64(1), staff.ID)
+	s.Equal("admin", staff.Username)
+	s.Equal("hashed-password", staff.PasswordHash)
+	s.Equal("admin", staff.Role)
+	s.Nil(staff.MerchantID)
+}
+
+func (s *staffRepositoryTestSuite) TestFindByUsername_MerchantStaff() {
+	dummyTime := time.Now()
+
+	rows := sqlmock.NewRows([]string{
+		"id", "username", "password_hash", "full_name", "role", "merchant_id", "created_at", "updated_at",
+	}).AddRow(
+		3, "merchant", "hashed-password", "Cashier", "merchant", int64(7), dummyTime, dummyTime,
+	)
+
+	s.Mock.ExpectQuery(`SELECT id, username, password_hash, full_name, role, merchant_id, created_at, updated_at FROM staffs WHERE username = \$1`).
+		WithArgs("merchant").
+		WillReturnRows(rows)
+
+	staff, err := s.Repo.FindByUsername(context.Background(), "merchant")
+
+	s.Require().NoError(err)
+	s.Require().NotNil(staff)
+	s.Equal("merchant", staff.Role)
+	s.Require().NotNil(staff.MerchantID)
+	s.Equal(int64(7), *staff.MerchantID)
+}
+
+func (s *staffRepositoryTestSuite) TestFindByUsername_NotFound() {
+	s.Mock.ExpectQuery(`SELECT id, username, password_hash, full_name, role, merchant_id, created_at, updated_at FROM staffs WHERE username = \$1`).
+		WithArgs("ghost").
+		WillReturnError(sql.ErrNoRows)
+
+	staff, err := s.Repo.FindByUsername(context.Background(), "ghost")
+
+	s.Require().NoError(err)
+	s.Nil(staff)
+}
+
+func TestStaffRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(staffRepositoryTestSuite))
+}
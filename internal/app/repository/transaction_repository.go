@@ -3,13 +3,25 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/pkg/money"
 )
 
 type TransactionRepository interface {
 	Save(ctx context.Context, tx *sql.Tx, transaction *model.Transaction) error
-	GetActiveTransactionSumByNIK(ctx context.Context, tx *sql.Tx, nik string) (float64, error)
+	FindByNomorKontrak(ctx context.Context, nomorKontrak string) (*model.Transaction, error)
+	FindAndLockByNomorKontrak(ctx context.Context, tx *sql.Tx, nomorKontrak string) (*model.Transaction, error)
+	ApplyPayment(ctx context.Context, tx *sql.Tx, nomorKontrak string, principal money.Amount, amount money.Amount) error
+	ClearOutstanding(ctx context.Context, tx *sql.Tx, nomorKontrak string) error
+	UpdateStatus(ctx context.Context, tx *sql.Tx, nomorKontrak string, status string) error
+	NextContractSequence(ctx context.Context, tx *sql.Tx) (int64, error)
+	Search(ctx context.Context, filter model.TransactionHistoryFilter) ([]model.TransactionSummary, error)
+	GetUsageByNIK(ctx context.Context, tx *sql.Tx, nik string) ([]model.TenorUsage, error)
+	GetActiveMonthlyObligationByNIK(ctx context.Context, tx *sql.Tx, nik string) (money.Amount, error)
 }
 
 type transactionRepository struct {
@@ -22,8 +34,8 @@ func NewTransactionRepository(db *sql.DB) TransactionRepository {
 
 func (r *transactionRepository) Save(ctx context.Context, tx *sql.Tx, transaction *model.Transaction) error {
 	query := `
-		INSERT INTO transactions (nomor_kontrak, consumer_nik, otr, admin_fee, jumlah_cicilan, jumlah_bunga, nama_asset, status)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO transactions (nomor_kontrak, consumer_nik, otr, admin_fee, jumlah_cicilan, jumlah_bunga, nama_asset, status, product_code, rate_card_id, merchant_id, outlet_id, mdr_amount, outstanding_principal)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''), $10, $11, $12, $13, $3)
 	`
 
 	_, err := tx.ExecContext(ctx, query,
@@ -35,21 +47,245 @@ func (r *transactionRepository) Save(ctx context.Context, tx *sql.Tx, transactio
 		transaction.JumlahBunga,
 		transaction.NamaAsset,
 		transaction.Status,
+		transaction.ProductCode,
+		transaction.RateCardID,
+		transaction.MerchantID,
+		transaction.OutletID,
+		transaction.MDRAmount,
 	)
 	return err
 }
 
-func (r *transactionRepository) GetActiveTransactionSumByNIK(ctx context.Context, tx *sql.Tx, nik string) (float64, error) {
+const transactionColumns = `nomor_kontrak, consumer_nik, otr, admin_fee, jumlah_cicilan, jumlah_bunga, nama_asset, status, COALESCE(product_code, ''), rate_card_id,
+  merchant_id, outlet_id, mdr_amount, outstanding_principal, total_paid, created_at, updated_at`
+
+func scanTransaction(row rowScanner) (*model.Transaction, error) {
+	transaction := &model.Transaction{}
+	var rateCardID, merchantID, outletID sql.NullInt64
+	err := row.Scan(&transaction.NomorKontrak, &transaction.ConsumerNIK, &transaction.OTR, &transaction.AdminFee, &transaction.JumlahCicilan, &transaction.JumlahBunga, &transaction.NamaAsset, &transaction.Status, &transaction.ProductCode, &rateCardID,
+		&merchantID, &outletID, &transaction.MDRAmount, &transaction.OutstandingPrincipal, &transaction.TotalPaid, &transaction.CreatedAt, &transaction.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if rateCardID.Valid {
+		transaction.RateCardID = &rateCardID.Int64
+	}
+	if merchantID.Valid {
+		transaction.MerchantID = &merchantID.Int64
+	}
+	if outletID.Valid {
+		transaction.OutletID = &outletID.Int64
+	}
+	return transaction, nil
+}
+
+func (r *transactionRepository) FindByNomorKontrak(ctx context.Context, nomorKontrak string) (*model.Transaction, error) {
+	query := `SELECT ` + transactionColumns + ` FROM transactions WHERE nomor_kontrak = $1`
+
+	transaction, err := scanTransaction(r.db.QueryRowContext(ctx, query, nomorKontrak))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return transaction, nil
+}
+
+func (r *transactionRepository) FindAndLockByNomorKontrak(ctx context.Context, tx *sql.Tx, nomorKontrak string) (*model.Transaction, error) {
+	query := `SELECT ` + transactionColumns + ` FROM transactions WHERE nomor_kontrak = $1 FOR UPDATE`
+
+	transaction, err := scanTransaction(tx.QueryRowContext(ctx, query, nomorKontrak))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// ApplyPayment moves the contract balances by the principal repaid and the
+// total amount allocated by a payment.
+func (r *transactionRepository) ApplyPayment(ctx context.Context, tx *sql.Tx, nomorKontrak string, principal money.Amount, amount money.Amount) error {
+	query := `
+		UPDATE transactions
+		SET outstanding_principal = outstanding_principal - $1, total_paid = total_paid + $2
+		WHERE nomor_kontrak = $3
+	`
+
+	_, err := tx.ExecContext(ctx, query, principal, amount, nomorKontrak)
+	return err
+}
+
+// ClearOutstanding writes the outstanding principal of a cancelled contract
+// down to zero.
+func (r *transactionRepository) ClearOutstanding(ctx context.Context, tx *sql.Tx, nomorKontrak string) error {
+	query := `UPDATE transactions SET outstanding_principal = 0 WHERE nomor_kontrak = $1`
+
+	_, err := tx.ExecContext(ctx, query, nomorKontrak)
+	return err
+}
+
+func (r *transactionRepository) UpdateStatus(ctx context.Context, tx *sql.Tx, nomorKontrak string, status string) error {
+	query := `UPDATE transactions SET status = $1 WHERE nomor_kontrak = $2`
+
+	_, err := tx.ExecContext(ctx, query, status, nomorKontrak)
+	return err
+}
+
+// NextContractSequence draws the next contract number sequence. Values are
+// never handed out twice, even when the transaction rolls back.
+func (r *transactionRepository) NextContractSequence(ctx context.Context, tx *sql.Tx) (int64, error) {
+	var sequence int64
+	err := tx.QueryRowContext(ctx, `SELECT nextval('contract_number_seq')`).Scan(&sequence)
+	return sequence, err
+}
+
+// Search lists one page of the contracts of a consumer, a merchant, or both.
+// At least one of them must be given so a caller can never list every
+// contract. Pages are keyed on the sort column with nomor_kontrak as the
+// tie-breaker.
+func (r *transactionRepository) Search(ctx context.Context, filter model.TransactionHistoryFilter) ([]model.TransactionSummary, error) {
+	if filter.ConsumerNIK == "" && filter.MerchantID == 0 {
+		return nil, errors.New("search needs a consumer or a merchant")
+	}
+
+	var args []interface{}
+	addArg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	var conditions []string
+	if filter.ConsumerNIK != "" {
+		conditions = append(conditions, "t.consumer_nik = "+addArg(filter.ConsumerNIK))
+	}
+	if filter.MerchantID != 0 {
+		conditions = append(conditions, "t.merchant_id = "+addArg(filter.MerchantID))
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "t.status = "+addArg(filter.Status))
+	}
+	if filter.CreatedFrom != nil {
+		conditions = append(conditions, "t.created_at >= "+addArg(*filter.CreatedFrom))
+	}
+	if filter.CreatedTo != nil {
+		conditions = append(conditions, "t.created_at < "+addArg(*filter.CreatedTo))
+	}
+	if filter.Asset != "" {
+		conditions = append(conditions, "LOWER(t.nama_asset) LIKE "+addArg("%"+escapeLike(strings.ToLower(filter.Asset))+"%"))
+	}
+
+	sortColumn := "t.created_at"
+	cursorCast := "::timestamp"
+	if filter.SortBy == model.TransactionSortByOTR {
+		sortColumn = "t.otr"
+		cursorCast = "::numeric"
+	}
+	direction, comparator := "ASC", ">"
+	if filter.SortOrder == model.SortOrderDesc {
+		direction, comparator = "DESC", "<"
+	}
+
+	if filter.Cursor != nil {
+		conditions = append(conditions, fmt.Sprintf("(%s, t.nomor_kontrak) %s (%s%s, %s)",
+			sortColumn, comparator, addArg(filter.Cursor.SortValue), cursorCast, addArg(filter.Cursor.Key)))
+	}
+
+	query := `SELECT t.nomor_kontrak, COALESCE(t.product_code, ''), t.otr, t.admin_fee, t.jumlah_cicilan, t.jumlah_bunga, t.nama_asset, t.status,
+  t.merchant_id, t.outlet_id, t.outstanding_principal, t.total_paid, t.created_at,
+  COALESCE((SELECT SUM(i.total_amount + i.penalty - i.paid_principal - i.paid_interest - i.paid_admin_fee - i.paid_penalty)
+    FROM installments i WHERE i.nomor_kontrak = t.nomor_kontrak AND i.status IN ('UNPAID', 'PARTIAL')), 0) AS outstanding_balance
+  FROM transactions t WHERE ` + strings.Join(conditions, " AND ")
+	query += fmt.Sprintf(" ORDER BY %s %s, t.nomor_kontrak %s LIMIT %s", sortColumn, direction, direction, addArg(filter.Limit))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transactions := []model.TransactionSummary{}
+	for rows.Next() {
+		transaction := model.TransactionSummary{}
+		var merchantID, outletID sql.NullInt64
+		if err := rows.Scan(&transaction.NomorKontrak, &transaction.ProductCode, &transaction.OTR, &transaction.AdminFee, &transaction.JumlahCicilan, &transaction.JumlahBunga, &transaction.NamaAsset, &transaction.Status,
+			&merchantID, &outletID, &transaction.OutstandingPrincipal, &transaction.TotalPaid, &transaction.CreatedAt, &transaction.OutstandingBalance); err != nil {
+			return nil, err
+		}
+		if merchantID.Valid {
+			transaction.MerchantID = &merchantID.Int64
+		}
+		if outletID.Valid {
+			transaction.OutletID = &outletID.Int64
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// GetUsageByNIK sums the principal of active and defaulted contracts and of
+// contracts awaiting disbursement per tenor. Only contracts in a final status
+// no longer use the limit, so curing a default never takes the consumer over
+// their limit. It reads through tx when one is given so the limit check sees
+// the rows locked by the caller.
+func (r *transactionRepository) GetUsageByNIK(ctx context.Context, tx *sql.Tx, nik string) ([]model.TenorUsage, error) {
+	query := `
+    SELECT jumlah_cicilan,
+      COALESCE(SUM(otr) FILTER (WHERE status IN ('ACTIVE', 'DEFAULTED')), 0),
+      COALESCE(SUM(otr) FILTER (WHERE status = 'PENDING_DISBURSEMENT'), 0)
+    FROM transactions
+    WHERE consumer_nik = $1 AND status IN ('ACTIVE', 'DEFAULTED', 'PENDING_DISBURSEMENT')
+    GROUP BY jumlah_cicilan ORDER BY jumlah_cicilan ASC
+  `
+
+	var q queryer = r.db
+	if tx != nil {
+		q = tx
+	}
+
+	rows, err := q.QueryContext(ctx, query, nik)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var usages []model.TenorUsage
+	for rows.Next() {
+		usage := model.TenorUsage{}
+		if err := rows.Scan(&usage.Tenor, &usage.Used, &usage.Pending); err != nil {
+			return nil, err
+		}
+		usages = append(usages, usage)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return usages, nil
+}
+
+// GetActiveMonthlyObligationByNIK sums the monthly installments of every
+// contract the consumer still owes on: the same statuses GetUsageByNIK counts
+// against the limit, so a default never makes a consumer look less indebted.
+func (r *transactionRepository) GetActiveMonthlyObligationByNIK(ctx context.Context, tx *sql.Tx, nik string) (money.Amount, error) {
 	query := `
-    SELECT COALESCE(SUM(otr), 0) FROM transactions
-    WHERE consumer_nik = $1 AND status = 'ACTIVE'
+    SELECT COALESCE(SUM((otr + jumlah_bunga) / jumlah_cicilan), 0) FROM transactions
+    WHERE consumer_nik = $1 AND status IN ('ACTIVE', 'DEFAULTED', 'PENDING_DISBURSEMENT') AND jumlah_cicilan > 0
   `
 
-	var sum float64
-	err := tx.QueryRowContext(ctx, query, nik).Scan(&sum)
+	var obligation money.Amount
+	err := tx.QueryRowContext(ctx, query, nik).Scan(&obligation)
 	if err != nil {
 		return 0, err
 	}
 
-	return sum, nil
+	return obligation, nil
 }
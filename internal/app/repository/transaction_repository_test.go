@@ -4,9 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/pkg/money"
+	"github.com/glennprays/xyz-fin/pkg/pagination"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -36,19 +39,26 @@ func (s *transactionRepositoryTestSuite) TestSave_Success() {
 	tx, err := s.DB.Begin()
 	s.Require().NoError(err)
 
+	rateCardID := int64(7)
+	merchantID, outletID := int64(3), int64(9)
 	transaction := &model.Transaction{
 		NomorKontrak:  "TRX12345",
 		ConsumerNIK:   "1234567890",
-		OTR:           100000000,
-		AdminFee:      500000,
+		OTR:           money.MustParse("100000000.00"),
+		AdminFee:      money.MustParse("500000.00"),
 		JumlahCicilan: 24,
-		JumlahBunga:   5.5,
+		JumlahBunga:   money.MustParse("5.50"),
 		NamaAsset:     "Motor Beat",
 		Status:        "pending",
+		ProductCode:   "motorcycle",
+		RateCardID:    &rateCardID,
+		MerchantID:    &merchantID,
+		OutletID:      &outletID,
+		MDRAmount:     money.MustParse("1500000.00"),
 	}
 
-	query := `INSERT INTO transactions \(nomor_kontrak, consumer_nik, otr, admin_fee, jumlah_cicilan, jumlah_bunga, nama_asset, status\) 
-			  VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8\)`
+	query := `INSERT INTO transactions \(nomor_kontrak, consumer_nik, otr, admin_fee, jumlah_cicilan, jumlah_bunga, nama_asset, status, product_code, rate_card_id, merchant_id, outlet_id, mdr_amount, outstanding_principal\) 
+			  VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8, NULLIF\(\$9, ''\), \$10, \$11, \$12, \$13, \$3\)`
 
 	s.Mock.ExpectExec(query).
 		WithArgs(
@@ -60,6 +70,11 @@ func (s *transactionRepositoryTestSuite) TestSave_Success() {
 			transaction.JumlahBunga,
 			transaction.NamaAsset,
 			transaction.Status,
+			transaction.ProductCode,
+			transaction.RateCardID,
+			transaction.MerchantID,
+			transaction.OutletID,
+			transaction.MDRAmount,
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
@@ -80,16 +95,16 @@ func (s *transactionRepositoryTestSuite) TestSave_Error() {
 	transaction := &model.Transaction{
 		NomorKontrak:  "TRX54321",
 		ConsumerNIK:   "0987654321",
-		OTR:           200000000,
-		AdminFee:      1000000,
+		OTR:           money.MustParse("200000000.00"),
+		AdminFee:      money.MustParse("1000000.00"),
 		JumlahCicilan: 36,
-		JumlahBunga:   6.5,
+		JumlahBunga:   money.MustParse("6.50"),
 		NamaAsset:     "Mobil Avanza",
 		Status:        "pending",
 	}
 
-	query := `INSERT INTO transactions \(nomor_kontrak, consumer_nik, otr, admin_fee, jumlah_cicilan, jumlah_bunga, nama_asset, status\) 
-			  VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8\)`
+	query := `INSERT INTO transactions \(nomor_kontrak, consumer_nik, otr, admin_fee, jumlah_cicilan, jumlah_bunga, nama_asset, status, product_code, rate_card_id, merchant_id, outlet_id, mdr_amount, outstanding_principal\) 
+			  VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8, NULLIF\(\$9, ''\), \$10, \$11, \$12, \$13, \$3\)`
 
 	s.Mock.ExpectExec(query).
 		WithArgs(
@@ -101,6 +116,11 @@ func (s *transactionRepositoryTestSuite) TestSave_Error() {
 			transaction.JumlahBunga,
 			transaction.NamaAsset,
 			transaction.Status,
+			transaction.ProductCode,
+			transaction.RateCardID,
+			transaction.MerchantID,
+			transaction.OutletID,
+			transaction.MDRAmount,
 		).
 		WillReturnError(sql.ErrConnDone)
 
@@ -113,49 +133,305 @@ func (s *transactionRepositoryTestSuite) TestSave_Error() {
 	s.Require().NoError(err)
 }
 
-func (s *transactionRepositoryTestSuite) TestGetActiveTransactionSumByNIK_Success() {
+func (s *transactionRepositoryTestSuite) TestFindByNomorKontrak_Success() {
+	dummyTime := time.Now()
+
+	rows := sqlmock.NewRows([]string{
+		"nomor_kontrak", "consumer_nik", "otr", "admin_fee", "jumlah_cicilan", "jumlah_bunga", "nama_asset", "status", "product_code", "rate_card_id", "merchant_id", "outlet_id", "mdr_amount", "outstanding_principal", "total_paid", "created_at", "updated_at",
+	}).AddRow(
+		"TRX12345", "1234567890", 1000000.00, 30000.00, 6, 50000.00, "Motor Beat", "ACTIVE", "motorcycle", int64(7), int64(3), nil, 15000.00, 750000.00, 280000.00, dummyTime, dummyTime,
+	)
+
+	s.Mock.ExpectQuery(`SELECT nomor_kontrak, consumer_nik, otr, admin_fee, jumlah_cicilan, jumlah_bunga, nama_asset, status, COALESCE\(product_code, ''\), rate_card_id, merchant_id, outlet_id, mdr_amount, outstanding_principal, total_paid, created_at, updated_at FROM transactions WHERE nomor_kontrak = \$1`).
+		WithArgs("TRX12345").
+		WillReturnRows(rows)
+
+	transaction, err := s.Repo.FindByNomorKontrak(context.Background(), "TRX12345")
+
+	s.Require().NoError(err)
+	s.Require().NotNil(transaction)
+	s.Equal("1234567890", transaction.ConsumerNIK)
+	s.Equal(6, transaction.JumlahCicilan)
+	s.Equal(money.MustParse("1000000.00"), transaction.OTR)
+	s.Equal("motorcycle", transaction.ProductCode)
+	s.Require().NotNil(transaction.RateCardID)
+	s.Equal(int64(7), *transaction.RateCardID)
+	s.Require().NotNil(transaction.MerchantID)
+	s.Equal(int64(3), *transaction.MerchantID)
+	s.Nil(transaction.OutletID)
+	s.Equal(money.MustParse("15000.00"), transaction.MDRAmount)
+	s.Equal(money.MustParse("750000.00"), transaction.OutstandingPrincipal)
+	s.Equal(money.MustParse("280000.00"), transaction.TotalPaid)
+}
+
+func (s *transactionRepositoryTestSuite) TestFindAndLockByNomorKontrak_NotFound() {
+	s.Mock.ExpectBegin()
+	tx, err := s.DB.Begin()
+	s.Require().NoError(err)
+
+	s.Mock.ExpectQuery(`SELECT .* FROM transactions WHERE nomor_kontrak = \$1 FOR UPDATE`).
+		WithArgs("TRXMISSING").
+		WillReturnError(sql.ErrNoRows)
+
+	transaction, err := s.Repo.FindAndLockByNomorKontrak(context.Background(), tx, "TRXMISSING")
+
+	s.Require().NoError(err)
+	s.Nil(transaction)
+}
+
+func (s *transactionRepositoryTestSuite) TestUpdateStatus_Success() {
+	s.Mock.ExpectBegin()
+	tx, err := s.DB.Begin()
+	s.Require().NoError(err)
+
+	s.Mock.ExpectExec(`UPDATE transactions SET status = \$1 WHERE nomor_kontrak = \$2`).
+		WithArgs(model.TransactionStatusPaidOff, "TRX12345").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = s.Repo.UpdateStatus(context.Background(), tx, "TRX12345", model.TransactionStatusPaidOff)
+
+	s.Require().NoError(err)
+	s.Require().NoError(s.Mock.ExpectationsWereMet())
+}
+
+func (s *transactionRepositoryTestSuite) TestApplyPayment_Success() {
+	s.Mock.ExpectBegin()
+	tx, err := s.DB.Begin()
+	s.Require().NoError(err)
+
+	s.Mock.ExpectExec(`UPDATE transactions SET outstanding_principal = outstanding_principal - \$1, total_paid = total_paid \+ \$2 WHERE nomor_kontrak = \$3`).
+		WithArgs(money.FromRupiah(333333), money.FromRupiah(368333), "TRX12345").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = s.Repo.ApplyPayment(context.Background(), tx, "TRX12345", money.FromRupiah(333333), money.FromRupiah(368333))
+
+	s.Require().NoError(err)
+	s.Require().NoError(s.Mock.ExpectationsWereMet())
+}
+
+func (s *transactionRepositoryTestSuite) TestClearOutstanding_Success() {
+	s.Mock.ExpectBegin()
+	tx, err := s.DB.Begin()
+	s.Require().NoError(err)
+
+	s.Mock.ExpectExec(`UPDATE transactions SET outstanding_principal = 0 WHERE nomor_kontrak = \$1`).
+		WithArgs("TRX12345").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = s.Repo.ClearOutstanding(context.Background(), tx, "TRX12345")
+
+	s.Require().NoError(err)
+	s.Require().NoError(s.Mock.ExpectationsWereMet())
+}
+
+func (s *transactionRepositoryTestSuite) TestNextContractSequence_Success() {
+	s.Mock.ExpectBegin()
+	tx, err := s.DB.Begin()
+	s.Require().NoError(err)
+
+	s.Mock.ExpectQuery(`SELECT nextval\('contract_number_seq'\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"nextval"}).AddRow(int64(12)))
+
+	sequence, err := s.Repo.NextContractSequence(context.Background(), tx)
+
+	s.Require().NoError(err)
+	s.Equal(int64(12), sequence)
+}
+
+func transactionSummaryRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{
+		"nomor_kontrak", "product_code", "otr", "admin_fee", "jumlah_cicilan", "jumlah_bunga", "nama_asset", "status",
+		"merchant_id", "outlet_id", "outstanding_principal", "total_paid", "created_at", "outstanding_balance",
+	})
+}
+
+func (s *transactionRepositoryTestSuite) TestSearch_WithFilters() {
+	createdFrom := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	createdTo := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2025, 4, 10, 9, 0, 0, 0, time.UTC)
+
+	s.Mock.ExpectQuery(`SELECT t.nomor_kontrak, COALESCE\(t.product_code, ''\), t.otr, t.admin_fee, t.jumlah_cicilan, t.jumlah_bunga, t.nama_asset, t.status, t.merchant_id, t.outlet_id, t.outstanding_principal, t.total_paid, t.created_at, COALESCE\(\(SELECT SUM\(i.total_amount \+ i.penalty - i.paid_principal - i.paid_interest - i.paid_admin_fee - i.paid_penalty\) FROM installments i WHERE i.nomor_kontrak = t.nomor_kontrak AND i.status IN \('UNPAID', 'PARTIAL'\)\), 0\) AS outstanding_balance FROM transactions t WHERE t.consumer_nik = \$1 AND t.status = \$2 AND t.created_at >= \$3 AND t.created_at < \$4 AND LOWER\(t.nama_asset\) LIKE \$5 ORDER BY t.otr ASC, t.nomor_kontrak ASC LIMIT \$6`).
+		WithArgs("1234567890123456", "ACTIVE", createdFrom, createdTo, `%kulkas 100\%%`, 21).
+		WillReturnRows(transactionSummaryRows().
+			AddRow("WG-001-250410-00000012-Z", "white_goods", "3000000.00", "150000.00", 6, "189735.00", "Kulkas 100%", "ACTIVE", int64(3), int64(9), "2500000.00", "560000.00", createdAt, "2779625.00"))
+
+	transactions, err := s.Repo.Search(context.Background(), model.TransactionHistoryFilter{
+		ConsumerNIK: "1234567890123456",
+		Status:      model.TransactionStatusActive,
+		CreatedFrom: &createdFrom,
+		CreatedTo:   &createdTo,
+		Asset:       "Kulkas 100%",
+		SortBy:      model.TransactionSortByOTR,
+		SortOrder:   model.SortOrderAsc,
+		Limit:       21,
+	})
+
+	s.Require().NoError(err)
+	s.Require().Len(transactions, 1)
+	s.Equal("WG-001-250410-00000012-Z", transactions[0].NomorKontrak)
+	s.Equal(money.FromRupiah(2500000), transactions[0].OutstandingPrincipal)
+	s.Equal(money.FromRupiah(2779625), transactions[0].OutstandingBalance)
+	s.Equal(createdAt, transactions[0].CreatedAt)
+	s.Require().NotNil(transactions[0].OutletID)
+	s.Equal(int64(9), *transactions[0].OutletID)
+}
+
+func (s *transactionRepositoryTestSuite) TestSearch_ByMerchant() {
+	s.Mock.ExpectQuery(`SELECT .* FROM transactions t WHERE t.merchant_id = \$1 AND t.status = \$2 ORDER BY t.created_at DESC, t.nomor_kontrak DESC LIMIT \$3`).
+		WithArgs(int64(3), "ACTIVE", 21).
+		WillReturnRows(transactionSummaryRows())
+
+	transactions, err := s.Repo.Search(context.Background(), model.TransactionHistoryFilter{
+		MerchantID: 3,
+		Status:     model.TransactionStatusActive,
+		SortBy:     model.TransactionSortByCreatedAt,
+		SortOrder:  model.SortOrderDesc,
+		Limit:      21,
+	})
+
+	s.Require().NoError(err)
+	s.Empty(transactions)
+}
+
+func (s *transactionRepositoryTestSuite) TestSearch_RequiresConsumerOrMerchant() {
+	transactions, err := s.Repo.Search(context.Background(), model.TransactionHistoryFilter{
+		Status: model.TransactionStatusActive,
+		Limit:  21,
+	})
+
+	s.Require().Error(err)
+	s.Nil(transactions)
+	s.NoError(s.Mock.ExpectationsWereMet())
+}
+
+func (s *transactionRepositoryTestSuite) TestSearch_WithCursor() {
+	s.Mock.ExpectQuery(`SELECT .* FROM transactions t WHERE t.consumer_nik = \$1 AND \(t.created_at, t.nomor_kontrak\) < \(\$2::timestamp, \$3\) ORDER BY t.created_at DESC, t.nomor_kontrak DESC LIMIT \$4`).
+		WithArgs("1234567890123456", "2025-04-10T09:00:00", "WG-001-250410-00000012-Z", 11).
+		WillReturnRows(transactionSummaryRows())
+
+	transactions, err := s.Repo.Search(context.Background(), model.TransactionHistoryFilter{
+		ConsumerNIK: "1234567890123456",
+		SortBy:      model.TransactionSortByCreatedAt,
+		SortOrder:   model.SortOrderDesc,
+		Limit:       11,
+		Cursor:      &pagination.Cursor{SortValue: "2025-04-10T09:00:00", Key: "WG-001-250410-00000012-Z"},
+	})
+
+	s.Require().NoError(err)
+	s.Empty(transactions)
+	s.NotNil(transactions)
+}
+
+func (s *transactionRepositoryTestSuite) TestSearch_OTRCursor() {
+	s.Mock.ExpectQuery(`SELECT .* FROM transactions t WHERE t.consumer_nik = \$1 AND \(t.otr, t.nomor_kontrak\) > \(\$2::numeric, \$3\) ORDER BY t.otr ASC, t.nomor_kontrak ASC LIMIT \$4`).
+		WithArgs("1234567890123456", "3000000.00", "WG-001-250410-00000012-Z", 11).
+		WillReturnError(sql.ErrConnDone)
+
+	transactions, err := s.Repo.Search(context.Background(), model.TransactionHistoryFilter{
+		ConsumerNIK: "1234567890123456",
+		SortBy:      model.TransactionSortByOTR,
+		SortOrder:   model.SortOrderAsc,
+		Limit:       11,
+		Cursor:      &pagination.Cursor{SortValue: "3000000.00", Key: "WG-001-250410-00000012-Z"},
+	})
+
+	s.Require().Error(err)
+	s.Nil(transactions)
+}
+
+func (s *transactionRepositoryTestSuite) TestFindByNomorKontrak_NotFound() {
+	s.Mock.ExpectQuery(`SELECT .* FROM transactions WHERE nomor_kontrak = \$1`).
+		WithArgs("TRXMISSING").
+		WillReturnError(sql.ErrNoRows)
+
+	transaction, err := s.Repo.FindByNomorKontrak(context.Background(), "TRXMISSING")
+
+	s.Require().NoError(err)
+	s.Nil(transaction)
+}
+
+func (s *transactionRepositoryTestSuite) TestGetUsageByNIK_Success() {
 	ctx := context.Background()
 	s.Mock.ExpectBegin()
 	tx, err := s.DB.Begin()
 	s.Require().NoError(err)
 
 	nik := "1234567890"
-	query := `SELECT SUM\(otr\) FROM transactions WHERE consumer_nik = \$1 AND status = 'ACTIVE'`
+	query := `SELECT jumlah_cicilan, COALESCE\(SUM\(otr\) FILTER \(WHERE status IN \('ACTIVE', 'DEFAULTED'\)\), 0\), COALESCE\(SUM\(otr\) FILTER \(WHERE status = 'PENDING_DISBURSEMENT'\), 0\) FROM transactions WHERE consumer_nik = \$1 AND status IN \('ACTIVE', 'DEFAULTED', 'PENDING_DISBURSEMENT'\) GROUP BY jumlah_cicilan ORDER BY jumlah_cicilan ASC`
 
 	s.Mock.ExpectQuery(query).
 		WithArgs(nik).
-		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(100000000))
+		WillReturnRows(sqlmock.NewRows([]string{"jumlah_cicilan", "used", "pending"}).
+			AddRow(3, 100000000, 0).
+			AddRow(6, 50000000, 25000000))
 
-	sum, err := s.Repo.GetActiveTransactionSumByNIK(ctx, tx, nik)
+	usages, err := s.Repo.GetUsageByNIK(ctx, tx, nik)
 	s.Require().NoError(err)
-	s.Equal(100000000.0, sum)
+	s.Require().Len(usages, 2)
+	s.Equal(3, usages[0].Tenor)
+	s.Equal(money.MustParse("100000000.00"), usages[0].Used)
+	s.Equal(money.MustParse("25000000.00"), usages[1].Pending)
 
 	s.Mock.ExpectCommit()
 	err = tx.Commit()
 	s.Require().NoError(err)
 }
 
-func (s *transactionRepositoryTestSuite) TestGetActiveTransactionSumByNIK_Error() {
+func (s *transactionRepositoryTestSuite) TestGetUsageByNIK_WithoutTransaction() {
+	ctx := context.Background()
+	nik := "1234567890"
+
+	s.Mock.ExpectQuery(`SELECT jumlah_cicilan, .* FROM transactions WHERE consumer_nik = \$1`).
+		WithArgs(nik).
+		WillReturnError(sql.ErrConnDone)
+
+	usages, err := s.Repo.GetUsageByNIK(ctx, nil, nik)
+	s.Require().Error(err)
+	s.Equal(sql.ErrConnDone, err)
+	s.Nil(usages)
+}
+
+func (s *transactionRepositoryTestSuite) TestGetActiveMonthlyObligationByNIK_Success() {
 	ctx := context.Background()
 	s.Mock.ExpectBegin()
 	tx, err := s.DB.Begin()
 	s.Require().NoError(err)
 
 	nik := "1234567890"
-	query := `SELECT SUM\(otr\) FROM transactions WHERE consumer_nik = \$1 AND status = 'ACTIVE'`
+	query := `SELECT COALESCE\(SUM\(\(otr \+ jumlah_bunga\) / jumlah_cicilan\), 0\) FROM transactions WHERE consumer_nik = \$1 AND status IN \('ACTIVE', 'DEFAULTED', 'PENDING_DISBURSEMENT'\) AND jumlah_cicilan > 0`
 
 	s.Mock.ExpectQuery(query).
 		WithArgs(nik).
-		WillReturnError(sql.ErrConnDone)
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(350000))
 
-	sum, err := s.Repo.GetActiveTransactionSumByNIK(ctx, tx, nik)
-	s.Require().Error(err)
-	s.Equal(sql.ErrConnDone, err)
-	s.Equal(0.0, sum)
+	obligation, err := s.Repo.GetActiveMonthlyObligationByNIK(ctx, tx, nik)
+	s.Require().NoError(err)
+	s.Equal(money.MustParse("350000.00"), obligation)
 
-	s.Mock.ExpectRollback()
-	err = tx.Rollback()
+	s.Mock.ExpectCommit()
+	err = tx.Commit()
+	s.Require().NoError(err)
+}
+
+func (s *transactionRepositoryTestSuite) TestGetActiveMonthlyObligationByNIK_CountsDefaulted() {
+	ctx := context.Background()
+	s.Mock.ExpectBegin()
+	tx, err := s.DB.Begin()
 	s.Require().NoError(err)
+
+	// A defaulted contract is still owed, so it must be counted like the
+	// limit usage counts it.
+	s.Mock.ExpectQuery(`FROM transactions WHERE consumer_nik = \$1 AND status IN \([^)]*'DEFAULTED'[^)]*\)`).
+		WithArgs("1234567890").
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(500000))
+
+	obligation, err := s.Repo.GetActiveMonthlyObligationByNIK(ctx, tx, "1234567890")
+
+	s.Require().NoError(err)
+	s.Equal(money.MustParse("500000.00"), obligation)
+
+	s.Mock.ExpectCommit()
+	s.Require().NoError(tx.Commit())
 }
 
 func TestTransactionRepositoryTestSuite(t *testing.T) {
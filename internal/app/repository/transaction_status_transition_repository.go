@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+)
+
+type TransactionStatusTransitionRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, transition *model.TransactionStatusTransition) error
+	FindByNomorKontrak(ctx context.Context, nomorKontrak string) ([]model.TransactionStatusTransition, error)
+}
+
+type transactionStatusTransitionRepository struct {
+	db *sql.DB
+}
+
+func NewTransactionStatusTransitionRepository(db *sql.DB) TransactionStatusTransitionRepository {
+	return &transactionStatusTransitionRepository{db: db}
+}
+
+func (r *transactionStatusTransitionRepository) Create(ctx context.Context, tx *sql.Tx, transition *model.TransactionStatusTransition) error {
+	query := `
+		INSERT INTO transaction_status_transitions (nomor_kontrak, from_status, to_status, reason, changed_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	return tx.QueryRowContext(ctx, query,
+		transition.NomorKontrak,
+		transition.FromStatus,
+		transition.ToStatus,
+		transition.Reason,
+		transition.ChangedBy,
+	).Scan(&transition.ID, &transition.CreatedAt)
+}
+
+func (r *transactionStatusTransitionRepository) FindByNomorKontrak(ctx context.Context, nomorKontrak string) ([]model.TransactionStatusTransition, error) {
+	query := `SELECT id, nomor_kontrak, from_status, to_status, reason, changed_by, created_at
+  FROM transaction_status_transitions WHERE nomor_kontrak = $1 ORDER BY id ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, nomorKontrak)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transitions := []model.TransactionStatusTransition{}
+	for rows.Next() {
+		transition := model.TransactionStatusTransition{}
+		var fromStatus sql.NullString
+		if err := rows.Scan(&transition.ID, &transition.NomorKontrak, &fromStatus, &transition.ToStatus, &transition.Reason, &transition.ChangedBy, &transition.CreatedAt); err != nil {
+			return nil, err
+		}
+		if fromStatus.Valid {
+			transition.FromStatus = &fromStatus.String
+		}
+		transitions = append(transitions, transition)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return transitions, nil
+}
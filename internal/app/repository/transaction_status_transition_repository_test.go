@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/stretchr/testify/suite"
+)
+
+type transactionStatusTransitionRepositoryTestSuite struct {
+	suite.Suite
+	DB   *sql.DB
+	Mock sqlmock.Sqlmock
+	Repo TransactionStatusTransitionRepository
+}
+
+func (s *transactionStatusTransitionRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	s.Require().NoError(err)
+	s.DB = db
+	s.Mock = mock
+	s.Repo = NewTransactionStatusTransitionRepository(db)
+}
+
+func (s *transactionStatusTransitionRepositoryTestSuite) TearDownTest() {
+	s.DB.Close()
+}
+
+func (s *transactionStatusTransitionRepositoryTestSuite) TestCreate_Success() {
+	ctx := context.Background()
+	now := time.Now()
+	from := model.TransactionStatusPendingDisbursement
+
+	s.Mock.ExpectBegin()
+	tx, err := s.DB.Begin()
+	s.Require().NoError(err)
+
+	transition := &model.TransactionStatusTransition{
+		NomorKontrak: "TRX1",
+		FromStatus:   &from,
+		ToStatus:     model.TransactionStatusActive,
+		Reason:       "disbursed to merchant",
+		ChangedBy:    "admin",
+	}
+
+	s.Mock.ExpectQuery(`INSERT INTO transaction_status_transitions \(nomor_kontrak, from_status, to_status, reason, changed_by\) VALUES \(\$1, \$2, \$3, \$4, \$5\) RETURNING id, created_at`).
+		WithArgs("TRX1", &from, "ACTIVE", "disbursed to merchant", "admin").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(int64(3), now))
+
+	err = s.Repo.Create(ctx, tx, transition)
+
+	s.Require().NoError(err)
+	s.Equal(int64(3), transition.ID)
+	s.Equal(now, transition.CreatedAt)
+}
+
+func (s *transactionStatusTransitionRepositoryTestSuite) TestFindByNomorKontrak_Success() {
+	ctx := context.Background()
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{"id", "nomor_kontrak", "from_status", "to_status", "reason", "changed_by", "created_at"}).
+		AddRow(int64(1), "TRX1", nil, "PENDING_DISBURSEMENT", "contract opened", "1234567890123456", now).
+		AddRow(int64(3), "TRX1", "PENDING_DISBURSEMENT", "ACTIVE", "disbursed to merchant", "admin", now)
+
+	s.Mock.ExpectQuery(`SELECT id, nomor_kontrak, from_status, to_status, reason, changed_by, created_at FROM transaction_status_transitions WHERE nomor_kontrak = \$1 ORDER BY id ASC`).
+		WithArgs("TRX1").
+		WillReturnRows(rows)
+
+	transitions, err := s.Repo.FindByNomorKontrak(ctx, "TRX1")
+
+	s.Require().NoError(err)
+	s.Require().Len(transitions, 2)
+	s.Nil(transitions[0].FromStatus)
+	s.Require().NotNil(transitions[1].FromStatus)
+	s.Equal(model.TransactionStatusPendingDisbursement, *transitions[1].FromStatus)
+	s.Equal(model.TransactionStatusActive, transitions[1].ToStatus)
+}
+
+func TestTransactionStatusTransitionRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(transactionStatusTransitionRepositoryTestSuite))
+}
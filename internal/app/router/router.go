@@ -8,6 +8,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/glennprays/xyz-fin/internal/app/handler"
 	"github.com/glennprays/xyz-fin/internal/app/middleware"
+	"github.com/glennprays/xyz-fin/internal/app/model"
 )
 
 const BasePath = "/api/v1"
@@ -17,6 +18,18 @@ func SetupRouter(
 	consumerHandler *handler.ConsumerHandler,
 	transactionHandler *handler.TransactionHandler,
 	consumerLimitHandler *handler.ConsumerLimitHandler,
+	staffHandler *handler.StaffHandler,
+	limitChangeRequestHandler *handler.LimitChangeRequestHandler,
+	limitHoldHandler *handler.LimitHoldHandler,
+	limitImportHandler *handler.LimitImportHandler,
+	productHandler *handler.ProductHandler,
+	rateCardHandler *handler.RateCardHandler,
+	paymentHandler *handler.PaymentHandler,
+	contractStatusHandler *handler.ContractStatusHandler,
+	earlySettlementHandler *handler.EarlySettlementHandler,
+	penaltyHandler *handler.PenaltyHandler,
+	contractCancellationHandler *handler.ContractCancellationHandler,
+	merchantHandler *handler.MerchantHandler,
 ) *gin.Engine {
 	router := gin.Default()
 	router.Use(cors.Default())
@@ -30,12 +43,72 @@ func SetupRouter(
 	consumerGroup := apiV1.Group("/consumers")
 	{
 		consumerGroup.POST("/login", consumerHandler.Login)
+		consumerGroup.GET("/me/transactions", authMiddleware.Authenticate(), transactionHandler.ListMine)
 		consumerGroup.GET("/:nik", authMiddleware.Authenticate(), consumerHandler.GetByNIK)
 		consumerGroup.GET("/:nik/limits", authMiddleware.Authenticate(), consumerLimitHandler.GetLimitsByNIK)
 
 	}
 
+	apiV1.GET("/products", productHandler.List)
+	apiV1.GET("/products/:code", productHandler.GetByCode)
+
 	apiV1.POST("/transactions", authMiddleware.Authenticate(), transactionHandler.CreateTransaction)
+	apiV1.POST("/transactions/simulate", authMiddleware.Authenticate(), transactionHandler.Simulate)
+	apiV1.GET("/transactions/:nomor_kontrak", authMiddleware.Authenticate(), transactionHandler.GetDetail)
+	apiV1.GET("/transactions/:nomor_kontrak/schedule", authMiddleware.Authenticate(), transactionHandler.GetSchedule)
+	apiV1.GET("/transactions/:nomor_kontrak/early-settlement", authMiddleware.Authenticate(), earlySettlementHandler.GetConsumerQuote)
+	apiV1.POST("/transactions/:nomor_kontrak/cancel", authMiddleware.Authenticate(), contractCancellationHandler.Cancel)
+
+	limitHoldGroup := apiV1.Group("/limit-holds", authMiddleware.Authenticate())
+	{
+		limitHoldGroup.POST("", limitHoldHandler.Create)
+		limitHoldGroup.GET("/:id", limitHoldHandler.GetByID)
+		limitHoldGroup.POST("/:id/capture", transactionHandler.CaptureHold)
+		limitHoldGroup.POST("/:id/release", limitHoldHandler.Release)
+	}
+
+	apiV1.POST("/staff/login", staffHandler.Login)
+
+	merchantGroup := apiV1.Group("/merchant", authMiddleware.Authenticate(), authMiddleware.RequireRoles(model.StaffRoleMerchant))
+	{
+		merchantGroup.GET("/transactions", transactionHandler.ListForMerchant)
+	}
+
+	adminGroup := apiV1.Group("/admin", authMiddleware.Authenticate(), authMiddleware.RequireRoles(model.StaffRoleAdmin, model.StaffRoleCreditAnalyst))
+	{
+		adminGroup.GET("/consumers", consumerHandler.Search)
+		adminGroup.POST("/consumers/:nik/limits/calculate", consumerLimitHandler.CalculateLimits)
+		adminGroup.GET("/consumers/:nik/limits/:tenor/versions", consumerLimitHandler.GetLimitVersions)
+		adminGroup.GET("/consumers/:nik/limits/:tenor/as-of", consumerLimitHandler.GetLimitAsOf)
+		adminGroup.GET("/transactions/:nomor_kontrak/limit", consumerLimitHandler.GetLimitAtContract)
+		adminGroup.GET("/transactions/:nomor_kontrak/payments", paymentHandler.List)
+		adminGroup.POST("/transactions/:nomor_kontrak/payments", authMiddleware.RequireRoles(model.StaffRoleAdmin), paymentHandler.Post)
+		adminGroup.GET("/transactions/:nomor_kontrak/status-transitions", contractStatusHandler.List)
+		adminGroup.POST("/transactions/:nomor_kontrak/status-transitions", authMiddleware.RequireRoles(model.StaffRoleAdmin), contractStatusHandler.Transition)
+		adminGroup.POST("/transactions/:nomor_kontrak/cancel", authMiddleware.RequireRoles(model.StaffRoleAdmin), contractCancellationHandler.CancelByStaff)
+		adminGroup.GET("/transactions/:nomor_kontrak/early-settlement", earlySettlementHandler.GetQuote)
+		adminGroup.POST("/transactions/:nomor_kontrak/early-settlement", authMiddleware.RequireRoles(model.StaffRoleAdmin), earlySettlementHandler.Execute)
+		adminGroup.GET("/transactions/:nomor_kontrak/penalty-charges", penaltyHandler.List)
+
+		adminGroup.POST("/limit-change-requests", limitChangeRequestHandler.Create)
+		adminGroup.GET("/limit-change-requests", limitChangeRequestHandler.List)
+		adminGroup.GET("/limit-change-requests/:id", limitChangeRequestHandler.GetByID)
+		adminGroup.POST("/limit-change-requests/:id/approve", limitChangeRequestHandler.Approve)
+		adminGroup.POST("/limit-change-requests/:id/reject", limitChangeRequestHandler.Reject)
+
+		adminGroup.POST("/limit-imports", authMiddleware.RequireRoles(model.StaffRoleAdmin), limitImportHandler.Import)
+
+		adminGroup.GET("/rate-cards", rateCardHandler.List)
+		adminGroup.GET("/rate-cards/:id", rateCardHandler.GetByID)
+		adminGroup.POST("/rate-cards", authMiddleware.RequireRoles(model.StaffRoleAdmin), rateCardHandler.Create)
+
+		adminGroup.GET("/merchants", merchantHandler.List)
+		adminGroup.GET("/merchants/:id", merchantHandler.GetByID)
+		adminGroup.POST("/merchants", authMiddleware.RequireRoles(model.StaffRoleAdmin), merchantHandler.Create)
+		adminGroup.POST("/merchants/:id/status", authMiddleware.RequireRoles(model.StaffRoleAdmin), merchantHandler.UpdateStatus)
+		adminGroup.POST("/merchants/:id/outlets", authMiddleware.RequireRoles(model.StaffRoleAdmin), merchantHandler.CreateOutlet)
+		adminGroup.POST("/merchants/:id/outlets/:outlet_id/status", authMiddleware.RequireRoles(model.StaffRoleAdmin), merchantHandler.UpdateOutletStatus)
+	}
 
 	return router
 }
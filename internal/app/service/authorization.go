@@ -0,0 +1,114 @@
+package service
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+)
+
+// Actions a principal can be authorized for.
+const (
+	ActionViewConsumer       = "consumer:view"
+	ActionViewLimits         = "limits:view"
+	ActionHoldLimit          = "limits:hold"
+	ActionCalculateLimits    = "limits:calculate"
+	ActionApplyLimits        = "limits:apply"
+	ActionRequestLimitChange = "limits:request_change"
+	ActionDecideLimitChange  = "limits:decide_change"
+	ActionCreateContract     = "contract:create"
+	ActionViewContract       = "contract:view"
+	ActionCancelContract     = "contract:cancel"
+	ActionListContracts      = "contract:list"
+	ActionViewPenalties      = "contract:view_penalties"
+)
+
+var (
+	// ErrNotOwner is returned when a consumer acts on a resource of another
+	// consumer, or a merchant on a resource of another merchant. Callers
+	// report it as the resource not existing.
+	ErrNotOwner = errors.New("resource belongs to another owner")
+	// ErrPermissionDenied is returned when the principal may not perform the
+	// action at all.
+	ErrPermissionDenied = errors.New("principal is not allowed to perform this action")
+)
+
+// consumerActions are the actions a consumer may perform on their own
+// resources.
+var consumerActions = []string{
+	ActionViewConsumer,
+	ActionViewLimits,
+	ActionHoldLimit,
+	ActionCreateContract,
+	ActionViewContract,
+	ActionCancelContract,
+}
+
+// staffGrants lists the actions each staff role may perform on the resources
+// of any consumer. Staff never act on behalf of a consumer, so holding limit
+// and opening or cancelling contracts are left out. Only admins may write
+// engine limits directly; credit analysts go through limit change requests.
+var staffGrants = map[string][]string{
+	model.StaffRoleAdmin: {
+		ActionViewConsumer,
+		ActionViewLimits,
+		ActionCalculateLimits,
+		ActionApplyLimits,
+		ActionRequestLimitChange,
+		ActionDecideLimitChange,
+		ActionViewContract,
+		ActionViewPenalties,
+	},
+	model.StaffRoleCreditAnalyst: {
+		ActionViewConsumer,
+		ActionViewLimits,
+		ActionCalculateLimits,
+		ActionRequestLimitChange,
+		ActionDecideLimitChange,
+		ActionViewContract,
+		ActionViewPenalties,
+	},
+}
+
+// merchantActions are the actions the staff of a merchant may perform on
+// the contracts their merchant originated and the limit holds it placed.
+var merchantActions = []string{
+	ActionHoldLimit,
+	ActionViewContract,
+	ActionListContracts,
+}
+
+// Resource is what an action is performed on. OwnerNIK is the consumer the
+// resource belongs to and MerchantID the merchant that originated it, if any.
+type Resource struct {
+	OwnerNIK   string
+	MerchantID int64
+}
+
+// Authorize reports whether principal may perform action on resource.
+func Authorize(principal model.Principal, action string, resource Resource) error {
+	if principal.IsStaff() && principal.Role == model.StaffRoleMerchant {
+		if principal.MerchantID == 0 || !slices.Contains(merchantActions, action) {
+			return ErrPermissionDenied
+		}
+		if resource.MerchantID != principal.MerchantID {
+			return ErrNotOwner
+		}
+		return nil
+	}
+
+	if principal.IsStaff() {
+		if !slices.Contains(staffGrants[principal.Role], action) {
+			return ErrPermissionDenied
+		}
+		return nil
+	}
+
+	if principal.ConsumerNIK == "" || !slices.Contains(consumerActions, action) {
+		return ErrPermissionDenied
+	}
+	if resource.OwnerNIK != principal.ConsumerNIK {
+		return ErrNotOwner
+	}
+	return nil
+}
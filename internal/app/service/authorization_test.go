@@ -0,0 +1,190 @@
+package service
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/stretchr/testify/assert"
+)
+
+var allActions = []string{
+	ActionViewConsumer,
+	ActionViewLimits,
+	ActionHoldLimit,
+	ActionCalculateLimits,
+	ActionApplyLimits,
+	ActionRequestLimitChange,
+	ActionDecideLimitChange,
+	ActionCreateContract,
+	ActionViewContract,
+	ActionCancelContract,
+	ActionListContracts,
+	ActionViewPenalties,
+}
+
+func TestAuthorize(t *testing.T) {
+	owner := model.Principal{PhoneNumber: "08123456789", ConsumerNIK: "1234567890123456"}
+	otherConsumer := model.Principal{PhoneNumber: "08198765432", ConsumerNIK: "6543210987654321"}
+	unresolvedConsumer := model.Principal{PhoneNumber: "08123456789"}
+	admin := model.Principal{StaffUsername: "admin", Role: model.StaffRoleAdmin}
+	analyst := model.Principal{StaffUsername: "analyst", Role: model.StaffRoleCreditAnalyst}
+	unknownStaff := model.Principal{StaffUsername: "intern", Role: "intern"}
+	staffWithNIK := model.Principal{StaffUsername: "intern", Role: "intern", ConsumerNIK: "1234567890123456"}
+	merchant := model.Principal{StaffUsername: "cashier", Role: model.StaffRoleMerchant, MerchantID: 3}
+	otherMerchant := model.Principal{StaffUsername: "cashier2", Role: model.StaffRoleMerchant, MerchantID: 4}
+	unlinkedMerchant := model.Principal{StaffUsername: "cashier3", Role: model.StaffRoleMerchant}
+	anonymous := model.Principal{}
+
+	resource := Resource{OwnerNIK: "1234567890123456", MerchantID: 3}
+
+	consumerActs := func(result error) func(action string) error {
+		return func(action string) error {
+			switch action {
+			case ActionViewConsumer, ActionViewLimits, ActionHoldLimit, ActionCreateContract, ActionViewContract, ActionCancelContract:
+				return result
+			}
+			return ErrPermissionDenied
+		}
+	}
+	merchantActs := func(result error) func(action string) error {
+		return func(action string) error {
+			if action == ActionHoldLimit || action == ActionViewContract || action == ActionListContracts {
+				return result
+			}
+			return ErrPermissionDenied
+		}
+	}
+
+	adminGrants := map[string]error{
+		ActionViewConsumer:       nil,
+		ActionViewLimits:         nil,
+		ActionHoldLimit:          ErrPermissionDenied,
+		ActionCalculateLimits:    nil,
+		ActionApplyLimits:        nil,
+		ActionRequestLimitChange: nil,
+		ActionDecideLimitChange:  nil,
+		ActionCreateContract:     ErrPermissionDenied,
+		ActionViewContract:       nil,
+		ActionCancelContract:     ErrPermissionDenied,
+		ActionListContracts:      ErrPermissionDenied,
+		ActionViewPenalties:      nil,
+	}
+	analystGrants := map[string]error{
+		ActionViewConsumer:       nil,
+		ActionViewLimits:         nil,
+		ActionHoldLimit:          ErrPermissionDenied,
+		ActionCalculateLimits:    nil,
+		ActionApplyLimits:        ErrPermissionDenied,
+		ActionRequestLimitChange: nil,
+		ActionDecideLimitChange:  nil,
+		ActionCreateContract:     ErrPermissionDenied,
+		ActionViewContract:       nil,
+		ActionCancelContract:     ErrPermissionDenied,
+		ActionListContracts:      ErrPermissionDenied,
+		ActionViewPenalties:      nil,
+	}
+
+	tests := []struct {
+		name      string
+		principal model.Principal
+		expected  func(action string) error
+	}{
+		{
+			name:      "owner",
+			principal: owner,
+			expected:  consumerActs(nil),
+		},
+		{
+			name:      "another consumer",
+			principal: otherConsumer,
+			expected:  consumerActs(ErrNotOwner),
+		},
+		{
+			name:      "consumer not yet resolved",
+			principal: unresolvedConsumer,
+			expected:  func(string) error { return ErrPermissionDenied },
+		},
+		{
+			name:      "admin",
+			principal: admin,
+			expected:  func(action string) error { return adminGrants[action] },
+		},
+		{
+			name:      "credit analyst",
+			principal: analyst,
+			expected:  func(action string) error { return analystGrants[action] },
+		},
+		{
+			name:      "staff role without grants",
+			principal: unknownStaff,
+			expected:  func(string) error { return ErrPermissionDenied },
+		},
+		{
+			name:      "staff is never treated as the owner",
+			principal: staffWithNIK,
+			expected:  func(string) error { return ErrPermissionDenied },
+		},
+		{
+			name:      "merchant that originated the contract",
+			principal: merchant,
+			expected:  merchantActs(nil),
+		},
+		{
+			name:      "another merchant",
+			principal: otherMerchant,
+			expected:  merchantActs(ErrNotOwner),
+		},
+		{
+			name:      "merchant staff without a merchant",
+			principal: unlinkedMerchant,
+			expected:  func(string) error { return ErrPermissionDenied },
+		},
+		{
+			name:      "anonymous",
+			principal: anonymous,
+			expected:  func(string) error { return ErrPermissionDenied },
+		},
+	}
+
+	for _, tt := range tests {
+		for _, action := range allActions {
+			t.Run(tt.name+"/"+action, func(t *testing.T) {
+				assert.Equal(t, tt.expected(action), Authorize(tt.principal, action, resource))
+			})
+		}
+	}
+}
+
+func TestAuthorize_UnknownAction(t *testing.T) {
+	owner := model.Principal{PhoneNumber: "08123456789", ConsumerNIK: "1234567890123456"}
+	admin := model.Principal{StaffUsername: "admin", Role: model.StaffRoleAdmin}
+
+	assert.Equal(t, ErrPermissionDenied, Authorize(owner, "contract:delete", Resource{OwnerNIK: owner.ConsumerNIK}))
+	assert.Equal(t, ErrPermissionDenied, Authorize(admin, "contract:delete", Resource{OwnerNIK: owner.ConsumerNIK}))
+}
+
+func TestAuthorize_ResourceWithoutOwner(t *testing.T) {
+	owner := model.Principal{PhoneNumber: "08123456789", ConsumerNIK: "1234567890123456"}
+
+	for _, action := range consumerActions {
+		assert.Equal(t, ErrNotOwner, Authorize(owner, action, Resource{}), action)
+	}
+}
+
+func TestAuthorize_GrantsCoverEveryAction(t *testing.T) {
+	admin := model.Principal{StaffUsername: "admin", Role: model.StaffRoleAdmin}
+
+	// Every action is granted to someone; an action nobody holds would make
+	// its endpoint unreachable.
+	for _, action := range allActions {
+		granted := slices.Contains(consumerActions, action) || slices.Contains(merchantActions, action) || Authorize(admin, action, Resource{}) == nil
+		assert.True(t, granted, action)
+	}
+}
+
+func TestAuthorize_ContractWithoutMerchant(t *testing.T) {
+	merchant := model.Principal{StaffUsername: "cashier", Role: model.StaffRoleMerchant, MerchantID: 3}
+
+	assert.Equal(t, ErrNotOwner, Authorize(merchant, ActionViewContract, Resource{OwnerNIK: "1234567890123456"}))
+}
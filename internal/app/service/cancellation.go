@@ -0,0 +1,31 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+)
+
+// CheckCancellable reports whether the consumer may still cancel a contract
+// at asOf: only before it is disbursed and within coolingOff of opening it.
+func CheckCancellable(transaction model.Transaction, asOf time.Time, coolingOff time.Duration) error {
+	if err := CheckNotDisbursed(transaction); err != nil {
+		return err
+	}
+
+	deadline := transaction.CreatedAt.Add(coolingOff)
+	if asOf.After(deadline) {
+		return fmt.Errorf("the cooling-off period ended at %s", deadline.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// CheckNotDisbursed reports whether a contract may still be cancelled by
+// staff, who are not bound by the cooling-off period.
+func CheckNotDisbursed(transaction model.Transaction) error {
+	if transaction.Status != model.TransactionStatusPendingDisbursement {
+		return fmt.Errorf("a %s contract cannot be cancelled", transaction.Status)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckCancellable(t *testing.T) {
+	createdAt := time.Date(2025, 4, 10, 9, 0, 0, 0, time.UTC)
+	coolingOff := 48 * time.Hour
+
+	tests := []struct {
+		name     string
+		status   string
+		asOf     time.Time
+		expected string
+	}{
+		{
+			name:   "right after opening",
+			status: model.TransactionStatusPendingDisbursement,
+			asOf:   createdAt.Add(time.Minute),
+		},
+		{
+			name:   "at the end of the cooling-off period",
+			status: model.TransactionStatusPendingDisbursement,
+			asOf:   createdAt.Add(coolingOff),
+		},
+		{
+			name:     "after the cooling-off period",
+			status:   model.TransactionStatusPendingDisbursement,
+			asOf:     createdAt.Add(coolingOff + time.Second),
+			expected: "the cooling-off period ended at 2025-04-12T09:00:00Z",
+		},
+		{
+			name:     "already disbursed",
+			status:   model.TransactionStatusActive,
+			asOf:     createdAt.Add(time.Hour),
+			expected: "a ACTIVE contract cannot be cancelled",
+		},
+		{
+			name:     "already cancelled",
+			status:   model.TransactionStatusCancelled,
+			asOf:     createdAt.Add(time.Hour),
+			expected: "a CANCELLED contract cannot be cancelled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transaction := model.Transaction{Status: tt.status, CreatedAt: createdAt}
+
+			err := CheckCancellable(transaction, tt.asOf, coolingOff)
+
+			if tt.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCheckNotDisbursed(t *testing.T) {
+	createdAt := time.Date(2025, 1, 10, 9, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, CheckNotDisbursed(model.Transaction{Status: model.TransactionStatusPendingDisbursement, CreatedAt: createdAt}))
+	assert.EqualError(t, CheckNotDisbursed(model.Transaction{Status: model.TransactionStatusActive, CreatedAt: createdAt}), "a ACTIVE contract cannot be cancelled")
+}
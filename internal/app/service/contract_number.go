@@ -0,0 +1,62 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/glennprays/xyz-fin/pkg/checkdigit"
+)
+
+var (
+	branchCodePattern     = regexp.MustCompile(`^[0-9A-Z]{3}$`)
+	contractNumberPattern = regexp.MustCompile(`^([A-Z]{2,4})-([0-9A-Z]{3})-([0-9]{6})-([0-9]{8,})-([0-9A-Z])$`)
+	// Contracts opened before structured numbering keep their random
+	// TRX numbers.
+	legacyContractNumberPattern = regexp.MustCompile(`^TRX[0-9A-Z]{9}$`)
+)
+
+var ErrInvalidContractNumber = errors.New("invalid nomor_kontrak")
+
+// ContractNumberGenerator builds contract numbers of the form
+// PREFIX-BRANCH-YYMMDD-SEQUENCE-C, for example WG-001-250410-00000012-Z: the
+// product's contract prefix, the issuing branch, the opening date, a number
+// drawn from a database sequence and a check character over the rest. The
+// sequence alone keeps numbers unique; the other parts make them readable.
+type ContractNumberGenerator struct {
+	branchCode string
+}
+
+func NewContractNumberGenerator(branchCode string) (*ContractNumberGenerator, error) {
+	if !branchCodePattern.MatchString(branchCode) {
+		return nil, fmt.Errorf("branch code %q must be 3 digits or upper-case letters", branchCode)
+	}
+	return &ContractNumberGenerator{branchCode: branchCode}, nil
+}
+
+func (g *ContractNumberGenerator) Generate(productPrefix string, date time.Time, sequence int64) (string, error) {
+	parts := []string{productPrefix, g.branchCode, date.Format("060102"), fmt.Sprintf("%08d", sequence)}
+	check, err := checkdigit.Compute(strings.Join(parts, ""))
+	if err != nil {
+		return "", fmt.Errorf("contract prefix %q: %w", productPrefix, err)
+	}
+	return strings.Join(append(parts, string(check)), "-"), nil
+}
+
+// ValidateContractNumber rejects numbers that cannot have been issued: a
+// wrong layout or a check character that does not match. Legacy TRX numbers
+// carry no check character and are only checked for their layout.
+func ValidateContractNumber(nomorKontrak string) error {
+	if legacyContractNumberPattern.MatchString(nomorKontrak) {
+		return nil
+	}
+	if !contractNumberPattern.MatchString(nomorKontrak) {
+		return ErrInvalidContractNumber
+	}
+	if !checkdigit.Valid(strings.ReplaceAll(nomorKontrak, "-", "")) {
+		return ErrInvalidContractNumber
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewContractNumberGenerator(t *testing.T) {
+	for _, branchCode := range []string{"", "01", "0001", "jkt"} {
+		_, err := NewContractNumberGenerator(branchCode)
+		assert.Error(t, err, branchCode)
+	}
+}
+
+func TestContractNumberGenerator_Generate(t *testing.T) {
+	generator, err := NewContractNumberGenerator("001")
+	require.NoError(t, err)
+
+	nomorKontrak, err := generator.Generate("WG", time.Date(2025, 4, 10, 23, 59, 0, 0, time.UTC), 12)
+
+	require.NoError(t, err)
+	assert.Equal(t, "WG-001-250410-00000012-Z", nomorKontrak)
+	assert.NoError(t, ValidateContractNumber(nomorKontrak))
+
+	nomorKontrak, err = generator.Generate("CL", time.Date(2025, 4, 10, 0, 0, 0, 0, time.UTC), 123456789)
+
+	require.NoError(t, err)
+	assert.Regexp(t, `^CL-001-250410-123456789-[0-9A-Z]$`, nomorKontrak)
+	assert.NoError(t, ValidateContractNumber(nomorKontrak))
+
+	_, err = generator.Generate("wg", time.Now(), 1)
+	assert.Error(t, err)
+}
+
+func TestValidateContractNumber(t *testing.T) {
+	tests := []struct {
+		name         string
+		nomorKontrak string
+		valid        bool
+	}{
+		{name: "structured", nomorKontrak: "WG-001-250410-00000012-Z", valid: true},
+		{name: "legacy", nomorKontrak: "TRXA1B2C3D4E", valid: true},
+		{name: "wrong check character", nomorKontrak: "WG-001-250410-00000012-Y"},
+		{name: "mistyped sequence", nomorKontrak: "WG-001-250410-00000013-Z"},
+		{name: "transposed digits", nomorKontrak: "WG-001-250401-00000012-Z"},
+		{name: "other product", nomorKontrak: "MC-001-250410-00000012-Z"},
+		{name: "missing separators", nomorKontrak: "WG00125041000000012Z"},
+		{name: "lower case", nomorKontrak: "wg-001-250410-00000012-z"},
+		{name: "short legacy", nomorKontrak: "TRX123"},
+		{name: "empty", nomorKontrak: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateContractNumber(tt.nomorKontrak)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidContractNumber)
+			}
+		})
+	}
+}
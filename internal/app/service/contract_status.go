@@ -0,0 +1,58 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+)
+
+// contractTransitions lists the statuses a contract may move to from each
+// status. PAID_OFF, CANCELLED, WRITTEN_OFF and RESTRUCTURED are final; a
+// restructured contract is replaced by a new one. Staff cannot move a
+// contract to WRITTEN_OFF or RESTRUCTURED yet, as there is no flow that closes
+// its schedule for either. Every other status counts
+// against the consumer's limit, so curing a DEFAULTED contract back to ACTIVE
+// needs no new limit check.
+var contractTransitions = map[string][]string{
+	model.TransactionStatusPendingDisbursement: {
+		model.TransactionStatusActive,
+		model.TransactionStatusCancelled,
+	},
+	model.TransactionStatusActive: {
+		model.TransactionStatusPaidOff,
+		model.TransactionStatusDefaulted,
+		model.TransactionStatusRestructured,
+	},
+	model.TransactionStatusDefaulted: {
+		model.TransactionStatusActive,
+		model.TransactionStatusPaidOff,
+		model.TransactionStatusWrittenOff,
+		model.TransactionStatusRestructured,
+	},
+	model.TransactionStatusPaidOff:      {},
+	model.TransactionStatusCancelled:    {},
+	model.TransactionStatusWrittenOff:   {},
+	model.TransactionStatusRestructured: {},
+}
+
+func IsContractStatus(status string) bool {
+	_, ok := contractTransitions[status]
+	return ok
+}
+
+// ValidateContractTransition reports whether a contract in status from may
+// move to status to.
+func ValidateContractTransition(from, to string) error {
+	allowed, ok := contractTransitions[from]
+	if !ok {
+		return fmt.Errorf("unknown contract status %q", from)
+	}
+	if !IsContractStatus(to) {
+		return fmt.Errorf("unknown contract status %q", to)
+	}
+	if !slices.Contains(allowed, to) {
+		return fmt.Errorf("a %s contract cannot move to %s", from, to)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateContractTransition(t *testing.T) {
+	allowed := map[[2]string]bool{
+		{model.TransactionStatusPendingDisbursement, model.TransactionStatusActive}:    true,
+		{model.TransactionStatusPendingDisbursement, model.TransactionStatusCancelled}: true,
+		{model.TransactionStatusActive, model.TransactionStatusPaidOff}:                true,
+		{model.TransactionStatusActive, model.TransactionStatusDefaulted}:              true,
+		{model.TransactionStatusActive, model.TransactionStatusRestructured}:           true,
+		{model.TransactionStatusDefaulted, model.TransactionStatusActive}:              true,
+		{model.TransactionStatusDefaulted, model.TransactionStatusPaidOff}:             true,
+		{model.TransactionStatusDefaulted, model.TransactionStatusWrittenOff}:          true,
+		{model.TransactionStatusDefaulted, model.TransactionStatusRestructured}:        true,
+	}
+	statuses := []string{
+		model.TransactionStatusPendingDisbursement,
+		model.TransactionStatusActive,
+		model.TransactionStatusPaidOff,
+		model.TransactionStatusCancelled,
+		model.TransactionStatusDefaulted,
+		model.TransactionStatusWrittenOff,
+		model.TransactionStatusRestructured,
+	}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			t.Run(from+" to "+to, func(t *testing.T) {
+				err := ValidateContractTransition(from, to)
+				if allowed[[2]string{from, to}] {
+					assert.NoError(t, err)
+					return
+				}
+				assert.EqualError(t, err, "a "+from+" contract cannot move to "+to)
+			})
+		}
+	}
+}
+
+func TestValidateContractTransition_UnknownStatus(t *testing.T) {
+	assert.EqualError(t, ValidateContractTransition("PENDING", model.TransactionStatusActive), `unknown contract status "PENDING"`)
+	assert.EqualError(t, ValidateContractTransition(model.TransactionStatusActive, "CLOSED"), `unknown contract status "CLOSED"`)
+}
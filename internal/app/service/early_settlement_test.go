@@ -0,0 +1,157 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// settlementContract is a 3 month flat contract of 1,000,000 at 2.5% with a
+// 30,000 admin fee opened on 10 January 2025.
+func settlementContract() (model.Transaction, []model.Installment) {
+	transaction := model.Transaction{
+		NomorKontrak: "TRX1",
+		OTR:          money.FromRupiah(1000000),
+		JumlahBunga:  money.FromRupiah(75000),
+		CreatedAt:    time.Date(2025, 1, 10, 9, 30, 0, 0, time.UTC),
+	}
+	installments := []model.Installment{
+		unpaidInstallment(1, 333333, 25000, 10000, 0),
+		unpaidInstallment(2, 333333, 25000, 10000, 0),
+		unpaidInstallment(3, 333334, 25000, 10000, 0),
+	}
+	for i := range installments {
+		installments[i].DueDate = time.Date(2025, time.Month(i+2), 10, 0, 0, 0, 0, time.UTC)
+	}
+	return transaction, installments
+}
+
+func paidInFull(installment *model.Installment) {
+	installment.PaidPrincipal = installment.Principal
+	installment.PaidInterest = installment.Interest
+	installment.PaidAdminFee = installment.AdminFee
+	installment.PaidPenalty = installment.Penalty
+	installment.Status = model.InstallmentStatusPaid
+}
+
+func TestNewEarlySettlementCalculator(t *testing.T) {
+	_, err := NewEarlySettlementCalculator(EarlySettlementRules{RebateRule: "actuarial"})
+	assert.EqualError(t, err, `unknown interest rebate rule "actuarial"`)
+
+	_, err = NewEarlySettlementCalculator(EarlySettlementRules{RebateRule: RebateRuleFull, MinTerminationFee: money.FromRupiah(-1)})
+	assert.EqualError(t, err, "early termination fee must not be negative")
+}
+
+func TestEarlySettlementCalculator_Quote(t *testing.T) {
+	tests := []struct {
+		name     string
+		rules    EarlySettlementRules
+		asOf     time.Time
+		prepare  func(installments []model.Installment)
+		expected model.EarlySettlementQuote
+	}{
+		{
+			name:  "mid period with full rebate",
+			rules: EarlySettlementRules{TerminationFeeRate: money.MustParseRate("0.02"), MinTerminationFee: money.FromRupiah(10000), RebateRule: RebateRuleFull},
+			asOf:  time.Date(2025, 2, 24, 15, 0, 0, 0, time.UTC),
+			prepare: func(installments []model.Installment) {
+				paidInFull(&installments[0])
+			},
+			expected: model.EarlySettlementQuote{
+				RemainingPrincipal:  money.FromRupiah(666667),
+				OutstandingAdminFee: money.FromRupiah(20000),
+				AccruedInterest:     money.FromRupiah(12500),
+				UnearnedInterest:    money.FromRupiah(37500),
+				InterestRebate:      money.FromRupiah(37500),
+				EarlyTerminationFee: money.FromRupiah(13333),
+				TotalPayoff:         money.FromRupiah(712500),
+			},
+		},
+		{
+			name:  "mid period with rule of 78",
+			rules: EarlySettlementRules{TerminationFeeRate: money.MustParseRate("0.02"), MinTerminationFee: money.FromRupiah(10000), RebateRule: RebateRuleOf78},
+			asOf:  time.Date(2025, 2, 24, 15, 0, 0, 0, time.UTC),
+			prepare: func(installments []model.Installment) {
+				paidInFull(&installments[0])
+			},
+			expected: model.EarlySettlementQuote{
+				RemainingPrincipal:  money.FromRupiah(666667),
+				OutstandingAdminFee: money.FromRupiah(20000),
+				AccruedInterest:     money.FromRupiah(12500),
+				UnearnedInterest:    money.FromRupiah(37500),
+				InterestRebate:      money.FromRupiah(12500),
+				EarlyTerminationFee: money.FromRupiah(13333),
+				TotalPayoff:         money.FromRupiah(737500),
+			},
+		},
+		{
+			name:  "mid period without rebate",
+			rules: EarlySettlementRules{RebateRule: RebateRuleNone},
+			asOf:  time.Date(2025, 2, 24, 15, 0, 0, 0, time.UTC),
+			prepare: func(installments []model.Installment) {
+				paidInFull(&installments[0])
+			},
+			expected: model.EarlySettlementQuote{
+				RemainingPrincipal:  money.FromRupiah(666667),
+				OutstandingAdminFee: money.FromRupiah(20000),
+				AccruedInterest:     money.FromRupiah(12500),
+				UnearnedInterest:    money.FromRupiah(37500),
+				TotalPayoff:         money.FromRupiah(736667),
+			},
+		},
+		{
+			name:  "overdue installments and penalty with minimum fee",
+			rules: EarlySettlementRules{TerminationFeeRate: money.MustParseRate("0.02"), MinTerminationFee: money.FromRupiah(50000), RebateRule: RebateRuleFull},
+			asOf:  time.Date(2025, 3, 15, 8, 0, 0, 0, time.UTC),
+			prepare: func(installments []model.Installment) {
+				installments[0].Penalty = money.FromRupiah(7000)
+			},
+			expected: model.EarlySettlementQuote{
+				RemainingPrincipal:  money.FromRupiah(1000000),
+				OutstandingPenalty:  money.FromRupiah(7000),
+				OutstandingAdminFee: money.FromRupiah(30000),
+				AccruedInterest:     money.FromRupiah(54032),
+				UnearnedInterest:    money.FromRupiah(20968),
+				InterestRebate:      money.FromRupiah(20968),
+				EarlyTerminationFee: money.FromRupiah(50000),
+				TotalPayoff:         money.FromRupiah(1141032),
+			},
+		},
+		{
+			name:  "interest paid ahead of accrual is not refunded",
+			rules: EarlySettlementRules{RebateRule: RebateRuleFull},
+			asOf:  time.Date(2025, 1, 12, 8, 0, 0, 0, time.UTC),
+			prepare: func(installments []model.Installment) {
+				installments[0].PaidInterest = money.FromRupiah(5000)
+				installments[0].Status = model.InstallmentStatusPartial
+			},
+			expected: model.EarlySettlementQuote{
+				RemainingPrincipal:  money.FromRupiah(1000000),
+				OutstandingAdminFee: money.FromRupiah(30000),
+				UnearnedInterest:    money.FromRupiah(70000),
+				InterestRebate:      money.FromRupiah(70000),
+				TotalPayoff:         money.FromRupiah(1030000),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calculator, err := NewEarlySettlementCalculator(tt.rules)
+			require.NoError(t, err)
+
+			transaction, installments := settlementContract()
+			tt.prepare(installments)
+
+			quote := calculator.Quote(transaction, installments, tt.asOf)
+
+			tt.expected.NomorKontrak = "TRX1"
+			tt.expected.AsOf = tt.asOf
+			assert.Equal(t, tt.expected, quote)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"time"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/pkg/loancalc"
+)
+
+// BuildInstallmentSchedule turns a priced contract into monthly
+// installments. Principal and interest follow the pricing plan; the admin fee
+// is split evenly in whole rupiah with the remainder on the last installment.
+func BuildInstallmentSchedule(pricing model.Pricing, start time.Time) []model.Installment {
+	tenor := len(pricing.Plan.Periods)
+	adminFeeParts := loancalc.Split(pricing.AdminFee, tenor)
+
+	installments := make([]model.Installment, 0, tenor)
+	for i, period := range pricing.Plan.Periods {
+		installments = append(installments, model.Installment{
+			InstallmentNumber: period.Number,
+			DueDate:           dueDate(start, period.Number),
+			Principal:         period.Principal,
+			Interest:          period.Interest,
+			AdminFee:          adminFeeParts[i],
+			TotalAmount:       period.Payment + adminFeeParts[i],
+			Status:            model.InstallmentStatusUnpaid,
+		})
+	}
+	return installments
+}
+
+// dueDate returns the date months after start on the same day of the month,
+// moved back to the last day when the month is shorter.
+func dueDate(start time.Time, months int) time.Time {
+	year, month, day := start.Date()
+	firstOfMonth := time.Date(year, month+time.Month(months), 1, 0, 0, 0, 0, start.Location())
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(firstOfMonth.Year(), firstOfMonth.Month(), day, 0, 0, 0, 0, start.Location())
+}
+
+// SummarizeSchedule totals a contract's installments as of asOf. Installments
+// closed by an early settlement count as paid and cancelled ones are owed by
+// no one; an open installment is overdue from the day after its due date.
+func SummarizeSchedule(installments []model.Installment, asOf time.Time) model.ScheduleSummary {
+	summary := model.ScheduleSummary{InstallmentCount: len(installments)}
+	today := dateOf(asOf)
+
+	for _, installment := range installments {
+		summary.TotalAmount += installment.TotalAmount
+		summary.TotalPenalty += installment.Penalty
+		summary.TotalPaid += installment.PaidPrincipal + installment.PaidInterest + installment.PaidAdminFee + installment.PaidPenalty
+
+		switch installment.Status {
+		case model.InstallmentStatusPaid, model.InstallmentStatusSettled:
+			summary.PaidCount++
+			continue
+		case model.InstallmentStatusCancelled:
+			continue
+		}
+
+		outstanding := InstallmentOutstanding(installment)
+		summary.OutstandingAmount += outstanding
+		if dateOf(installment.DueDate).Before(today) {
+			summary.OverdueCount++
+			summary.OverdueAmount += outstanding
+		}
+		if summary.NextDueDate == nil {
+			next := installment.DueDate
+			summary.NextDueDate = &next
+			summary.NextDueAmount = outstanding
+		}
+	}
+	return summary
+}
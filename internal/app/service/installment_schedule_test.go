@@ -0,0 +1,213 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/pkg/loancalc"
+	"github.com/glennprays/xyz-fin/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pricingFor(t *testing.T, method string, principal money.Amount, rate string, adminFee money.Amount, tenor int) model.Pricing {
+	t.Helper()
+	calculator, err := loancalc.New(method)
+	require.NoError(t, err)
+	return model.Pricing{
+		AdminFee: adminFee,
+		Plan:     calculator.Plan(principal, money.MustParseRate(rate), tenor),
+	}
+}
+
+func TestBuildInstallmentSchedule(t *testing.T) {
+	tests := []struct {
+		name          string
+		method        string
+		principal     money.Amount
+		rate          string
+		adminFee      money.Amount
+		tenor         int
+		expectedFirst money.Amount
+		expectedLast  money.Amount
+	}{
+		{
+			name:          "evenly divisible",
+			method:        loancalc.MethodFlat,
+			principal:     money.FromRupiah(1200000),
+			rate:          "0.01",
+			adminFee:      money.FromRupiah(36000),
+			tenor:         6,
+			expectedFirst: money.FromRupiah(218000),
+			expectedLast:  money.FromRupiah(218000),
+		},
+		{
+			name:          "remainder goes to the last installment",
+			method:        loancalc.MethodFlat,
+			principal:     money.FromRupiah(1000000),
+			rate:          "0.025",
+			adminFee:      money.FromRupiah(30000),
+			tenor:         3,
+			expectedFirst: money.FromRupiah(368333),
+			expectedLast:  money.FromRupiah(368334),
+		},
+		{
+			name:          "sen stay on the last installment",
+			method:        loancalc.MethodFlat,
+			principal:     money.MustParse("1000000.50"),
+			rate:          "0",
+			tenor:         2,
+			expectedFirst: money.FromRupiah(500000),
+			expectedLast:  money.MustParse("500000.50"),
+		},
+		{
+			name:          "annuity plan with admin fee on top",
+			method:        loancalc.MethodAnnuity,
+			principal:     money.FromRupiah(1000000),
+			rate:          "0.02",
+			adminFee:      money.FromRupiah(60000),
+			tenor:         6,
+			expectedFirst: money.FromRupiah(188526),
+			expectedLast:  money.FromRupiah(188524),
+		},
+	}
+
+	start := time.Date(2025, 3, 10, 14, 30, 0, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pricing := pricingFor(t, tt.method, tt.principal, tt.rate, tt.adminFee, tt.tenor)
+
+			installments := BuildInstallmentSchedule(pricing, start)
+			require.Len(t, installments, tt.tenor)
+
+			assert.Equal(t, tt.expectedFirst, installments[0].TotalAmount)
+			assert.Equal(t, tt.expectedLast, installments[tt.tenor-1].TotalAmount)
+
+			var principal, interest, adminFee money.Amount
+			for i, installment := range installments {
+				assert.Equal(t, i+1, installment.InstallmentNumber)
+				assert.Equal(t, installment.Principal+installment.Interest+installment.AdminFee, installment.TotalAmount)
+				principal += installment.Principal
+				interest += installment.Interest
+				adminFee += installment.AdminFee
+			}
+			assert.Equal(t, tt.principal, principal)
+			assert.Equal(t, pricing.Plan.TotalInterest, interest)
+			assert.Equal(t, tt.adminFee, adminFee)
+		})
+	}
+}
+
+func TestBuildInstallmentSchedule_DueDates(t *testing.T) {
+	tests := []struct {
+		name     string
+		start    time.Time
+		tenor    int
+		expected []string
+	}{
+		{
+			name:     "same day every month",
+			start:    time.Date(2025, 3, 10, 14, 30, 0, 0, time.UTC),
+			tenor:    3,
+			expected: []string{"2025-04-10", "2025-05-10", "2025-06-10"},
+		},
+		{
+			name:     "month end is clamped",
+			start:    time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC),
+			tenor:    3,
+			expected: []string{"2025-02-28", "2025-03-31", "2025-04-30"},
+		},
+		{
+			name:     "leap year and year change",
+			start:    time.Date(2023, 11, 30, 9, 0, 0, 0, time.UTC),
+			tenor:    3,
+			expected: []string{"2023-12-30", "2024-01-30", "2024-02-29"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pricing := pricingFor(t, loancalc.MethodFlat, money.FromRupiah(300000), "0", 0, tt.tenor)
+			installments := BuildInstallmentSchedule(pricing, tt.start)
+
+			var dates []string
+			for _, installment := range installments {
+				dates = append(dates, installment.DueDate.Format("2006-01-02"))
+			}
+			assert.Equal(t, tt.expected, dates)
+		})
+	}
+}
+
+func TestSummarizeSchedule(t *testing.T) {
+	installments := []model.Installment{
+		unpaidInstallment(1, 333333, 25000, 10000, 0),
+		unpaidInstallment(2, 333333, 25000, 10000, 1500),
+		unpaidInstallment(3, 333334, 25000, 10000, 0),
+	}
+	for i := range installments {
+		installments[i].DueDate = time.Date(2025, time.Month(i+2), 10, 0, 0, 0, 0, time.UTC)
+	}
+	paidInFull(&installments[0])
+	installments[1].PaidInterest = money.FromRupiah(25000)
+	installments[1].Status = model.InstallmentStatusPartial
+
+	t.Run("one installment overdue", func(t *testing.T) {
+		summary := SummarizeSchedule(installments, time.Date(2025, 3, 11, 8, 0, 0, 0, time.UTC))
+
+		nextDueDate := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+		assert.Equal(t, model.ScheduleSummary{
+			InstallmentCount:  3,
+			PaidCount:         1,
+			OverdueCount:      1,
+			TotalAmount:       money.FromRupiah(1105000),
+			TotalPenalty:      money.FromRupiah(1500),
+			TotalPaid:         money.FromRupiah(393333),
+			OutstandingAmount: money.FromRupiah(713167),
+			OverdueAmount:     money.FromRupiah(344833),
+			NextDueDate:       &nextDueDate,
+			NextDueAmount:     money.FromRupiah(344833),
+		}, summary)
+	})
+
+	t.Run("due today is not overdue", func(t *testing.T) {
+		summary := SummarizeSchedule(installments, time.Date(2025, 3, 10, 23, 0, 0, 0, time.UTC))
+
+		assert.Zero(t, summary.OverdueCount)
+		assert.Zero(t, summary.OverdueAmount)
+	})
+
+	t.Run("settled schedule", func(t *testing.T) {
+		settled := make([]model.Installment, len(installments))
+		copy(settled, installments)
+		for i := 1; i < len(settled); i++ {
+			settled[i].Status = model.InstallmentStatusSettled
+		}
+
+		summary := SummarizeSchedule(settled, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+
+		assert.Equal(t, 3, summary.PaidCount)
+		assert.Zero(t, summary.OutstandingAmount)
+		assert.Nil(t, summary.NextDueDate)
+	})
+
+	t.Run("cancelled schedule", func(t *testing.T) {
+		cancelled := []model.Installment{
+			unpaidInstallment(1, 333333, 25000, 10000, 0),
+			unpaidInstallment(2, 333333, 25000, 10000, 0),
+		}
+		for i := range cancelled {
+			cancelled[i].DueDate = time.Date(2025, time.Month(i+2), 10, 0, 0, 0, 0, time.UTC)
+			cancelled[i].Status = model.InstallmentStatusCancelled
+		}
+
+		summary := SummarizeSchedule(cancelled, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+
+		assert.Equal(t, 2, summary.InstallmentCount)
+		assert.Zero(t, summary.PaidCount)
+		assert.Zero(t, summary.OverdueCount)
+		assert.Zero(t, summary.OutstandingAmount)
+		assert.Nil(t, summary.NextDueDate)
+	})
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/pkg/money"
+)
+
+const (
+	LimitFactorIncome              = "income"
+	LimitFactorAge                 = "age"
+	LimitFactorAgeAtMaturity       = "age_at_maturity"
+	LimitFactorObligations         = "existing_obligations"
+	LimitFactorInstallmentCapacity = "installment_capacity"
+	LimitFactorIncomeCap           = "income_cap"
+	LimitFactorRounding            = "rounding"
+)
+
+// LimitRules are the credit policy parameters used by the limit engine.
+type LimitRules struct {
+	Tenors              []int
+	MinAge              int
+	MaxAgeAtMaturity    int
+	MinIncome           money.Amount
+	MaxInstallmentRatio money.Rate
+	InterestLoadRate    money.Rate
+	IncomeMultiplierCap money.Rate
+	RoundingUnit        money.Amount
+}
+
+type LimitEngineInput struct {
+	Consumer           *model.Consumer
+	MonthlyObligations money.Amount
+	AsOf               time.Time
+}
+
+type LimitEngine interface {
+	Calculate(input LimitEngineInput) []model.LimitCalculation
+}
+
+type limitEngine struct {
+	rules LimitRules
+}
+
+func NewLimitEngine(rules LimitRules) LimitEngine {
+	return &limitEngine{rules: rules}
+}
+
+func (e *limitEngine) Calculate(input LimitEngineInput) []model.LimitCalculation {
+	consumer := input.Consumer
+	age := ageAt(consumer.TanggalLahir, input.AsOf)
+	capacity := consumer.Gaji.Mul(e.rules.MaxInstallmentRatio, money.RoundDown) - input.MonthlyObligations
+
+	results := make([]model.LimitCalculation, 0, len(e.rules.Tenors))
+	for _, tenor := range e.rules.Tenors {
+		result := model.LimitCalculation{
+			ConsumerNIK: consumer.NIK,
+			Tenor:       tenor,
+		}
+
+		result.Factors = append(result.Factors, model.LimitFactor{
+			Name:        LimitFactorIncome,
+			Value:       consumer.Gaji.String(),
+			Description: fmt.Sprintf("monthly income, minimum required %s", e.rules.MinIncome),
+		})
+		if consumer.Gaji < e.rules.MinIncome {
+			result.Factors[len(result.Factors)-1].Rejected = true
+			results = append(results, result)
+			continue
+		}
+
+		ageFactor := model.LimitFactor{
+			Name:        LimitFactorAge,
+			Value:       strconv.Itoa(age),
+			Description: fmt.Sprintf("current age, minimum required %d", e.rules.MinAge),
+		}
+		if age < e.rules.MinAge {
+			ageFactor.Rejected = true
+			result.Factors = append(result.Factors, ageFactor)
+			results = append(results, result)
+			continue
+		}
+		result.Factors = append(result.Factors, ageFactor)
+
+		ageAtMaturity := ageAt(consumer.TanggalLahir, input.AsOf.AddDate(0, tenor, 0))
+		maturityFactor := model.LimitFactor{
+			Name:        LimitFactorAgeAtMaturity,
+			Value:       strconv.Itoa(ageAtMaturity),
+			Description: fmt.Sprintf("age when the last installment is due, maximum allowed %d", e.rules.MaxAgeAtMaturity),
+		}
+		if ageAtMaturity > e.rules.MaxAgeAtMaturity {
+			maturityFactor.Rejected = true
+			result.Factors = append(result.Factors, maturityFactor)
+			results = append(results, result)
+			continue
+		}
+		result.Factors = append(result.Factors, maturityFactor)
+
+		result.Factors = append(result.Factors, model.LimitFactor{
+			Name:        LimitFactorObligations,
+			Value:       input.MonthlyObligations.String(),
+			Description: "monthly installments of active contracts",
+		})
+
+		capacityFactor := model.LimitFactor{
+			Name:        LimitFactorInstallmentCapacity,
+			Value:       money.Max(capacity, 0).String(),
+			Description: fmt.Sprintf("%s%% of income minus existing obligations", e.rules.MaxInstallmentRatio.Percent()),
+		}
+		if capacity <= 0 {
+			capacityFactor.Rejected = true
+			result.Factors = append(result.Factors, capacityFactor)
+			results = append(results, result)
+			continue
+		}
+		result.Factors = append(result.Factors, capacityFactor)
+
+		// The principal whose installments, loaded with interest, fit the capacity.
+		amount := capacity.MulInt(int64(tenor)).DivRate(money.One+e.rules.InterestLoadRate, money.RoundDown)
+
+		if e.rules.IncomeMultiplierCap > 0 {
+			incomeCap := consumer.Gaji.Mul(e.rules.IncomeMultiplierCap, money.RoundDown)
+			result.Factors = append(result.Factors, model.LimitFactor{
+				Name:        LimitFactorIncomeCap,
+				Value:       incomeCap.String(),
+				Description: fmt.Sprintf("limit capped at %sx monthly income", e.rules.IncomeMultiplierCap),
+			})
+			amount = money.Min(amount, incomeCap)
+		}
+
+		if e.rules.RoundingUnit > 0 {
+			amount = amount.Round(e.rules.RoundingUnit, money.RoundDown)
+			result.Factors = append(result.Factors, model.LimitFactor{
+				Name:        LimitFactorRounding,
+				Value:       e.rules.RoundingUnit.String(),
+				Description: "limit rounded down to the nearest unit",
+			})
+		}
+
+		result.LimitAmount = amount
+		results = append(results, result)
+	}
+
+	return results
+}
+
+func ageAt(birthDate, at time.Time) int {
+	age := at.Year() - birthDate.Year()
+	if at.Month() < birthDate.Month() || (at.Month() == birthDate.Month() && at.Day() < birthDate.Day()) {
+		age--
+	}
+	return age
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimitEngine_Calculate(t *testing.T) {
+	rules := LimitRules{
+		Tenors:              []int{1, 6},
+		MinAge:              21,
+		MaxAgeAtMaturity:    60,
+		MinIncome:           money.FromRupiah(3000000),
+		MaxInstallmentRatio: money.MustParseRate("0.3"),
+		InterestLoadRate:    money.MustParseRate("0.05"),
+		IncomeMultiplierCap: money.MustParseRate("3"),
+		RoundingUnit:        money.FromRupiah(50000),
+	}
+	asOf := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		birthDate   time.Time
+		income      int64
+		obligations int64
+		expected    []int64
+	}{
+		{
+			name:      "capacity based limit capped by income multiplier",
+			birthDate: time.Date(1990, 5, 15, 0, 0, 0, 0, time.UTC),
+			income:    5000000,
+			expected:  []int64{1400000, 8550000},
+		},
+		{
+			name:        "obligations reduce capacity",
+			birthDate:   time.Date(1990, 5, 15, 0, 0, 0, 0, time.UTC),
+			income:      5000000,
+			obligations: 1000000,
+			expected:    []int64{450000, 2850000},
+		},
+		{
+			name:        "obligations exceeding capacity yield zero",
+			birthDate:   time.Date(1990, 5, 15, 0, 0, 0, 0, time.UTC),
+			income:      5000000,
+			obligations: 2000000,
+			expected:    []int64{0, 0},
+		},
+		{
+			name:      "income below minimum yields zero",
+			birthDate: time.Date(1990, 5, 15, 0, 0, 0, 0, time.UTC),
+			income:    2000000,
+			expected:  []int64{0, 0},
+		},
+		{
+			name:      "too young",
+			birthDate: time.Date(2005, 1, 2, 0, 0, 0, 0, time.UTC),
+			income:    5000000,
+			expected:  []int64{0, 0},
+		},
+		{
+			name:      "tenor ending after maximum age is rejected",
+			birthDate: time.Date(1964, 3, 1, 0, 0, 0, 0, time.UTC),
+			income:    5000000,
+			expected:  []int64{1400000, 0},
+		},
+	}
+
+	engine := NewLimitEngine(rules)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := engine.Calculate(LimitEngineInput{
+				Consumer: &model.Consumer{
+					NIK:          "1111",
+					TanggalLahir: tt.birthDate,
+					Gaji:         money.FromRupiah(tt.income),
+				},
+				MonthlyObligations: money.FromRupiah(tt.obligations),
+				AsOf:               asOf,
+			})
+
+			require.Len(t, results, len(tt.expected))
+			for i, expected := range tt.expected {
+				assert.Equal(t, rules.Tenors[i], results[i].Tenor)
+				assert.Equal(t, money.FromRupiah(expected), results[i].LimitAmount)
+				assert.NotEmpty(t, results[i].Factors)
+			}
+		})
+	}
+}
+
+func TestLimitEngine_AgeFactors(t *testing.T) {
+	rules := LimitRules{
+		Tenors:              []int{1, 6},
+		MinAge:              21,
+		MaxAgeAtMaturity:    60,
+		MinIncome:           money.FromRupiah(3000000),
+		MaxInstallmentRatio: money.MustParseRate("0.3"),
+	}
+	asOf := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	engine := NewLimitEngine(rules)
+
+	lastFactor := func(result model.LimitCalculation) model.LimitFactor {
+		return result.Factors[len(result.Factors)-1]
+	}
+
+	t.Run("too young is rejected on current age", func(t *testing.T) {
+		results := engine.Calculate(LimitEngineInput{
+			Consumer: &model.Consumer{TanggalLahir: time.Date(2005, 1, 2, 0, 0, 0, 0, time.UTC), Gaji: money.FromRupiah(5000000)},
+			AsOf:     asOf,
+		})
+
+		factor := lastFactor(results[0])
+		assert.Equal(t, LimitFactorAge, factor.Name)
+		assert.Equal(t, "19", factor.Value)
+		assert.True(t, factor.Rejected)
+	})
+
+	t.Run("tenor ending after maximum age is rejected on age at maturity", func(t *testing.T) {
+		results := engine.Calculate(LimitEngineInput{
+			Consumer: &model.Consumer{TanggalLahir: time.Date(1964, 3, 1, 0, 0, 0, 0, time.UTC), Gaji: money.FromRupiah(5000000)},
+			AsOf:     asOf,
+		})
+
+		assert.Equal(t, model.LimitFactor{Name: LimitFactorAge, Value: "60", Description: "current age, minimum required 21"}, results[1].Factors[1])
+		factor := lastFactor(results[1])
+		assert.Equal(t, LimitFactorAgeAtMaturity, factor.Name)
+		assert.Equal(t, "61", factor.Value)
+		assert.True(t, factor.Rejected)
+
+		assert.False(t, results[0].Factors[2].Rejected)
+		assert.Equal(t, "60", results[0].Factors[2].Value)
+	})
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/pkg/money"
+)
+
+var limitImportHeader = []string{"nik", "tenor", "limit_amount"}
+
+var limitImportResultHeader = []string{"line", "nik", "tenor", "limit_amount", "status", "error"}
+
+// LimitImportParser reads limit import files and writes their per-row
+// result files. Parse only fails when the file itself is unreadable; problems
+// with individual rows are reported on the row.
+type LimitImportParser interface {
+	Parse(r io.Reader) ([]model.LimitImportRow, error)
+	WriteResult(w io.Writer, rows []model.LimitImportRow) error
+}
+
+type limitImportParser struct {
+	tenors map[int]bool
+}
+
+func NewLimitImportParser(tenors []int) LimitImportParser {
+	allowed := make(map[int]bool, len(tenors))
+	for _, tenor := range tenors {
+		allowed[tenor] = true
+	}
+	return &limitImportParser{tenors: allowed}
+}
+
+func (p *limitImportParser) Parse(r io.Reader) ([]model.LimitImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("file is empty")
+		}
+		return nil, err
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+	if !sameColumns(header, limitImportHeader) {
+		return nil, fmt.Errorf("expected header %q, got %q", strings.Join(limitImportHeader, ","), strings.Join(header, ","))
+	}
+
+	var rows []model.LimitImportRow
+	seen := make(map[string]int)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		row := p.parseRecord(record)
+		row.Line = line
+		if row.Status == model.LimitImportRowValid {
+			key := fmt.Sprintf("%s:%d", row.ConsumerNIK, row.Tenor)
+			if firstLine, ok := seen[key]; ok {
+				row.Status = model.LimitImportRowInvalid
+				row.Error = fmt.Sprintf("duplicate of line %d", firstLine)
+			} else {
+				seen[key] = line
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func (p *limitImportParser) parseRecord(record []string) model.LimitImportRow {
+	row := model.LimitImportRow{Status: model.LimitImportRowInvalid}
+	if len(record) != len(limitImportHeader) {
+		row.Error = fmt.Sprintf("expected %d columns, got %d", len(limitImportHeader), len(record))
+		return row
+	}
+
+	row.ConsumerNIK = strings.TrimSpace(record[0])
+	if row.ConsumerNIK == "" || strings.Trim(row.ConsumerNIK, "0123456789") != "" {
+		row.Error = "nik must be numeric"
+		return row
+	}
+
+	tenor, err := strconv.Atoi(strings.TrimSpace(record[1]))
+	if err != nil {
+		row.Error = "tenor must be an integer"
+		return row
+	}
+	row.Tenor = tenor
+	if !p.tenors[tenor] {
+		row.Error = fmt.Sprintf("tenor %d is not offered", tenor)
+		return row
+	}
+
+	amount, err := money.Parse(record[2])
+	if err != nil {
+		row.Error = "limit_amount must be a decimal with at most 2 fraction digits"
+		return row
+	}
+	row.LimitAmount = amount
+	if amount < 0 {
+		row.Error = "limit_amount must not be negative"
+		return row
+	}
+
+	row.Status = model.LimitImportRowValid
+	return row
+}
+
+func (p *limitImportParser) WriteResult(w io.Writer, rows []model.LimitImportRow) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(limitImportResultHeader); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := []string{
+			strconv.Itoa(row.Line),
+			row.ConsumerNIK,
+			strconv.Itoa(row.Tenor),
+			row.LimitAmount.String(),
+			row.Status,
+			row.Error,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func sameColumns(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range want {
+		if strings.ToLower(strings.TrimSpace(got[i])) != want[i] {
+			return false
+		}
+	}
+	return true
+}
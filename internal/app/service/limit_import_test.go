@@ -0,0 +1,89 @@
+package service
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimitImportParser_Parse(t *testing.T) {
+	parser := NewLimitImportParser([]int{1, 3, 6})
+
+	tests := []struct {
+		name     string
+		input    string
+		expected []model.LimitImportRow
+	}{
+		{
+			name:  "valid rows",
+			input: "nik,tenor,limit_amount\n1111,3,1500000\n2222,6,2000000.50\n",
+			expected: []model.LimitImportRow{
+				{Line: 2, ConsumerNIK: "1111", Tenor: 3, LimitAmount: money.FromRupiah(1500000), Status: model.LimitImportRowValid},
+				{Line: 3, ConsumerNIK: "2222", Tenor: 6, LimitAmount: money.MustParse("2000000.50"), Status: model.LimitImportRowValid},
+			},
+		},
+		{
+			name:  "header with byte order mark and upper case",
+			input: "\ufeffNIK,Tenor,Limit_Amount\n1111,1,100000\n",
+			expected: []model.LimitImportRow{
+				{Line: 2, ConsumerNIK: "1111", Tenor: 1, LimitAmount: money.FromRupiah(100000), Status: model.LimitImportRowValid},
+			},
+		},
+		{
+			name:  "invalid rows are reported individually",
+			input: "nik,tenor,limit_amount\nabc,3,100\n1111,x,100\n1111,2,100\n1111,3,abc\n1111,3,-1\n1111,3\n",
+			expected: []model.LimitImportRow{
+				{Line: 2, ConsumerNIK: "abc", Status: model.LimitImportRowInvalid, Error: "nik must be numeric"},
+				{Line: 3, ConsumerNIK: "1111", Status: model.LimitImportRowInvalid, Error: "tenor must be an integer"},
+				{Line: 4, ConsumerNIK: "1111", Tenor: 2, Status: model.LimitImportRowInvalid, Error: "tenor 2 is not offered"},
+				{Line: 5, ConsumerNIK: "1111", Tenor: 3, Status: model.LimitImportRowInvalid, Error: "limit_amount must be a decimal with at most 2 fraction digits"},
+				{Line: 6, ConsumerNIK: "1111", Tenor: 3, LimitAmount: money.FromRupiah(-1), Status: model.LimitImportRowInvalid, Error: "limit_amount must not be negative"},
+				{Line: 7, Status: model.LimitImportRowInvalid, Error: "expected 3 columns, got 2"},
+			},
+		},
+		{
+			name:  "duplicate nik and tenor",
+			input: "nik,tenor,limit_amount\n1111,3,100\n1111,3,200\n",
+			expected: []model.LimitImportRow{
+				{Line: 2, ConsumerNIK: "1111", Tenor: 3, LimitAmount: money.FromRupiah(100), Status: model.LimitImportRowValid},
+				{Line: 3, ConsumerNIK: "1111", Tenor: 3, LimitAmount: money.FromRupiah(200), Status: model.LimitImportRowInvalid, Error: "duplicate of line 2"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := parser.Parse(strings.NewReader(tt.input))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, rows)
+		})
+	}
+}
+
+func TestLimitImportParser_ParseRejectsFile(t *testing.T) {
+	parser := NewLimitImportParser([]int{3})
+
+	_, err := parser.Parse(strings.NewReader(""))
+	assert.EqualError(t, err, "file is empty")
+
+	_, err = parser.Parse(strings.NewReader("nik,limit_amount\n1111,100\n"))
+	assert.Error(t, err)
+}
+
+func TestLimitImportParser_WriteResult(t *testing.T) {
+	parser := NewLimitImportParser([]int{3})
+	var buf bytes.Buffer
+
+	err := parser.WriteResult(&buf, []model.LimitImportRow{
+		{Line: 2, ConsumerNIK: "1111", Tenor: 3, LimitAmount: money.FromRupiah(1500000), Status: model.LimitImportRowApplied},
+		{Line: 3, ConsumerNIK: "2222", Tenor: 3, LimitAmount: money.FromRupiah(100), Status: model.LimitImportRowInvalid, Error: "consumer not found"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "line,nik,tenor,limit_amount,status,error\n2,1111,3,1500000.00,APPLIED,\n3,2222,3,100.00,INVALID,consumer not found\n", buf.String())
+}
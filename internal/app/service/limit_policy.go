@@ -0,0 +1,172 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/pkg/money"
+)
+
+const (
+	LimitPolicySharedPool   = "shared"
+	LimitPolicyPerTenorPool = "per_tenor"
+	LimitPolicyHierarchical = "hierarchical"
+)
+
+// ErrLimitExceeded is returned by CheckLimit when the amount does not fit in
+// what is left of the limit.
+var ErrLimitExceeded = errors.New("amount exceeds available limit")
+
+// LimitPolicy decides how contract usage is counted against a consumer's
+// limit for one tenor.
+type LimitPolicy interface {
+	Name() string
+	Calculate(limit model.ConsumerLimit, limits []model.ConsumerLimit, usages []model.TenorUsage) model.LimitUtilization
+}
+
+func NewLimitPolicy(name string) (LimitPolicy, error) {
+	switch name {
+	case LimitPolicySharedPool:
+		return sharedPoolPolicy{}, nil
+	case LimitPolicyPerTenorPool:
+		return perTenorPoolPolicy{}, nil
+	case LimitPolicyHierarchical:
+		return hierarchicalPolicy{}, nil
+	default:
+		return nil, fmt.Errorf("unknown limit policy %q", name)
+	}
+}
+
+// sharedPoolPolicy counts usage on every tenor against the requested tenor's
+// limit.
+type sharedPoolPolicy struct{}
+
+func (sharedPoolPolicy) Name() string {
+	return LimitPolicySharedPool
+}
+
+func (sharedPoolPolicy) Calculate(limit model.ConsumerLimit, _ []model.ConsumerLimit, usages []model.TenorUsage) model.LimitUtilization {
+	usage := sumUsage(usages, func(model.TenorUsage) bool { return true })
+	return newUtilization(limit, usage, limit.LimitAmount-total(usage))
+}
+
+// perTenorPoolPolicy gives every tenor its own pool; only contracts with the
+// same tenor count against its limit.
+type perTenorPoolPolicy struct{}
+
+func (perTenorPoolPolicy) Name() string {
+	return LimitPolicyPerTenorPool
+}
+
+func (perTenorPoolPolicy) Calculate(limit model.ConsumerLimit, _ []model.ConsumerLimit, usages []model.TenorUsage) model.LimitUtilization {
+	usage := sumUsage(usages, func(usage model.TenorUsage) bool { return usage.Tenor == limit.Tenor })
+	return newUtilization(limit, usage, limit.LimitAmount-total(usage))
+}
+
+// hierarchicalPolicy gives every tenor its own pool but caps the total usage
+// across tenors at an overall ceiling, which is the consumer's highest tenor
+// limit.
+type hierarchicalPolicy struct{}
+
+func (hierarchicalPolicy) Name() string {
+	return LimitPolicyHierarchical
+}
+
+func (hierarchicalPolicy) Calculate(limit model.ConsumerLimit, limits []model.ConsumerLimit, usages []model.TenorUsage) model.LimitUtilization {
+	usage := sumUsage(usages, func(usage model.TenorUsage) bool { return usage.Tenor == limit.Tenor })
+	overallUsage := sumUsage(usages, func(model.TenorUsage) bool { return true })
+
+	ceiling := limit.LimitAmount
+	for _, other := range limits {
+		if other.LimitAmount > ceiling {
+			ceiling = other.LimitAmount
+		}
+	}
+
+	available := limit.LimitAmount - total(usage)
+	if overall := ceiling - total(overallUsage); overall < available {
+		available = overall
+	}
+
+	return newUtilization(limit, usage, available)
+}
+
+func sumUsage(usages []model.TenorUsage, include func(model.TenorUsage) bool) model.TenorUsage {
+	var sum model.TenorUsage
+	for _, usage := range usages {
+		if include(usage) {
+			sum.Used += usage.Used
+			sum.Pending += usage.Pending
+			sum.Held += usage.Held
+		}
+	}
+	return sum
+}
+
+func total(usage model.TenorUsage) money.Amount {
+	return usage.Used + usage.Pending + usage.Held
+}
+
+func newUtilization(limit model.ConsumerLimit, usage model.TenorUsage, available money.Amount) model.LimitUtilization {
+	if available < 0 {
+		available = 0
+	}
+	return model.LimitUtilization{
+		Tenor:     limit.Tenor,
+		Limit:     limit.LimitAmount,
+		Used:      usage.Used,
+		Pending:   usage.Pending,
+		Held:      usage.Held,
+		Available: available,
+	}
+}
+
+// LimitPolicyResolver selects the limit policy configured for a product,
+// falling back to the default policy.
+type LimitPolicyResolver interface {
+	Resolve(productCode string) LimitPolicy
+}
+
+type limitPolicyResolver struct {
+	defaultPolicy LimitPolicy
+	byProduct     map[string]LimitPolicy
+}
+
+func NewLimitPolicyResolver(defaultPolicy string, byProduct map[string]string) (LimitPolicyResolver, error) {
+	resolver := &limitPolicyResolver{byProduct: make(map[string]LimitPolicy, len(byProduct))}
+
+	policy, err := NewLimitPolicy(defaultPolicy)
+	if err != nil {
+		return nil, err
+	}
+	resolver.defaultPolicy = policy
+
+	for productCode, name := range byProduct {
+		policy, err := NewLimitPolicy(name)
+		if err != nil {
+			return nil, fmt.Errorf("product %s: %w", productCode, err)
+		}
+		resolver.byProduct[productCode] = policy
+	}
+
+	return resolver, nil
+}
+
+func (r *limitPolicyResolver) Resolve(productCode string) LimitPolicy {
+	if policy, ok := r.byProduct[productCode]; ok {
+		return policy
+	}
+	return r.defaultPolicy
+}
+
+// CheckLimit counts usages, the contract usage and unexpired holds of the
+// consumer, against limit under the policy configured for productCode and
+// reports whether amount still fits.
+func CheckLimit(resolver LimitPolicyResolver, productCode string, limit model.ConsumerLimit, limits []model.ConsumerLimit, usages []model.TenorUsage, amount money.Amount) (model.LimitUtilization, error) {
+	utilization := resolver.Resolve(productCode).Calculate(limit, limits, usages)
+	if amount > utilization.Available {
+		return utilization, ErrLimitExceeded
+	}
+	return utilization, nil
+}
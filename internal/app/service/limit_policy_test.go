@@ -0,0 +1,281 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimitPolicy_Calculate(t *testing.T) {
+	limits := []model.ConsumerLimit{
+		{ConsumerNIK: "1111", Tenor: 1, LimitAmount: money.FromRupiah(100000)},
+		{ConsumerNIK: "1111", Tenor: 3, LimitAmount: money.FromRupiah(500000)},
+		{ConsumerNIK: "1111", Tenor: 6, LimitAmount: money.FromRupiah(700000)},
+	}
+
+	tests := []struct {
+		name              string
+		policy            string
+		tenor             int
+		usages            []model.TenorUsage
+		expectedUsed      money.Amount
+		expectedPending   money.Amount
+		expectedHeld      money.Amount
+		expectedAvailable money.Amount
+	}{
+		{
+			name:              "shared pool without usage",
+			policy:            LimitPolicySharedPool,
+			tenor:             3,
+			expectedAvailable: money.FromRupiah(500000),
+		},
+		{
+			name:   "shared pool counts usage on every tenor",
+			policy: LimitPolicySharedPool,
+			tenor:  3,
+			usages: []model.TenorUsage{
+				{Tenor: 1, Used: money.FromRupiah(50000)},
+				{Tenor: 6, Used: money.FromRupiah(200000), Pending: money.FromRupiah(100000)},
+			},
+			expectedUsed:      money.FromRupiah(250000),
+			expectedPending:   money.FromRupiah(100000),
+			expectedAvailable: money.FromRupiah(150000),
+		},
+		{
+			name:   "shared pool never goes below zero",
+			policy: LimitPolicySharedPool,
+			tenor:  1,
+			usages: []model.TenorUsage{
+				{Tenor: 6, Used: money.FromRupiah(600000)},
+			},
+			expectedUsed:      money.FromRupiah(600000),
+			expectedAvailable: 0,
+		},
+		{
+			name:   "shared pool counts held amounts",
+			policy: LimitPolicySharedPool,
+			tenor:  6,
+			usages: []model.TenorUsage{
+				{Tenor: 1, Held: money.FromRupiah(100000)},
+				{Tenor: 6, Used: money.FromRupiah(200000), Held: money.FromRupiah(150000)},
+			},
+			expectedUsed:      money.FromRupiah(200000),
+			expectedHeld:      money.FromRupiah(250000),
+			expectedAvailable: money.FromRupiah(250000),
+		},
+		{
+			name:   "per tenor pool ignores other tenors",
+			policy: LimitPolicyPerTenorPool,
+			tenor:  3,
+			usages: []model.TenorUsage{
+				{Tenor: 1, Used: money.FromRupiah(50000)},
+				{Tenor: 6, Used: money.FromRupiah(600000)},
+			},
+			expectedAvailable: money.FromRupiah(500000),
+		},
+		{
+			name:   "per tenor pool counts same tenor usage",
+			policy: LimitPolicyPerTenorPool,
+			tenor:  3,
+			usages: []model.TenorUsage{
+				{Tenor: 3, Used: money.FromRupiah(200000), Pending: money.FromRupiah(50000)},
+				{Tenor: 6, Used: money.FromRupiah(600000)},
+			},
+			expectedUsed:      money.FromRupiah(200000),
+			expectedPending:   money.FromRupiah(50000),
+			expectedAvailable: money.FromRupiah(250000),
+		},
+		{
+			name:   "hierarchical limited by tenor pool",
+			policy: LimitPolicyHierarchical,
+			tenor:  3,
+			usages: []model.TenorUsage{
+				{Tenor: 3, Used: money.FromRupiah(400000)},
+			},
+			expectedUsed:      money.FromRupiah(400000),
+			expectedAvailable: money.FromRupiah(100000),
+		},
+		{
+			name:   "hierarchical limited by overall ceiling",
+			policy: LimitPolicyHierarchical,
+			tenor:  3,
+			usages: []model.TenorUsage{
+				{Tenor: 1, Used: money.FromRupiah(100000)},
+				{Tenor: 6, Used: money.FromRupiah(450000), Pending: money.FromRupiah(50000)},
+			},
+			expectedAvailable: money.FromRupiah(100000),
+		},
+		{
+			name:   "hierarchical counts holds on other tenors against the ceiling",
+			policy: LimitPolicyHierarchical,
+			tenor:  1,
+			usages: []model.TenorUsage{
+				{Tenor: 6, Used: money.FromRupiah(500000), Held: money.FromRupiah(150000)},
+			},
+			expectedAvailable: money.FromRupiah(50000),
+		},
+		{
+			name:   "hierarchical with exhausted ceiling",
+			policy: LimitPolicyHierarchical,
+			tenor:  1,
+			usages: []model.TenorUsage{
+				{Tenor: 6, Used: money.FromRupiah(700000)},
+			},
+			expectedAvailable: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := NewLimitPolicy(tt.policy)
+			require.NoError(t, err)
+
+			var limit model.ConsumerLimit
+			for _, l := range limits {
+				if l.Tenor == tt.tenor {
+					limit = l
+				}
+			}
+
+			utilization := policy.Calculate(limit, limits, tt.usages)
+
+			assert.Equal(t, tt.tenor, utilization.Tenor)
+			assert.Equal(t, limit.LimitAmount, utilization.Limit)
+			assert.Equal(t, tt.expectedUsed, utilization.Used)
+			assert.Equal(t, tt.expectedPending, utilization.Pending)
+			assert.Equal(t, tt.expectedHeld, utilization.Held)
+			assert.Equal(t, tt.expectedAvailable, utilization.Available)
+		})
+	}
+}
+
+func TestNewLimitPolicy_Unknown(t *testing.T) {
+	policy, err := NewLimitPolicy("unknown")
+	assert.Error(t, err)
+	assert.Nil(t, policy)
+}
+
+func TestLimitPolicyResolver_Resolve(t *testing.T) {
+	resolver, err := NewLimitPolicyResolver(LimitPolicySharedPool, map[string]string{
+		"cash_loan":   LimitPolicyPerTenorPool,
+		"white_goods": LimitPolicyHierarchical,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, LimitPolicySharedPool, resolver.Resolve("").Name())
+	assert.Equal(t, LimitPolicySharedPool, resolver.Resolve("paylater").Name())
+	assert.Equal(t, LimitPolicyPerTenorPool, resolver.Resolve("cash_loan").Name())
+	assert.Equal(t, LimitPolicyHierarchical, resolver.Resolve("white_goods").Name())
+
+	_, err = NewLimitPolicyResolver(LimitPolicySharedPool, map[string]string{"motorcycle": "bogus"})
+	assert.Error(t, err)
+}
+
+func TestCheckLimit(t *testing.T) {
+	// Mirrors LIMIT_POLICY_DEFAULT=shared with
+	// LIMIT_POLICY_BY_PRODUCT=cash_loan:per_tenor,white_goods:hierarchical.
+	resolver, err := NewLimitPolicyResolver(LimitPolicySharedPool, map[string]string{
+		"cash_loan":   LimitPolicyPerTenorPool,
+		"white_goods": LimitPolicyHierarchical,
+	})
+	require.NoError(t, err)
+
+	limits := []model.ConsumerLimit{
+		{ConsumerNIK: "1111", Tenor: 3, LimitAmount: money.FromRupiah(500000)},
+		{ConsumerNIK: "1111", Tenor: 6, LimitAmount: money.FromRupiah(1000000)},
+	}
+	// Contract usage as returned by the transaction repository followed by
+	// the holds returned by the limit hold repository, as loaded for a
+	// contract.
+	contracts := []model.TenorUsage{
+		{Tenor: 3, Used: money.FromRupiah(200000), Pending: money.FromRupiah(100000)},
+		{Tenor: 6, Used: money.FromRupiah(400000)},
+	}
+	holds := []model.TenorUsage{
+		{Tenor: 3, Held: money.FromRupiah(150000)},
+		{Tenor: 6, Held: money.FromRupiah(200000)},
+	}
+	usages := append(append([]model.TenorUsage{}, contracts...), holds...)
+
+	tests := []struct {
+		name              string
+		productCode       string
+		limit             model.ConsumerLimit
+		usages            []model.TenorUsage
+		amount            money.Amount
+		expectedAvailable money.Amount
+		expectedErr       error
+	}{
+		{
+			name:              "per tenor product fits what contracts and holds leave on its tenor",
+			productCode:       "cash_loan",
+			limit:             limits[0],
+			usages:            usages,
+			amount:            money.FromRupiah(50000),
+			expectedAvailable: money.FromRupiah(50000),
+		},
+		{
+			name:              "per tenor product rejects an over-limit contract",
+			productCode:       "cash_loan",
+			limit:             limits[0],
+			usages:            usages,
+			amount:            money.FromRupiah(60000),
+			expectedAvailable: money.FromRupiah(50000),
+			expectedErr:       ErrLimitExceeded,
+		},
+		{
+			name:              "per tenor product would fit without the holds",
+			productCode:       "cash_loan",
+			limit:             limits[0],
+			usages:            contracts,
+			amount:            money.FromRupiah(60000),
+			expectedAvailable: money.FromRupiah(200000),
+		},
+		{
+			name:              "per tenor product ignores usage on other tenors",
+			productCode:       "cash_loan",
+			limit:             limits[1],
+			usages:            usages,
+			amount:            money.FromRupiah(400000),
+			expectedAvailable: money.FromRupiah(400000),
+		},
+		{
+			name:              "hierarchical product rejects a contract over the overall ceiling",
+			productCode:       "white_goods",
+			limit:             limits[1],
+			usages:            usages,
+			amount:            money.FromRupiah(1),
+			expectedAvailable: 0,
+			expectedErr:       ErrLimitExceeded,
+		},
+		{
+			name:              "hierarchical product rejects a contract over its tenor pool",
+			productCode:       "white_goods",
+			limit:             limits[0],
+			usages:            contracts,
+			amount:            money.FromRupiah(250000),
+			expectedAvailable: money.FromRupiah(200000),
+			expectedErr:       ErrLimitExceeded,
+		},
+		{
+			name:              "unmapped product falls back to the shared pool",
+			productCode:       "paylater",
+			limit:             limits[1],
+			usages:            contracts,
+			amount:            money.FromRupiah(300000),
+			expectedAvailable: money.FromRupiah(300000),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilization, err := CheckLimit(resolver, tt.productCode, tt.limit, limits, tt.usages, tt.amount)
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedAvailable, utilization.Available)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/pkg/money"
+)
+
+// ValidateMDRRate checks that a merchant discount rate is a fraction of the
+// OTR: zero or more and below one.
+func ValidateMDRRate(rate money.Rate) error {
+	if rate < 0 || rate >= money.One {
+		return errors.New("mdr_rate must be at least 0 and less than 1")
+	}
+	return nil
+}
+
+// CheckMerchantOrigin validates that a contract may be originated at the
+// merchant and, when given, the outlet. The returned error is meant for the
+// caller.
+func CheckMerchantOrigin(merchant model.Merchant, outlet *model.MerchantOutlet) error {
+	if merchant.Status != model.MerchantStatusActive {
+		return fmt.Errorf("merchant %s is not active", merchant.Code)
+	}
+	if outlet == nil {
+		return nil
+	}
+	if outlet.MerchantID != merchant.ID {
+		return fmt.Errorf("outlet %s does not belong to merchant %s", outlet.Code, merchant.Code)
+	}
+	if outlet.Status != model.OutletStatusActive {
+		return fmt.Errorf("outlet %s is not active", outlet.Code)
+	}
+	return nil
+}
+
+// CalculateMDR is the merchant discount withheld from the OTR, rounded to
+// whole rupiah.
+func CalculateMDR(otr money.Amount, rate money.Rate) money.Amount {
+	return otr.Mul(rate, money.RoundHalfUp).Round(money.Rupiah, money.RoundHalfUp)
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateMDRRate(t *testing.T) {
+	assert.NoError(t, ValidateMDRRate(money.Rate(0)))
+	assert.NoError(t, ValidateMDRRate(money.MustParseRate("0.015")))
+	assert.EqualError(t, ValidateMDRRate(money.One), "mdr_rate must be at least 0 and less than 1")
+	assert.EqualError(t, ValidateMDRRate(money.MustParseRate("-0.01")), "mdr_rate must be at least 0 and less than 1")
+}
+
+func TestCheckMerchantOrigin(t *testing.T) {
+	merchant := model.Merchant{ID: 3, Code: "ELEKTRO", Status: model.MerchantStatusActive}
+	outlet := model.MerchantOutlet{ID: 9, MerchantID: 3, Code: "JKT-01", Status: model.OutletStatusActive}
+
+	tests := []struct {
+		name     string
+		merchant func(m *model.Merchant)
+		outlet   func(o *model.MerchantOutlet)
+		noOutlet bool
+		expected string
+	}{
+		{
+			name: "active merchant and outlet",
+		},
+		{
+			name:     "active merchant without outlet",
+			noOutlet: true,
+		},
+		{
+			name:     "suspended merchant",
+			merchant: func(m *model.Merchant) { m.Status = model.MerchantStatusSuspended },
+			expected: "merchant ELEKTRO is not active",
+		},
+		{
+			name:     "terminated merchant without outlet",
+			merchant: func(m *model.Merchant) { m.Status = model.MerchantStatusTerminated },
+			noOutlet: true,
+			expected: "merchant ELEKTRO is not active",
+		},
+		{
+			name:     "outlet of another merchant",
+			outlet:   func(o *model.MerchantOutlet) { o.MerchantID = 4 },
+			expected: "outlet JKT-01 does not belong to merchant ELEKTRO",
+		},
+		{
+			name:     "closed outlet",
+			outlet:   func(o *model.MerchantOutlet) { o.Status = model.OutletStatusClosed },
+			expected: "outlet JKT-01 is not active",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := merchant
+			if tt.merchant != nil {
+				tt.merchant(&m)
+			}
+			var o *model.MerchantOutlet
+			if !tt.noOutlet {
+				copied := outlet
+				if tt.outlet != nil {
+					tt.outlet(&copied)
+				}
+				o = &copied
+			}
+
+			err := CheckMerchantOrigin(m, o)
+			if tt.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCalculateMDR(t *testing.T) {
+	assert.Equal(t, money.FromRupiah(45000), CalculateMDR(money.FromRupiah(3000000), money.MustParseRate("0.015")))
+	assert.Equal(t, money.FromRupiah(18519), CalculateMDR(money.FromRupiah(1234567), money.MustParseRate("0.015")))
+	assert.Equal(t, money.Zero, CalculateMDR(money.FromRupiah(3000000), money.Rate(0)))
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/pkg/money"
+)
+
+var paymentComponents = []string{
+	model.PaymentComponentPenalty,
+	model.PaymentComponentAdminFee,
+	model.PaymentComponentInterest,
+	model.PaymentComponentPrincipal,
+}
+
+// PaymentAllocator distributes payments over a contract's installments.
+type PaymentAllocator struct {
+	order []string
+}
+
+// NewPaymentAllocator checks that order lists every payment component exactly
+// once.
+func NewPaymentAllocator(order []string) (*PaymentAllocator, error) {
+	if len(order) != len(paymentComponents) {
+		return nil, fmt.Errorf("allocation order must list %v, got %v", paymentComponents, order)
+	}
+	for i, component := range order {
+		if !slices.Contains(paymentComponents, component) {
+			return nil, fmt.Errorf("unknown payment component %q", component)
+		}
+		if slices.Contains(order[:i], component) {
+			return nil, fmt.Errorf("payment component %q is listed twice", component)
+		}
+	}
+	return &PaymentAllocator{order: slices.Clone(order)}, nil
+}
+
+// Allocate applies amount to the installments oldest first. Each installment
+// is settled component by component in the allocator's order before the next
+// one receives anything, so a partial payment always leaves the earliest
+// installment with the least outstanding. Paid amounts and statuses are
+// updated in place. The returned remainder is what is left once every
+// installment is paid.
+func (a *PaymentAllocator) Allocate(amount money.Amount, installments []model.Installment) ([]model.PaymentAllocation, money.Amount) {
+	var allocations []model.PaymentAllocation
+	remaining := amount
+
+	for i := range installments {
+		if remaining == 0 {
+			break
+		}
+		installment := &installments[i]
+
+		for _, component := range a.order {
+			due, paid := componentBalance(installment, component)
+			applied := min(due-*paid, remaining)
+			if applied <= 0 {
+				continue
+			}
+			*paid += applied
+			remaining -= applied
+			allocations = append(allocations, model.PaymentAllocation{
+				InstallmentNumber: installment.InstallmentNumber,
+				Component:         component,
+				Amount:            applied,
+			})
+		}
+		installment.Status = InstallmentStatus(*installment)
+	}
+
+	return allocations, remaining
+}
+
+// InstallmentOutstanding returns what is still owed on the installment,
+// penalties included.
+func InstallmentOutstanding(installment model.Installment) money.Amount {
+	return installment.TotalAmount + installment.Penalty -
+		installment.PaidPrincipal - installment.PaidInterest - installment.PaidAdminFee - installment.PaidPenalty
+}
+
+func InstallmentStatus(installment model.Installment) string {
+	switch {
+	case InstallmentOutstanding(installment) == 0:
+		return model.InstallmentStatusPaid
+	case installment.PaidPrincipal+installment.PaidInterest+installment.PaidAdminFee+installment.PaidPenalty > 0:
+		return model.InstallmentStatusPartial
+	default:
+		return model.InstallmentStatusUnpaid
+	}
+}
+
+func componentBalance(installment *model.Installment, component string) (money.Amount, *money.Amount) {
+	switch component {
+	case model.PaymentComponentPenalty:
+		return installment.Penalty, &installment.PaidPenalty
+	case model.PaymentComponentAdminFee:
+		return installment.AdminFee, &installment.PaidAdminFee
+	case model.PaymentComponentInterest:
+		return installment.Interest, &installment.PaidInterest
+	default:
+		return installment.Principal, &installment.PaidPrincipal
+	}
+}
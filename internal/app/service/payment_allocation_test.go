@@ -0,0 +1,187 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func unpaidInstallment(number int, principal, interest, adminFee, penalty int64) model.Installment {
+	return model.Installment{
+		InstallmentNumber: number,
+		Principal:         money.FromRupiah(principal),
+		Interest:          money.FromRupiah(interest),
+		AdminFee:          money.FromRupiah(adminFee),
+		TotalAmount:       money.FromRupiah(principal + interest + adminFee),
+		Penalty:           money.FromRupiah(penalty),
+		Status:            model.InstallmentStatusUnpaid,
+	}
+}
+
+func TestNewPaymentAllocator(t *testing.T) {
+	tests := []struct {
+		name     string
+		order    []string
+		expected string
+	}{
+		{
+			name:  "default order",
+			order: []string{"PENALTY", "ADMIN_FEE", "INTEREST", "PRINCIPAL"},
+		},
+		{
+			name:  "principal first",
+			order: []string{"PRINCIPAL", "INTEREST", "ADMIN_FEE", "PENALTY"},
+		},
+		{
+			name:     "missing component",
+			order:    []string{"PENALTY", "INTEREST", "PRINCIPAL"},
+			expected: "allocation order must list [PENALTY ADMIN_FEE INTEREST PRINCIPAL], got [PENALTY INTEREST PRINCIPAL]",
+		},
+		{
+			name:     "unknown component",
+			order:    []string{"PENALTY", "ADMIN_FEE", "INTEREST", "TAX"},
+			expected: `unknown payment component "TAX"`,
+		},
+		{
+			name:     "duplicate component",
+			order:    []string{"PENALTY", "INTEREST", "INTEREST", "PRINCIPAL"},
+			expected: `payment component "INTEREST" is listed twice`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPaymentAllocator(tt.order)
+			if tt.expected == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
+
+func TestPaymentAllocator_Allocate(t *testing.T) {
+	defaultOrder := []string{"PENALTY", "ADMIN_FEE", "INTEREST", "PRINCIPAL"}
+
+	tests := []struct {
+		name                string
+		order               []string
+		amount              money.Amount
+		installments        []model.Installment
+		expectedAllocations []model.PaymentAllocation
+		expectedRemainder   money.Amount
+		expectedStatuses    []string
+	}{
+		{
+			name:   "exact installment",
+			order:  defaultOrder,
+			amount: money.FromRupiah(368333),
+			installments: []model.Installment{
+				unpaidInstallment(1, 333333, 25000, 10000, 0),
+				unpaidInstallment(2, 333333, 25000, 10000, 0),
+			},
+			expectedAllocations: []model.PaymentAllocation{
+				{InstallmentNumber: 1, Component: "ADMIN_FEE", Amount: money.FromRupiah(10000)},
+				{InstallmentNumber: 1, Component: "INTEREST", Amount: money.FromRupiah(25000)},
+				{InstallmentNumber: 1, Component: "PRINCIPAL", Amount: money.FromRupiah(333333)},
+			},
+			expectedStatuses: []string{"PAID", "UNPAID"},
+		},
+		{
+			name:   "partial payment settles penalty first",
+			order:  defaultOrder,
+			amount: money.FromRupiah(30000),
+			installments: []model.Installment{
+				unpaidInstallment(1, 333333, 25000, 10000, 5000),
+			},
+			expectedAllocations: []model.PaymentAllocation{
+				{InstallmentNumber: 1, Component: "PENALTY", Amount: money.FromRupiah(5000)},
+				{InstallmentNumber: 1, Component: "ADMIN_FEE", Amount: money.FromRupiah(10000)},
+				{InstallmentNumber: 1, Component: "INTEREST", Amount: money.FromRupiah(15000)},
+			},
+			expectedStatuses: []string{"PARTIAL"},
+		},
+		{
+			name:   "configured order",
+			order:  []string{"PRINCIPAL", "INTEREST", "ADMIN_FEE", "PENALTY"},
+			amount: money.FromRupiah(340000),
+			installments: []model.Installment{
+				unpaidInstallment(1, 333333, 25000, 10000, 5000),
+			},
+			expectedAllocations: []model.PaymentAllocation{
+				{InstallmentNumber: 1, Component: "PRINCIPAL", Amount: money.FromRupiah(333333)},
+				{InstallmentNumber: 1, Component: "INTEREST", Amount: money.FromRupiah(6667)},
+			},
+			expectedStatuses: []string{"PARTIAL"},
+		},
+		{
+			name:   "skips paid installments and continues into the next",
+			order:  defaultOrder,
+			amount: money.FromRupiah(100000),
+			installments: []model.Installment{
+				func() model.Installment {
+					installment := unpaidInstallment(1, 100000, 10000, 0, 0)
+					installment.PaidPrincipal = money.FromRupiah(100000)
+					installment.PaidInterest = money.FromRupiah(10000)
+					installment.Status = model.InstallmentStatusPaid
+					return installment
+				}(),
+				func() model.Installment {
+					installment := unpaidInstallment(2, 100000, 10000, 0, 0)
+					installment.PaidInterest = money.FromRupiah(4000)
+					installment.Status = model.InstallmentStatusPartial
+					return installment
+				}(),
+				unpaidInstallment(3, 100000, 10000, 0, 0),
+			},
+			expectedAllocations: []model.PaymentAllocation{
+				{InstallmentNumber: 2, Component: "INTEREST", Amount: money.FromRupiah(6000)},
+				{InstallmentNumber: 2, Component: "PRINCIPAL", Amount: money.FromRupiah(94000)},
+			},
+			expectedStatuses: []string{"PAID", "PARTIAL", "UNPAID"},
+		},
+		{
+			name:   "over-payment leaves a remainder",
+			order:  defaultOrder,
+			amount: money.MustParse("250000.50"),
+			installments: []model.Installment{
+				unpaidInstallment(1, 100000, 10000, 0, 0),
+				unpaidInstallment(2, 100000, 10000, 0, 0),
+			},
+			expectedAllocations: []model.PaymentAllocation{
+				{InstallmentNumber: 1, Component: "INTEREST", Amount: money.FromRupiah(10000)},
+				{InstallmentNumber: 1, Component: "PRINCIPAL", Amount: money.FromRupiah(100000)},
+				{InstallmentNumber: 2, Component: "INTEREST", Amount: money.FromRupiah(10000)},
+				{InstallmentNumber: 2, Component: "PRINCIPAL", Amount: money.FromRupiah(100000)},
+			},
+			expectedRemainder: money.MustParse("30000.50"),
+			expectedStatuses:  []string{"PAID", "PAID"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allocator, err := NewPaymentAllocator(tt.order)
+			require.NoError(t, err)
+
+			allocations, remainder := allocator.Allocate(tt.amount, tt.installments)
+
+			assert.Equal(t, tt.expectedAllocations, allocations)
+			assert.Equal(t, tt.expectedRemainder, remainder)
+
+			var allocated money.Amount
+			for _, allocation := range allocations {
+				allocated += allocation.Amount
+			}
+			assert.Equal(t, tt.amount, allocated+remainder)
+
+			for i, status := range tt.expectedStatuses {
+				assert.Equal(t, status, tt.installments[i].Status, "installment %d", i+1)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/pkg/money"
+)
+
+// PenaltyLimits are the regulatory maximums on late-payment penalties. A
+// product asking for more is held to them.
+type PenaltyLimits struct {
+	MaxDailyRate money.Rate
+	MaxCapRate   money.Rate
+}
+
+type PenaltyCalculator struct {
+	limits PenaltyLimits
+}
+
+func NewPenaltyCalculator(limits PenaltyLimits) (*PenaltyCalculator, error) {
+	if limits.MaxDailyRate < 0 || limits.MaxCapRate < 0 {
+		return nil, errors.New("penalty limits must not be negative")
+	}
+	return &PenaltyCalculator{limits: limits}, nil
+}
+
+// Accrue brings the penalty of an installment up to asOf. An installment is
+// overdue once it is still open after its due date plus the product's grace
+// period; from then on it is charged the daily rate on its unpaid principal,
+// interest and admin fee for every day since the due date, until the penalty
+// reaches the cap rate of the installment's total amount. Days up to
+// PenaltyAccruedTo have already been charged and are skipped.
+//
+// The installment is updated in place. The bool reports whether it is
+// overdue; the charge is nil when nothing new is owed.
+func (c *PenaltyCalculator) Accrue(product model.Product, installment *model.Installment, asOf time.Time) (*model.PenaltyCharge, bool) {
+	if installment.Status != model.InstallmentStatusUnpaid && installment.Status != model.InstallmentStatusPartial {
+		return nil, false
+	}
+	base := installment.TotalAmount - installment.PaidPrincipal - installment.PaidInterest - installment.PaidAdminFee
+	if base <= 0 {
+		return nil, false
+	}
+
+	today := dateOf(asOf)
+	dueDate := dateOf(installment.DueDate)
+	overdueSince := dueDate.AddDate(0, 0, product.GracePeriodDays+1)
+	if today.Before(overdueSince) {
+		return nil, false
+	}
+	if installment.OverdueSince == nil {
+		installment.OverdueSince = &overdueSince
+	}
+
+	from := dueDate
+	if installment.PenaltyAccruedTo != nil && dateOf(*installment.PenaltyAccruedTo).After(from) {
+		from = dateOf(*installment.PenaltyAccruedTo)
+	}
+	days := int(today.Sub(from).Hours() / 24)
+	if days <= 0 {
+		return nil, true
+	}
+	installment.PenaltyAccruedTo = &today
+
+	rate := min(product.PenaltyDailyRate, c.limits.MaxDailyRate)
+	capRate := min(product.PenaltyCapRate, c.limits.MaxCapRate)
+	penaltyCap := installment.TotalAmount.Mul(capRate, money.RoundDown).Round(money.Rupiah, money.RoundDown)
+
+	amount := base.MulInt(int64(days)).Mul(rate, money.RoundHalfUp).Round(money.Rupiah, money.RoundHalfUp)
+	amount = min(amount, penaltyCap-installment.Penalty)
+	if amount <= 0 {
+		return nil, true
+	}
+	installment.Penalty += amount
+
+	return &model.PenaltyCharge{
+		NomorKontrak:      installment.NomorKontrak,
+		InstallmentNumber: installment.InstallmentNumber,
+		ChargeDate:        today,
+		Days:              days,
+		BaseAmount:        base,
+		DailyRate:         rate,
+		Amount:            amount,
+	}, true
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func penaltyProduct() model.Product {
+	return model.Product{
+		Code:             "white_goods",
+		GracePeriodDays:  3,
+		PenaltyDailyRate: money.MustParseRate("0.001"),
+		PenaltyCapRate:   money.MustParseRate("0.1"),
+	}
+}
+
+// overdueInstallment is an unpaid installment of 368,333 due on 10 April 2025.
+func overdueInstallment() model.Installment {
+	installment := unpaidInstallment(1, 333333, 25000, 10000, 0)
+	installment.NomorKontrak = "TRX1"
+	installment.DueDate = time.Date(2025, 4, 10, 0, 0, 0, 0, time.UTC)
+	return installment
+}
+
+func penaltyLimits() PenaltyLimits {
+	return PenaltyLimits{MaxDailyRate: money.MustParseRate("0.002"), MaxCapRate: money.MustParseRate("0.2")}
+}
+
+func TestNewPenaltyCalculator(t *testing.T) {
+	_, err := NewPenaltyCalculator(PenaltyLimits{MaxDailyRate: -1})
+	assert.EqualError(t, err, "penalty limits must not be negative")
+}
+
+func TestPenaltyCalculator_Accrue(t *testing.T) {
+	calculator, err := NewPenaltyCalculator(penaltyLimits())
+	require.NoError(t, err)
+
+	t.Run("within grace period", func(t *testing.T) {
+		installment := overdueInstallment()
+
+		charge, overdue := calculator.Accrue(penaltyProduct(), &installment, time.Date(2025, 4, 13, 23, 0, 0, 0, time.UTC))
+
+		assert.Nil(t, charge)
+		assert.False(t, overdue)
+		assert.Nil(t, installment.OverdueSince)
+		assert.Equal(t, money.Zero, installment.Penalty)
+	})
+
+	t.Run("first day overdue charges every day since the due date", func(t *testing.T) {
+		installment := overdueInstallment()
+
+		charge, overdue := calculator.Accrue(penaltyProduct(), &installment, time.Date(2025, 4, 14, 1, 0, 0, 0, time.UTC))
+
+		require.True(t, overdue)
+		require.NotNil(t, charge)
+		assert.Equal(t, 4, charge.Days)
+		assert.Equal(t, money.FromRupiah(368333), charge.BaseAmount)
+		assert.Equal(t, money.FromRupiah(1473), charge.Amount)
+		assert.Equal(t, time.Date(2025, 4, 14, 0, 0, 0, 0, time.UTC), charge.ChargeDate)
+		assert.Equal(t, money.FromRupiah(1473), installment.Penalty)
+		require.NotNil(t, installment.OverdueSince)
+		assert.Equal(t, time.Date(2025, 4, 14, 0, 0, 0, 0, time.UTC), *installment.OverdueSince)
+		require.NotNil(t, installment.PenaltyAccruedTo)
+		assert.Equal(t, charge.ChargeDate, *installment.PenaltyAccruedTo)
+	})
+
+	t.Run("repeated run on the same day charges nothing", func(t *testing.T) {
+		installment := overdueInstallment()
+		calculator.Accrue(penaltyProduct(), &installment, time.Date(2025, 4, 14, 1, 0, 0, 0, time.UTC))
+
+		charge, overdue := calculator.Accrue(penaltyProduct(), &installment, time.Date(2025, 4, 14, 13, 0, 0, 0, time.UTC))
+
+		assert.True(t, overdue)
+		assert.Nil(t, charge)
+		assert.Equal(t, money.FromRupiah(1473), installment.Penalty)
+	})
+
+	t.Run("missed runs are caught up", func(t *testing.T) {
+		installment := overdueInstallment()
+		calculator.Accrue(penaltyProduct(), &installment, time.Date(2025, 4, 14, 1, 0, 0, 0, time.UTC))
+
+		charge, _ := calculator.Accrue(penaltyProduct(), &installment, time.Date(2025, 4, 17, 1, 0, 0, 0, time.UTC))
+
+		require.NotNil(t, charge)
+		assert.Equal(t, 3, charge.Days)
+		assert.Equal(t, money.FromRupiah(1105), charge.Amount)
+		assert.Equal(t, money.FromRupiah(2578), installment.Penalty)
+	})
+
+	t.Run("partial payment lowers the base", func(t *testing.T) {
+		installment := overdueInstallment()
+		installment.PaidAdminFee = money.FromRupiah(10000)
+		installment.PaidInterest = money.FromRupiah(25000)
+		installment.Status = model.InstallmentStatusPartial
+
+		charge, _ := calculator.Accrue(penaltyProduct(), &installment, time.Date(2025, 4, 14, 1, 0, 0, 0, time.UTC))
+
+		require.NotNil(t, charge)
+		assert.Equal(t, money.FromRupiah(333333), charge.BaseAmount)
+		assert.Equal(t, money.FromRupiah(1333), charge.Amount)
+	})
+
+	t.Run("penalty stops at the product cap", func(t *testing.T) {
+		installment := overdueInstallment()
+		installment.Penalty = money.FromRupiah(36000)
+
+		charge, overdue := calculator.Accrue(penaltyProduct(), &installment, time.Date(2025, 5, 20, 1, 0, 0, 0, time.UTC))
+
+		require.True(t, overdue)
+		require.NotNil(t, charge)
+		assert.Equal(t, money.FromRupiah(833), charge.Amount)
+		assert.Equal(t, money.FromRupiah(36833), installment.Penalty)
+
+		charge, overdue = calculator.Accrue(penaltyProduct(), &installment, time.Date(2025, 5, 21, 1, 0, 0, 0, time.UTC))
+
+		assert.True(t, overdue)
+		assert.Nil(t, charge)
+		assert.Equal(t, money.FromRupiah(36833), installment.Penalty)
+	})
+
+	t.Run("product rates above the regulatory maximum are held to it", func(t *testing.T) {
+		installment := overdueInstallment()
+		product := penaltyProduct()
+		product.PenaltyDailyRate = money.MustParseRate("0.01")
+		product.PenaltyCapRate = money.MustParseRate("0.5")
+
+		charge, _ := calculator.Accrue(product, &installment, time.Date(2025, 8, 29, 1, 0, 0, 0, time.UTC))
+
+		require.NotNil(t, charge)
+		assert.Equal(t, money.MustParseRate("0.002"), charge.DailyRate)
+		assert.Equal(t, money.FromRupiah(73666), charge.Amount)
+	})
+
+	t.Run("paid installments are not overdue", func(t *testing.T) {
+		installment := overdueInstallment()
+		paidInFull(&installment)
+
+		charge, overdue := calculator.Accrue(penaltyProduct(), &installment, time.Date(2025, 5, 20, 1, 0, 0, 0, time.UTC))
+
+		assert.Nil(t, charge)
+		assert.False(t, overdue)
+	})
+
+	t.Run("zero grace period", func(t *testing.T) {
+		installment := overdueInstallment()
+		product := penaltyProduct()
+		product.GracePeriodDays = 0
+
+		charge, overdue := calculator.Accrue(product, &installment, time.Date(2025, 4, 11, 0, 0, 0, 0, time.UTC))
+
+		require.True(t, overdue)
+		require.NotNil(t, charge)
+		assert.Equal(t, 1, charge.Days)
+		assert.Equal(t, money.FromRupiah(368), charge.Amount)
+	})
+}
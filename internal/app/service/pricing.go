@@ -0,0 +1,85 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/pkg/loancalc"
+	"github.com/glennprays/xyz-fin/pkg/money"
+)
+
+// ValidateFeeTiers checks that the tiers form a usable fee schedule: a flat
+// fee has exactly one tier starting at zero, a tiered fee starts at zero and
+// every following tier starts at a higher OTR.
+func ValidateFeeTiers(feeType string, tiers []model.AdminFeeTier) error {
+	switch feeType {
+	case model.AdminFeeTypeFlat:
+		if len(tiers) != 1 {
+			return errors.New("a flat admin fee needs exactly one fee tier")
+		}
+	case model.AdminFeeTypeTiered:
+		if len(tiers) < 2 {
+			return errors.New("a tiered admin fee needs at least two fee tiers")
+		}
+	default:
+		return fmt.Errorf("unknown admin_fee_type %q", feeType)
+	}
+
+	if tiers[0].MinOTR != money.Zero {
+		return errors.New("the first fee tier must start at min_otr 0")
+	}
+	for i, tier := range tiers {
+		if tier.FeeAmount < 0 || tier.FeeRate < 0 {
+			return fmt.Errorf("fee tier %d must not be negative", i+1)
+		}
+		if i > 0 && tier.MinOTR <= tiers[i-1].MinOTR {
+			return fmt.Errorf("fee tier %d must start above min_otr %s", i+1, tiers[i-1].MinOTR)
+		}
+	}
+	return nil
+}
+
+// PriceContract applies the rate card to a contract. The admin fee comes from
+// the highest tier whose MinOTR does not exceed the OTR and is rounded half up
+// to whole rupiah. Interest follows the product's interest method at the
+// card's monthly rate. APR and EIR are disclosed on the installments
+// including their admin fee parts.
+func PriceContract(card *model.RateCard, interestMethod string, otr money.Amount, tenor int) (model.Pricing, error) {
+	calculator, err := loancalc.New(interestMethod)
+	if err != nil {
+		return model.Pricing{}, err
+	}
+
+	var tier *model.AdminFeeTier
+	for i := range card.FeeTiers {
+		if card.FeeTiers[i].MinOTR <= otr && (tier == nil || card.FeeTiers[i].MinOTR > tier.MinOTR) {
+			tier = &card.FeeTiers[i]
+		}
+	}
+	if tier == nil {
+		return model.Pricing{}, fmt.Errorf("rate card %d has no fee tier for otr %s", card.ID, otr)
+	}
+
+	adminFee := (tier.FeeAmount + otr.Mul(tier.FeeRate, money.RoundHalfUp)).Round(money.Rupiah, money.RoundHalfUp)
+	plan := calculator.Plan(otr, card.InterestRate, tenor)
+
+	adminFeeParts := loancalc.Split(adminFee, tenor)
+	payments := make([]money.Amount, 0, tenor)
+	for i, period := range plan.Periods {
+		payments = append(payments, period.Payment+adminFeeParts[i])
+	}
+	disclosure := loancalc.Disclose(otr, payments)
+
+	return model.Pricing{
+		RateCardID:      card.ID,
+		RateCardVersion: card.Version,
+		InterestMethod:  calculator.Method(),
+		InterestRate:    card.InterestRate,
+		AdminFee:        adminFee,
+		JumlahBunga:     plan.TotalInterest,
+		APR:             disclosure.APR,
+		EIR:             disclosure.EIR,
+		Plan:            plan,
+	}, nil
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/pkg/loancalc"
+	"github.com/glennprays/xyz-fin/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceContract(t *testing.T) {
+	flat := model.RateCard{
+		ID:           1,
+		Version:      3,
+		InterestRate: money.MustParseRate("0.025"),
+		AdminFeeType: model.AdminFeeTypeFlat,
+		FeeTiers: []model.AdminFeeTier{
+			{MinOTR: money.Zero, FeeRate: money.MustParseRate("0.03")},
+		},
+	}
+	tiered := model.RateCard{
+		ID:           2,
+		Version:      1,
+		InterestRate: money.MustParseRate("0.0175"),
+		AdminFeeType: model.AdminFeeTypeTiered,
+		FeeTiers: []model.AdminFeeTier{
+			{MinOTR: money.Zero, FeeAmount: money.FromRupiah(150000)},
+			{MinOTR: money.FromRupiah(5000000), FeeAmount: money.FromRupiah(250000)},
+			{MinOTR: money.FromRupiah(15000000), FeeAmount: money.FromRupiah(100000), FeeRate: money.MustParseRate("0.01")},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		card             model.RateCard
+		method           string
+		otr              money.Amount
+		tenor            int
+		expectedAdminFee money.Amount
+		expectedInterest money.Amount
+	}{
+		{
+			name:             "flat percentage fee",
+			card:             flat,
+			method:           loancalc.MethodFlat,
+			otr:              money.FromRupiah(1000000),
+			tenor:            3,
+			expectedAdminFee: money.FromRupiah(30000),
+			expectedInterest: money.FromRupiah(75000),
+		},
+		{
+			name:             "flat fee rounds to whole rupiah",
+			card:             flat,
+			method:           loancalc.MethodFlat,
+			otr:              money.FromRupiah(333333),
+			tenor:            1,
+			expectedAdminFee: money.FromRupiah(10000),
+			expectedInterest: money.FromRupiah(8333),
+		},
+		{
+			name:             "lowest tier",
+			card:             tiered,
+			method:           loancalc.MethodFlat,
+			otr:              money.FromRupiah(4999999),
+			tenor:            6,
+			expectedAdminFee: money.FromRupiah(150000),
+			expectedInterest: money.FromRupiah(525000),
+		},
+		{
+			name:             "tier boundary is inclusive",
+			card:             tiered,
+			method:           loancalc.MethodFlat,
+			otr:              money.FromRupiah(5000000),
+			tenor:            3,
+			expectedAdminFee: money.FromRupiah(250000),
+			expectedInterest: money.FromRupiah(262500),
+		},
+		{
+			name:             "top tier combines amount and rate",
+			card:             tiered,
+			method:           loancalc.MethodFlat,
+			otr:              money.FromRupiah(20000000),
+			tenor:            6,
+			expectedAdminFee: money.FromRupiah(300000),
+			expectedInterest: money.FromRupiah(2100000),
+		},
+		{
+			name:             "annuity interest on the declining balance",
+			card:             tiered,
+			method:           loancalc.MethodAnnuity,
+			otr:              money.FromRupiah(20000000),
+			tenor:            6,
+			expectedAdminFee: money.FromRupiah(300000),
+			expectedInterest: money.FromRupiah(1242706),
+		},
+		{
+			name:             "declining balance interest",
+			card:             flat,
+			method:           loancalc.MethodDecliningBalance,
+			otr:              money.FromRupiah(1200000),
+			tenor:            3,
+			expectedAdminFee: money.FromRupiah(36000),
+			expectedInterest: money.FromRupiah(60000),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pricing, err := PriceContract(&tt.card, tt.method, tt.otr, tt.tenor)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.card.ID, pricing.RateCardID)
+			assert.Equal(t, tt.card.Version, pricing.RateCardVersion)
+			assert.Equal(t, tt.card.InterestRate, pricing.InterestRate)
+			assert.Equal(t, tt.expectedAdminFee, pricing.AdminFee)
+			assert.Equal(t, tt.method, pricing.InterestMethod)
+			assert.Equal(t, tt.expectedInterest, pricing.JumlahBunga)
+			assert.Len(t, pricing.Plan.Periods, tt.tenor)
+			assert.Greater(t, pricing.EIR, pricing.APR)
+			assert.Greater(t, pricing.APR, money.Rate(0))
+		})
+	}
+}
+
+func TestPriceContract_NoMatchingTier(t *testing.T) {
+	card := model.RateCard{
+		ID:       9,
+		FeeTiers: []model.AdminFeeTier{{MinOTR: money.FromRupiah(1000000)}},
+	}
+
+	_, err := PriceContract(&card, loancalc.MethodFlat, money.FromRupiah(500000), 3)
+	assert.EqualError(t, err, "rate card 9 has no fee tier for otr 500000.00")
+}
+
+func TestPriceContract_UnknownMethod(t *testing.T) {
+	card := model.RateCard{
+		ID:       9,
+		FeeTiers: []model.AdminFeeTier{{}},
+	}
+
+	_, err := PriceContract(&card, "BALLOON", money.FromRupiah(500000), 3)
+	assert.ErrorIs(t, err, loancalc.ErrUnknownMethod)
+}
+
+func TestValidateFeeTiers(t *testing.T) {
+	tests := []struct {
+		name     string
+		feeType  string
+		tiers    []model.AdminFeeTier
+		expected string
+	}{
+		{
+			name:    "valid flat fee",
+			feeType: model.AdminFeeTypeFlat,
+			tiers:   []model.AdminFeeTier{{FeeAmount: money.FromRupiah(50000)}},
+		},
+		{
+			name:    "valid tiered fee",
+			feeType: model.AdminFeeTypeTiered,
+			tiers: []model.AdminFeeTier{
+				{FeeAmount: money.FromRupiah(50000)},
+				{MinOTR: money.FromRupiah(1000000), FeeRate: money.MustParseRate("0.02")},
+			},
+		},
+		{
+			name:     "unknown fee type",
+			feeType:  "PERCENT",
+			tiers:    []model.AdminFeeTier{{}},
+			expected: `unknown admin_fee_type "PERCENT"`,
+		},
+		{
+			name:     "flat fee with several tiers",
+			feeType:  model.AdminFeeTypeFlat,
+			tiers:    []model.AdminFeeTier{{}, {MinOTR: money.FromRupiah(1)}},
+			expected: "a flat admin fee needs exactly one fee tier",
+		},
+		{
+			name:     "tiered fee with one tier",
+			feeType:  model.AdminFeeTypeTiered,
+			tiers:    []model.AdminFeeTier{{}},
+			expected: "a tiered admin fee needs at least two fee tiers",
+		},
+		{
+			name:     "first tier above zero",
+			feeType:  model.AdminFeeTypeFlat,
+			tiers:    []model.AdminFeeTier{{MinOTR: money.FromRupiah(1000)}},
+			expected: "the first fee tier must start at min_otr 0",
+		},
+		{
+			name:    "tiers out of order",
+			feeType: model.AdminFeeTypeTiered,
+			tiers: []model.AdminFeeTier{
+				{},
+				{MinOTR: money.FromRupiah(5000000)},
+				{MinOTR: money.FromRupiah(1000000)},
+			},
+			expected: "fee tier 3 must start above min_otr 5000000.00",
+		},
+		{
+			name:     "negative fee",
+			feeType:  model.AdminFeeTypeFlat,
+			tiers:    []model.AdminFeeTier{{FeeRate: money.MustParseRate("-0.01")}},
+			expected: "fee tier 1 must not be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateFeeTiers(tt.feeType, tt.tiers)
+			if tt.expected == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/pkg/money"
+)
+
+// CheckProduct validates a financing request against the product's tenors,
+// OTR range and eligibility rules. The returned error is meant for the caller.
+func CheckProduct(product *model.Product, consumer *model.Consumer, otr money.Amount, tenor int, asOf time.Time) error {
+	if !product.IsActive {
+		return fmt.Errorf("product %s is not available", product.Code)
+	}
+	if !slices.Contains(product.Tenors, tenor) {
+		return fmt.Errorf("tenor %d is not offered for product %s", tenor, product.Code)
+	}
+	if otr < product.MinOTR || otr > product.MaxOTR {
+		return fmt.Errorf("otr must be between %s and %s for product %s", product.MinOTR, product.MaxOTR, product.Code)
+	}
+	if product.RequireVerifiedKYC && consumer.KYCStatus != model.KYCStatusVerified {
+		return fmt.Errorf("product %s requires a verified consumer", product.Code)
+	}
+	if ageAt(consumer.TanggalLahir, asOf) < product.MinAge {
+		return fmt.Errorf("product %s requires a minimum age of %d", product.Code, product.MinAge)
+	}
+	if consumer.Gaji < product.MinIncome {
+		return fmt.Errorf("product %s requires a minimum income of %s", product.Code, product.MinIncome)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckProduct(t *testing.T) {
+	asOf := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	product := model.Product{
+		Code:               "white_goods",
+		Tenors:             []int{3, 6},
+		MinOTR:             money.FromRupiah(1000000),
+		MaxOTR:             money.FromRupiah(25000000),
+		MinAge:             21,
+		MinIncome:          money.FromRupiah(3000000),
+		RequireVerifiedKYC: true,
+		IsActive:           true,
+	}
+	consumer := model.Consumer{
+		TanggalLahir: time.Date(1990, 5, 15, 0, 0, 0, 0, time.UTC),
+		Gaji:         money.FromRupiah(5000000),
+		KYCStatus:    model.KYCStatusVerified,
+	}
+
+	tests := []struct {
+		name     string
+		product  func(p *model.Product)
+		consumer func(c *model.Consumer)
+		otr      money.Amount
+		tenor    int
+		expected string
+	}{
+		{
+			name:  "eligible request",
+			otr:   money.FromRupiah(1000000),
+			tenor: 6,
+		},
+		{
+			name:     "inactive product",
+			product:  func(p *model.Product) { p.IsActive = false },
+			otr:      money.FromRupiah(1000000),
+			tenor:    6,
+			expected: "product white_goods is not available",
+		},
+		{
+			name:     "tenor not offered",
+			otr:      money.FromRupiah(1000000),
+			tenor:    1,
+			expected: "tenor 1 is not offered for product white_goods",
+		},
+		{
+			name:     "otr below minimum",
+			otr:      money.MustParse("999999.99"),
+			tenor:    3,
+			expected: "otr must be between 1000000.00 and 25000000.00 for product white_goods",
+		},
+		{
+			name:     "otr above maximum",
+			otr:      money.MustParse("25000000.01"),
+			tenor:    3,
+			expected: "otr must be between 1000000.00 and 25000000.00 for product white_goods",
+		},
+		{
+			name:     "consumer not verified",
+			consumer: func(c *model.Consumer) { c.KYCStatus = model.KYCStatusPending },
+			otr:      money.FromRupiah(1000000),
+			tenor:    3,
+			expected: "product white_goods requires a verified consumer",
+		},
+		{
+			name:     "verification not required",
+			product:  func(p *model.Product) { p.RequireVerifiedKYC = false },
+			consumer: func(c *model.Consumer) { c.KYCStatus = model.KYCStatusPending },
+			otr:      money.FromRupiah(1000000),
+			tenor:    3,
+		},
+		{
+			name:     "consumer too young",
+			consumer: func(c *model.Consumer) { c.TanggalLahir = time.Date(2004, 1, 2, 0, 0, 0, 0, time.UTC) },
+			otr:      money.FromRupiah(1000000),
+			tenor:    3,
+			expected: "product white_goods requires a minimum age of 21",
+		},
+		{
+			name:     "income below minimum",
+			consumer: func(c *model.Consumer) { c.Gaji = money.FromRupiah(2999999) },
+			otr:      money.FromRupiah(1000000),
+			tenor:    3,
+			expected: "product white_goods requires a minimum income of 3000000.00",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, c := product, consumer
+			if tt.product != nil {
+				tt.product(&p)
+			}
+			if tt.consumer != nil {
+				tt.consumer(&c)
+			}
+
+			err := CheckProduct(&p, &c, tt.otr, tt.tenor, asOf)
+			if tt.expected == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/internal/app/repository"
+	"github.com/glennprays/xyz-fin/internal/app/service"
+)
+
+// resolvePrincipal looks up the consumer behind a consumer principal so that
+// ownership can be checked against their NIK. It also returns the consumer,
+// which is nil for staff and for a token whose consumer no longer exists.
+func resolvePrincipal(ctx context.Context, consumerRepo repository.ConsumerRepository, principal model.Principal) (model.Principal, *model.Consumer, error) {
+	if principal.IsStaff() {
+		return principal, nil, nil
+	}
+
+	consumer, err := consumerRepo.FindByPhoneNumber(ctx, principal.PhoneNumber)
+	if err != nil {
+		appErr := errors.New("failed to find consumer")
+		return principal, nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if consumer != nil {
+		principal.ConsumerNIK = consumer.NIK
+	}
+	return principal, consumer, nil
+}
+
+// consumerPrincipal resolves the consumer logged in with phoneNumber.
+func consumerPrincipal(ctx context.Context, consumerRepo repository.ConsumerRepository, phoneNumber string) (model.Principal, *model.Consumer, error) {
+	return resolvePrincipal(ctx, consumerRepo, model.Principal{PhoneNumber: phoneNumber})
+}
+
+// authorize checks principal against the access policy. A consumer acting on
+// a resource of another consumer is told the resource does not exist, so
+// resources cannot be probed; notFound is the message of that error.
+func authorize(principal model.Principal, action string, resource service.Resource, notFound string) error {
+	err := service.Authorize(principal, action, resource)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, service.ErrNotOwner):
+		return model.NewError(model.ErrNotFound, errors.New(notFound))
+	default:
+		return model.NewError(model.ErrForbidden, err)
+	}
+}
@@ -2,46 +2,61 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/glennprays/xyz-fin/internal/app/model"
 	"github.com/glennprays/xyz-fin/internal/app/repository"
+	"github.com/glennprays/xyz-fin/internal/app/service"
 )
 
 type ConsumerLimitUsecase interface {
-	GetLimitsByNIK(ctx context.Context, phoneNumber string, nik string) ([]model.ConsumerLimitResponse, error)
+	GetLimitsByNIK(ctx context.Context, phoneNumber string, nik string, productCode string) ([]model.ConsumerLimitResponse, error)
+	CalculateLimits(ctx context.Context, principal model.Principal, nik string, apply bool) (*model.LimitCalculationResponse, error)
+	GetLimitVersions(ctx context.Context, principal model.Principal, nik string, tenor int) ([]model.ConsumerLimitVersion, error)
+	GetLimitAsOf(ctx context.Context, principal model.Principal, nik string, tenor int, asOf time.Time) (*model.ConsumerLimitVersion, error)
+	GetLimitAtContract(ctx context.Context, principal model.Principal, nomorKontrak string) (*model.ContractLimitResponse, error)
 }
 
 type consumerLimitUsecase struct {
+	db                *sql.DB
 	consumerRepo      repository.ConsumerRepository
 	consumerLimitRepo repository.ConsumerLimitRepository
+	transactionRepo   repository.TransactionRepository
+	limitHoldRepo     repository.LimitHoldRepository
+	limitEngine       service.LimitEngine
+	limitPolicies     service.LimitPolicyResolver
 }
 
 func NewConsumerLimitUsecase(
+	db *sql.DB,
 	consumerRepo repository.ConsumerRepository,
 	consumerLimitRepo repository.ConsumerLimitRepository,
+	transactionRepo repository.TransactionRepository,
+	limitHoldRepo repository.LimitHoldRepository,
+	limitEngine service.LimitEngine,
+	limitPolicies service.LimitPolicyResolver,
 ) ConsumerLimitUsecase {
 	return &consumerLimitUsecase{
+		db:                db,
 		consumerRepo:      consumerRepo,
 		consumerLimitRepo: consumerLimitRepo,
+		transactionRepo:   transactionRepo,
+		limitHoldRepo:     limitHoldRepo,
+		limitEngine:       limitEngine,
+		limitPolicies:     limitPolicies,
 	}
 }
 
-func (u *consumerLimitUsecase) GetLimitsByNIK(ctx context.Context, phoneNumber string, nik string) ([]model.ConsumerLimitResponse, error) {
-	consumer, err := u.consumerRepo.FindByPhoneNumber(ctx, phoneNumber)
+func (u *consumerLimitUsecase) GetLimitsByNIK(ctx context.Context, phoneNumber string, nik string, productCode string) ([]model.ConsumerLimitResponse, error) {
+	principal, _, err := consumerPrincipal(ctx, u.consumerRepo, phoneNumber)
 	if err != nil {
-		appErr := errors.New("consumer not found")
-		return nil, model.NewError(model.ErrNotFound, appErr)
-	}
-
-	if consumer == nil {
-		appErr := errors.New("consumer not found")
-		return nil, model.NewError(model.ErrNotFound, appErr)
+		return nil, err
 	}
 
-	if consumer.NIK != nik {
-		appErr := errors.New("nik does not match")
-		return nil, model.NewError(model.ErrUnauthorized, appErr)
+	if err := authorize(principal, service.ActionViewLimits, service.Resource{OwnerNIK: nik}, "consumer limits not found"); err != nil {
+		return nil, err
 	}
 
 	limits, err := u.consumerLimitRepo.FindByNIK(ctx, nik)
@@ -50,13 +65,165 @@ func (u *consumerLimitUsecase) GetLimitsByNIK(ctx context.Context, phoneNumber s
 		return nil, model.NewError(model.ErrInternalFailure, appErr)
 	}
 
+	usages, err := loadLimitUsage(ctx, nil, u.transactionRepo, u.limitHoldRepo, nik)
+	if err != nil {
+		appErr := errors.New("failed to get limit usage")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	policy := u.limitPolicies.Resolve(productCode)
+
 	var limitResponses []model.ConsumerLimitResponse
 	for _, limit := range limits {
+		utilization := policy.Calculate(limit, limits, usages)
 		limitResponses = append(limitResponses, model.ConsumerLimitResponse{
-			LimitAmount: limit.LimitAmount,
-			Tenor:       limit.Tenor,
+			LimitAmount:     limit.LimitAmount,
+			Tenor:           limit.Tenor,
+			UsedAmount:      utilization.Used,
+			PendingAmount:   utilization.Pending,
+			HeldAmount:      utilization.Held,
+			AvailableAmount: utilization.Available,
 		})
 	}
 
 	return limitResponses, nil
 }
+
+// CalculateLimits runs the limit engine for a consumer. Applying the result
+// writes the limits without a second approval, so only staff allowed to apply
+// limits may do it; everyone else can only run it as a dry run and submit a
+// limit change request.
+func (u *consumerLimitUsecase) CalculateLimits(ctx context.Context, principal model.Principal, nik string, apply bool) (*model.LimitCalculationResponse, error) {
+	if err := authorize(principal, service.ActionCalculateLimits, service.Resource{OwnerNIK: nik}, "consumer not found"); err != nil {
+		return nil, err
+	}
+	if apply {
+		if err := authorize(principal, service.ActionApplyLimits, service.Resource{OwnerNIK: nik}, "consumer not found"); err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		appErr := errors.New("failed to begin transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	defer tx.Rollback()
+
+	consumer, err := u.consumerRepo.FindAndLockByNIK(ctx, tx, nik)
+	if err != nil {
+		appErr := errors.New("failed to find consumer")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if consumer == nil {
+		appErr := errors.New("consumer not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+
+	obligations, err := u.transactionRepo.GetActiveMonthlyObligationByNIK(ctx, tx, nik)
+	if err != nil {
+		appErr := errors.New("failed to get existing obligations")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	calculations := u.limitEngine.Calculate(service.LimitEngineInput{
+		Consumer:           consumer,
+		MonthlyObligations: obligations,
+		AsOf:               time.Now(),
+	})
+
+	response := &model.LimitCalculationResponse{
+		ConsumerNIK: nik,
+		Applied:     apply,
+		Limits:      calculations,
+	}
+	if !apply {
+		return response, nil
+	}
+
+	for _, calculation := range calculations {
+		err = u.consumerLimitRepo.Upsert(ctx, tx, &model.ConsumerLimit{
+			ConsumerNIK: calculation.ConsumerNIK,
+			Tenor:       calculation.Tenor,
+			LimitAmount: calculation.LimitAmount,
+		}, model.LimitSourceEngine, "")
+		if err != nil {
+			appErr := errors.New("failed to save consumer limits")
+			return nil, model.NewError(model.ErrInternalFailure, appErr)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		appErr := errors.New("failed to commit transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	return response, nil
+}
+
+func (u *consumerLimitUsecase) GetLimitVersions(ctx context.Context, principal model.Principal, nik string, tenor int) ([]model.ConsumerLimitVersion, error) {
+	if err := authorize(principal, service.ActionViewLimits, service.Resource{OwnerNIK: nik}, "consumer limits not found"); err != nil {
+		return nil, err
+	}
+
+	versions, err := u.consumerLimitRepo.FindVersions(ctx, nik, tenor)
+	if err != nil {
+		appErr := errors.New("failed to find consumer limit versions")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	return versions, nil
+}
+
+func (u *consumerLimitUsecase) GetLimitAsOf(ctx context.Context, principal model.Principal, nik string, tenor int, asOf time.Time) (*model.ConsumerLimitVersion, error) {
+	if err := authorize(principal, service.ActionViewLimits, service.Resource{OwnerNIK: nik}, "consumer limits not found"); err != nil {
+		return nil, err
+	}
+
+	version, err := u.consumerLimitRepo.FindAsOf(ctx, nik, tenor, asOf)
+	if err != nil {
+		appErr := errors.New("failed to find consumer limit version")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if version == nil {
+		appErr := errors.New("no consumer limit was in effect at the given time")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+
+	return version, nil
+}
+
+func (u *consumerLimitUsecase) GetLimitAtContract(ctx context.Context, principal model.Principal, nomorKontrak string) (*model.ContractLimitResponse, error) {
+	if err := checkNomorKontrak(nomorKontrak); err != nil {
+		return nil, err
+	}
+
+	transaction, err := u.transactionRepo.FindByNomorKontrak(ctx, nomorKontrak)
+	if err != nil {
+		appErr := errors.New("failed to find transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if transaction == nil {
+		appErr := errors.New("transaction not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+	if err := authorize(principal, service.ActionViewLimits, contractResource(transaction), "transaction not found"); err != nil {
+		return nil, err
+	}
+
+	version, err := u.consumerLimitRepo.FindAsOf(ctx, transaction.ConsumerNIK, transaction.JumlahCicilan, transaction.CreatedAt)
+	if err != nil {
+		appErr := errors.New("failed to find consumer limit version")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if version == nil {
+		appErr := errors.New("no consumer limit was in effect when the contract was opened")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+
+	return &model.ContractLimitResponse{
+		NomorKontrak:      transaction.NomorKontrak,
+		ContractCreatedAt: transaction.CreatedAt,
+		Limit:             version,
+	}, nil
+}
@@ -4,16 +4,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/glennprays/xyz-fin/internal/app/model"
 	"github.com/glennprays/xyz-fin/internal/app/repository"
+	"github.com/glennprays/xyz-fin/internal/app/service"
 	"github.com/glennprays/xyz-fin/pkg/auth"
 	"github.com/glennprays/xyz-fin/pkg/hasher"
+	"github.com/glennprays/xyz-fin/pkg/pagination"
 )
 
+const consumerCursorTimeLayout = "2006-01-02T15:04:05.999999"
+
 type ConsumerUsecase interface {
 	Login(ctx context.Context, phoneNumber string, password string) (*model.AuthResponse, error)
 	GetByNIK(ctx context.Context, phoneNumber string, nik string) (*model.Consumer, error)
+	Search(ctx context.Context, req *model.ConsumerSearchRequest) (*model.ConsumerSearchResponse, error)
 }
 
 type consumerUsecase struct {
@@ -55,20 +61,92 @@ func (u *consumerUsecase) Login(ctx context.Context, phoneNumber string, passwor
 }
 
 func (u *consumerUsecase) GetByNIK(ctx context.Context, phoneNumber string, nik string) (*model.Consumer, error) {
-	consumer, err := u.repo.FindByNIK(ctx, nik)
+	principal, consumer, err := consumerPrincipal(ctx, u.repo, phoneNumber)
 	if err != nil {
-		appErr := errors.New("failed to find consumer")
-		return nil, model.NewError(model.ErrInternalFailure, appErr)
-	}
-	if consumer == nil {
-		appErr := errors.New("consumer not found")
-		return nil, model.NewError(model.ErrNotFound, appErr)
+		return nil, err
 	}
 
-	if consumer.PhoneNumber != phoneNumber {
-		appErr := errors.New("unauthorized access")
-		return nil, model.NewError(model.ErrUnauthorized, appErr)
+	if err := authorize(principal, service.ActionViewConsumer, service.Resource{OwnerNIK: nik}, "consumer not found"); err != nil {
+		return nil, err
 	}
 
 	return consumer, nil
 }
+
+func (u *consumerUsecase) Search(ctx context.Context, req *model.ConsumerSearchRequest) (*model.ConsumerSearchResponse, error) {
+	filter := model.ConsumerSearchFilter{
+		NamePrefix:        req.Name,
+		PhoneNumber:       req.PhoneNumber,
+		NIK:               req.NIK,
+		KYCStatus:         req.KYCStatus,
+		CreatedFrom:       req.CreatedFrom,
+		HasActiveContract: req.HasActiveContract,
+		SortBy:            req.SortBy,
+		SortOrder:         req.SortOrder,
+		Limit:             pagination.NormalizeLimit(req.Limit),
+	}
+
+	if req.CreatedTo != nil {
+		createdTo := req.CreatedTo.AddDate(0, 0, 1)
+		filter.CreatedTo = &createdTo
+	}
+
+	switch filter.SortBy {
+	case "":
+		filter.SortBy = model.ConsumerSortByCreatedAt
+	case model.ConsumerSortByCreatedAt, model.ConsumerSortByFullName:
+	default:
+		appErr := fmt.Errorf("unsupported sort_by %q", req.SortBy)
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+
+	switch filter.SortOrder {
+	case "":
+		filter.SortOrder = model.SortOrderDesc
+	case model.SortOrderAsc, model.SortOrderDesc:
+	default:
+		appErr := fmt.Errorf("unsupported sort_order %q", req.SortOrder)
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+
+	switch filter.KYCStatus {
+	case "", model.KYCStatusPending, model.KYCStatusVerified, model.KYCStatusRejected:
+	default:
+		appErr := fmt.Errorf("unsupported kyc_status %q", req.KYCStatus)
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+
+	cursor, err := pagination.Decode(req.Cursor)
+	if err != nil {
+		return nil, model.NewError(model.ErrBadRequest, err)
+	}
+	if cursor != nil && filter.SortBy == model.ConsumerSortByCreatedAt {
+		if _, err := time.Parse(consumerCursorTimeLayout, cursor.SortValue); err != nil {
+			return nil, model.NewError(model.ErrBadRequest, pagination.ErrInvalidCursor)
+		}
+	}
+	filter.Cursor = cursor
+
+	// Fetch one extra row to know whether another page exists.
+	requested := filter.Limit
+	filter.Limit++
+
+	consumers, err := u.repo.Search(ctx, filter)
+	if err != nil {
+		appErr := errors.New("failed to search consumers")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	response := &model.ConsumerSearchResponse{Data: consumers}
+	if len(consumers) > requested {
+		response.Data = consumers[:requested]
+		last := response.Data[requested-1]
+		next := pagination.Cursor{Key: last.NIK, SortValue: last.CreatedAt.Format(consumerCursorTimeLayout)}
+		if filter.SortBy == model.ConsumerSortByFullName {
+			next.SortValue = last.FullName
+		}
+		response.NextCursor = pagination.Encode(next)
+	}
+
+	return response, nil
+}
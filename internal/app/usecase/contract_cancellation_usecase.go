@@ -0,0 +1,242 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/internal/app/repository"
+	"github.com/glennprays/xyz-fin/internal/app/service"
+)
+
+type ContractCancellationUsecase interface {
+	Cancel(ctx context.Context, phoneNumber string, nomorKontrak string, req *model.ContractCancellationRequest) (*model.ContractCancellation, error)
+	CancelByStaff(ctx context.Context, staffUsername string, nomorKontrak string, req *model.ContractCancellationRequest) (*model.ContractCancellation, error)
+}
+
+type contractCancellationUsecase struct {
+	db                       *sql.DB
+	consumerRepo             repository.ConsumerRepository
+	transactionRepo          repository.TransactionRepository
+	installmentRepo          repository.InstallmentRepository
+	cancellationRepo         repository.ContractCancellationRepository
+	transitionRepo           repository.TransactionStatusTransitionRepository
+	merchantNotificationRepo repository.MerchantNotificationRepository
+	coolingOff               time.Duration
+}
+
+func NewContractCancellationUsecase(
+	db *sql.DB,
+	consumerRepo repository.ConsumerRepository,
+	transactionRepo repository.TransactionRepository,
+	installmentRepo repository.InstallmentRepository,
+	cancellationRepo repository.ContractCancellationRepository,
+	transitionRepo repository.TransactionStatusTransitionRepository,
+	merchantNotificationRepo repository.MerchantNotificationRepository,
+	coolingOff time.Duration,
+) ContractCancellationUsecase {
+	return &contractCancellationUsecase{
+		db:                       db,
+		consumerRepo:             consumerRepo,
+		transactionRepo:          transactionRepo,
+		installmentRepo:          installmentRepo,
+		cancellationRepo:         cancellationRepo,
+		transitionRepo:           transitionRepo,
+		merchantNotificationRepo: merchantNotificationRepo,
+		coolingOff:               coolingOff,
+	}
+}
+
+// Cancel lets a consumer withdraw from a contract within the cooling-off
+// period, as long as it has not been disbursed. The installments are closed
+// as CANCELLED so neither the admin fee nor the interest is owed, the
+// contract moves to CANCELLED, which takes it off the consumer's limit, and
+// the merchant is notified, all in one transaction.
+func (u *contractCancellationUsecase) Cancel(ctx context.Context, phoneNumber string, nomorKontrak string, req *model.ContractCancellationRequest) (*model.ContractCancellation, error) {
+	if err := checkNomorKontrak(nomorKontrak); err != nil {
+		return nil, err
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		appErr := errors.New("reason is required")
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+
+	principal, _, err := consumerPrincipal(ctx, u.consumerRepo, phoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction, err := u.transactionRepo.FindByNomorKontrak(ctx, nomorKontrak)
+	if err != nil {
+		appErr := errors.New("failed to find transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if transaction == nil {
+		appErr := errors.New("transaction not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+	if err := authorize(principal, service.ActionCancelContract, contractResource(transaction), "transaction not found"); err != nil {
+		return nil, err
+	}
+
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		appErr := errors.New("failed to begin transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	defer tx.Rollback()
+
+	// Lock the consumer before the contract, the same order every limit
+	// writer uses.
+	if _, err := u.consumerRepo.FindAndLockByNIK(ctx, tx, transaction.ConsumerNIK); err != nil {
+		appErr := errors.New("failed to find consumer")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	transaction, err = u.transactionRepo.FindAndLockByNomorKontrak(ctx, tx, nomorKontrak)
+	if err != nil {
+		appErr := errors.New("failed to find transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if transaction == nil {
+		appErr := errors.New("transaction not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+
+	now := time.Now()
+	if err := service.CheckCancellable(*transaction, now, u.coolingOff); err != nil {
+		return nil, model.NewError(model.ErrConflict, err)
+	}
+
+	cancellation, err := u.cancel(ctx, tx, transaction, reason, transaction.ConsumerNIK, "cancelled by consumer: "+reason, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		appErr := errors.New("failed to commit transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	return cancellation, nil
+}
+
+// CancelByStaff lets an admin cancel a contract that has not been disbursed
+// yet, for example on a merchant's request, regardless of the cooling-off
+// period. The effects are the same as a consumer cancellation.
+func (u *contractCancellationUsecase) CancelByStaff(ctx context.Context, staffUsername string, nomorKontrak string, req *model.ContractCancellationRequest) (*model.ContractCancellation, error) {
+	if err := checkNomorKontrak(nomorKontrak); err != nil {
+		return nil, err
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		appErr := errors.New("reason is required")
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+
+	transaction, err := u.transactionRepo.FindByNomorKontrak(ctx, nomorKontrak)
+	if err != nil {
+		appErr := errors.New("failed to find transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if transaction == nil {
+		appErr := errors.New("transaction not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		appErr := errors.New("failed to begin transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	defer tx.Rollback()
+
+	if _, err := u.consumerRepo.FindAndLockByNIK(ctx, tx, transaction.ConsumerNIK); err != nil {
+		appErr := errors.New("failed to find consumer")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	transaction, err = u.transactionRepo.FindAndLockByNomorKontrak(ctx, tx, nomorKontrak)
+	if err != nil {
+		appErr := errors.New("failed to find transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if transaction == nil {
+		appErr := errors.New("transaction not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+
+	if err := service.CheckNotDisbursed(*transaction); err != nil {
+		return nil, model.NewError(model.ErrConflict, err)
+	}
+
+	cancellation, err := u.cancel(ctx, tx, transaction, reason, staffUsername, "cancelled by staff: "+reason, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		appErr := errors.New("failed to commit transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	return cancellation, nil
+}
+
+// cancel closes the installments of a contract locked in tx, records the
+// cancellation, moves the contract to CANCELLED and queues the merchant
+// notification.
+func (u *contractCancellationUsecase) cancel(ctx context.Context, tx *sql.Tx, transaction *model.Transaction, reason string, cancelledBy string, transitionReason string, now time.Time) (*model.ContractCancellation, error) {
+	nomorKontrak := transaction.NomorKontrak
+	if err := u.installmentRepo.CancelOutstanding(ctx, tx, nomorKontrak); err != nil {
+		appErr := errors.New("failed to cancel installments")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	if err := u.transactionRepo.ClearOutstanding(ctx, tx, nomorKontrak); err != nil {
+		appErr := errors.New("failed to update transaction balances")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	cancellation := &model.ContractCancellation{
+		NomorKontrak:     nomorKontrak,
+		Reason:           reason,
+		ReversedAdminFee: transaction.AdminFee,
+		ReversedInterest: transaction.JumlahBunga,
+		ReleasedLimit:    transaction.OTR,
+		CancelledBy:      cancelledBy,
+		CancelledAt:      now,
+	}
+	if err := u.cancellationRepo.Create(ctx, tx, cancellation); err != nil {
+		appErr := errors.New("failed to save cancellation")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	_, err := transitionContract(ctx, tx, u.transactionRepo, u.transitionRepo, transaction, model.TransactionStatusCancelled, transitionReason, cancelledBy)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(cancellation)
+	if err != nil {
+		appErr := errors.New("failed to encode merchant notification")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	notification := &model.MerchantNotification{
+		NomorKontrak: nomorKontrak,
+		MerchantID:   transaction.MerchantID,
+		Event:        model.MerchantEventContractCancelled,
+		Payload:      payload,
+	}
+	if err := u.merchantNotificationRepo.Create(ctx, tx, notification); err != nil {
+		appErr := errors.New("failed to queue merchant notification")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	return cancellation, nil
+}
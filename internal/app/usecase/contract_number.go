@@ -0,0 +1,16 @@
+package usecase
+
+import (
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/internal/app/service"
+)
+
+// checkNomorKontrak rejects a contract number with a bad layout or check
+// character before it is looked up, so a mistyped number is reported as such
+// instead of as a missing contract.
+func checkNomorKontrak(nomorKontrak string) error {
+	if err := service.ValidateContractNumber(nomorKontrak); err != nil {
+		return model.NewError(model.ErrBadRequest, err)
+	}
+	return nil
+}
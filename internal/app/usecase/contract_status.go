@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/internal/app/repository"
+	"github.com/glennprays/xyz-fin/internal/app/service"
+)
+
+// transitionContract moves a contract locked in tx to a new status and
+// records the change in its status log. Transitions the state machine does
+// not allow are rejected as conflicts.
+func transitionContract(
+	ctx context.Context,
+	tx *sql.Tx,
+	transactionRepo repository.TransactionRepository,
+	transitionRepo repository.TransactionStatusTransitionRepository,
+	transaction *model.Transaction,
+	to string,
+	reason string,
+	changedBy string,
+) (*model.TransactionStatusTransition, error) {
+	if err := service.ValidateContractTransition(transaction.Status, to); err != nil {
+		return nil, model.NewError(model.ErrConflict, err)
+	}
+
+	if err := transactionRepo.UpdateStatus(ctx, tx, transaction.NomorKontrak, to); err != nil {
+		appErr := errors.New("failed to update transaction status")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	from := transaction.Status
+	transition := &model.TransactionStatusTransition{
+		NomorKontrak: transaction.NomorKontrak,
+		FromStatus:   &from,
+		ToStatus:     to,
+		Reason:       reason,
+		ChangedBy:    changedBy,
+	}
+	if err := transitionRepo.Create(ctx, tx, transition); err != nil {
+		appErr := errors.New("failed to record status transition")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	transaction.Status = to
+	return transition, nil
+}
@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/internal/app/repository"
+	"github.com/glennprays/xyz-fin/internal/app/service"
+)
+
+type ContractStatusUsecase interface {
+	Transition(ctx context.Context, staffUsername string, nomorKontrak string, req *model.TransactionStatusRequest) (*model.TransactionStatusTransition, error)
+	ListTransitions(ctx context.Context, nomorKontrak string) ([]model.TransactionStatusTransition, error)
+}
+
+type contractStatusUsecase struct {
+	db              *sql.DB
+	transactionRepo repository.TransactionRepository
+	transitionRepo  repository.TransactionStatusTransitionRepository
+}
+
+func NewContractStatusUsecase(
+	db *sql.DB,
+	transactionRepo repository.TransactionRepository,
+	transitionRepo repository.TransactionStatusTransitionRepository,
+) ContractStatusUsecase {
+	return &contractStatusUsecase{
+		db:              db,
+		transactionRepo: transactionRepo,
+		transitionRepo:  transitionRepo,
+	}
+}
+
+// Transition lets staff move a contract through the lifecycle, for example
+// to record the disbursement or a default. Paying off is left to the payment
+// flows, which know when nothing is outstanding, and cancelling to the
+// cancellation flow, which closes the installments and notifies the merchant.
+// Writing off and restructuring are rejected until there are flows that close
+// the schedule and record the write-off or the replacement contract; moving
+// only the status would take the contract off the limit while its
+// installments are still open.
+func (u *contractStatusUsecase) Transition(ctx context.Context, staffUsername string, nomorKontrak string, req *model.TransactionStatusRequest) (*model.TransactionStatusTransition, error) {
+	if err := checkNomorKontrak(nomorKontrak); err != nil {
+		return nil, err
+	}
+	if !service.IsContractStatus(req.Status) {
+		appErr := errors.New("unknown contract status")
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+	switch req.Status {
+	case model.TransactionStatusPaidOff:
+		appErr := errors.New("contracts are paid off by posting payments")
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	case model.TransactionStatusCancelled:
+		appErr := errors.New("contracts are cancelled through the cancel endpoint")
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	case model.TransactionStatusWrittenOff, model.TransactionStatusRestructured:
+		appErr := fmt.Errorf("moving a contract to %s is not supported yet", req.Status)
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		appErr := errors.New("reason is required")
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		appErr := errors.New("failed to begin transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	defer tx.Rollback()
+
+	transaction, err := u.transactionRepo.FindAndLockByNomorKontrak(ctx, tx, nomorKontrak)
+	if err != nil {
+		appErr := errors.New("failed to find transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if transaction == nil {
+		appErr := errors.New("transaction not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+
+	transition, err := transitionContract(ctx, tx, u.transactionRepo, u.transitionRepo, transaction, req.Status, req.Reason, staffUsername)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		appErr := errors.New("failed to commit transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	return transition, nil
+}
+
+func (u *contractStatusUsecase) ListTransitions(ctx context.Context, nomorKontrak string) ([]model.TransactionStatusTransition, error) {
+	if err := checkNomorKontrak(nomorKontrak); err != nil {
+		return nil, err
+	}
+
+	transaction, err := u.transactionRepo.FindByNomorKontrak(ctx, nomorKontrak)
+	if err != nil {
+		appErr := errors.New("failed to find transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if transaction == nil {
+		appErr := errors.New("transaction not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+
+	transitions, err := u.transitionRepo.FindByNomorKontrak(ctx, nomorKontrak)
+	if err != nil {
+		appErr := errors.New("failed to find status transitions")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	return transitions, nil
+}
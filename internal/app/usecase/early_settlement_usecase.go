@@ -0,0 +1,232 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/internal/app/repository"
+	"github.com/glennprays/xyz-fin/internal/app/service"
+)
+
+type EarlySettlementUsecase interface {
+	Quote(ctx context.Context, nomorKontrak string) (*model.EarlySettlementQuote, error)
+	QuoteForConsumer(ctx context.Context, phoneNumber string, nomorKontrak string) (*model.EarlySettlementQuote, error)
+	Execute(ctx context.Context, staffUsername string, nomorKontrak string, req *model.EarlySettlementRequest) (*model.EarlySettlement, error)
+}
+
+type earlySettlementUsecase struct {
+	db                  *sql.DB
+	consumerRepo        repository.ConsumerRepository
+	transactionRepo     repository.TransactionRepository
+	installmentRepo     repository.InstallmentRepository
+	paymentRepo         repository.PaymentRepository
+	earlySettlementRepo repository.EarlySettlementRepository
+	transitionRepo      repository.TransactionStatusTransitionRepository
+	calculator          *service.EarlySettlementCalculator
+}
+
+func NewEarlySettlementUsecase(
+	db *sql.DB,
+	consumerRepo repository.ConsumerRepository,
+	transactionRepo repository.TransactionRepository,
+	installmentRepo repository.InstallmentRepository,
+	paymentRepo repository.PaymentRepository,
+	earlySettlementRepo repository.EarlySettlementRepository,
+	transitionRepo repository.TransactionStatusTransitionRepository,
+	calculator *service.EarlySettlementCalculator,
+) EarlySettlementUsecase {
+	return &earlySettlementUsecase{
+		db:                  db,
+		consumerRepo:        consumerRepo,
+		transactionRepo:     transactionRepo,
+		installmentRepo:     installmentRepo,
+		paymentRepo:         paymentRepo,
+		earlySettlementRepo: earlySettlementRepo,
+		transitionRepo:      transitionRepo,
+		calculator:          calculator,
+	}
+}
+
+func (u *earlySettlementUsecase) Quote(ctx context.Context, nomorKontrak string) (*model.EarlySettlementQuote, error) {
+	if err := checkNomorKontrak(nomorKontrak); err != nil {
+		return nil, err
+	}
+
+	transaction, err := u.transactionRepo.FindByNomorKontrak(ctx, nomorKontrak)
+	if err != nil {
+		appErr := errors.New("failed to find transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if transaction == nil {
+		appErr := errors.New("transaction not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+
+	return u.quote(ctx, transaction)
+}
+
+func (u *earlySettlementUsecase) QuoteForConsumer(ctx context.Context, phoneNumber string, nomorKontrak string) (*model.EarlySettlementQuote, error) {
+	if err := checkNomorKontrak(nomorKontrak); err != nil {
+		return nil, err
+	}
+
+	principal, _, err := consumerPrincipal(ctx, u.consumerRepo, phoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction, err := u.transactionRepo.FindByNomorKontrak(ctx, nomorKontrak)
+	if err != nil {
+		appErr := errors.New("failed to find transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if transaction == nil {
+		appErr := errors.New("transaction not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+	if err := authorize(principal, service.ActionViewContract, contractResource(transaction), "transaction not found"); err != nil {
+		return nil, err
+	}
+
+	return u.quote(ctx, transaction)
+}
+
+func (u *earlySettlementUsecase) quote(ctx context.Context, transaction *model.Transaction) (*model.EarlySettlementQuote, error) {
+	if err := checkSettleable(transaction); err != nil {
+		return nil, err
+	}
+
+	installments, err := u.installmentRepo.FindByNomorKontrak(ctx, transaction.NomorKontrak)
+	if err != nil {
+		appErr := errors.New("failed to find installment schedule")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	quote := u.calculator.Quote(*transaction, installments, time.Now())
+	return &quote, nil
+}
+
+// Execute accepts the payoff of a contract. The amount must match today's
+// quote exactly; the open installments are closed as SETTLED, the payoff is
+// recorded as a payment under its reference, the balances are cleared and the
+// contract is paid off in the same transaction.
+func (u *earlySettlementUsecase) Execute(ctx context.Context, staffUsername string, nomorKontrak string, req *model.EarlySettlementRequest) (*model.EarlySettlement, error) {
+	if err := checkNomorKontrak(nomorKontrak); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.Reference) == "" {
+		appErr := errors.New("reference is required")
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		appErr := errors.New("failed to begin transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	defer tx.Rollback()
+
+	transaction, err := u.transactionRepo.FindAndLockByNomorKontrak(ctx, tx, nomorKontrak)
+	if err != nil {
+		appErr := errors.New("failed to find transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if transaction == nil {
+		appErr := errors.New("transaction not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+	if err := checkSettleable(transaction); err != nil {
+		return nil, err
+	}
+
+	existing, err := u.paymentRepo.FindByReference(ctx, tx, nomorKontrak, req.Reference)
+	if err != nil {
+		appErr := errors.New("failed to find payment")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if existing != nil {
+		appErr := fmt.Errorf("payment %s was already posted", req.Reference)
+		return nil, model.NewError(model.ErrConflict, appErr)
+	}
+
+	installments, err := u.installmentRepo.FindAndLockByNomorKontrak(ctx, tx, nomorKontrak)
+	if err != nil {
+		appErr := errors.New("failed to find installment schedule")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	now := time.Now()
+	quote := u.calculator.Quote(*transaction, installments, now)
+	if quote.TotalPayoff <= 0 {
+		appErr := errors.New("contract has nothing left to pay")
+		return nil, model.NewError(model.ErrConflict, appErr)
+	}
+	if req.Amount != quote.TotalPayoff {
+		appErr := fmt.Errorf("amount must equal the payoff of %s", quote.TotalPayoff)
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+
+	if err := u.installmentRepo.SettleOutstanding(ctx, tx, nomorKontrak, now); err != nil {
+		appErr := errors.New("failed to settle installments")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	settlement := &model.EarlySettlement{
+		EarlySettlementQuote: quote,
+		Reference:            req.Reference,
+		SettledBy:            staffUsername,
+		SettledAt:            now,
+	}
+	if err := u.earlySettlementRepo.Create(ctx, tx, settlement); err != nil {
+		appErr := errors.New("failed to save early settlement")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	// The payoff is recorded with the contract's other payments so its
+	// history and total_paid agree. It has no allocation lines: the rebate
+	// and the termination fee do not map onto installment components, and
+	// its breakdown is kept on the early settlement instead.
+	payment := &model.Payment{
+		NomorKontrak:    nomorKontrak,
+		Reference:       req.Reference,
+		Amount:          quote.TotalPayoff,
+		AllocatedAmount: quote.TotalPayoff,
+		PaidAt:          now,
+		PostedBy:        staffUsername,
+	}
+	if err := u.paymentRepo.Create(ctx, tx, payment); err != nil {
+		appErr := errors.New("failed to save payment")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	if err := u.transactionRepo.ApplyPayment(ctx, tx, nomorKontrak, quote.RemainingPrincipal, quote.TotalPayoff); err != nil {
+		appErr := errors.New("failed to update transaction balances")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	reason := fmt.Sprintf("settled early by payment %s", req.Reference)
+	_, err = transitionContract(ctx, tx, u.transactionRepo, u.transitionRepo, transaction, model.TransactionStatusPaidOff, reason, staffUsername)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		appErr := errors.New("failed to commit transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	return settlement, nil
+}
+
+func checkSettleable(transaction *model.Transaction) error {
+	if transaction.Status != model.TransactionStatusActive && transaction.Status != model.TransactionStatusDefaulted {
+		appErr := fmt.Errorf("a %s contract cannot be settled early", transaction.Status)
+		return model.NewError(model.ErrConflict, appErr)
+	}
+	return nil
+}
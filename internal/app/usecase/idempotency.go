@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/internal/app/repository"
+)
+
+const maxIdempotencyKeyLength = 255
+
+// claimIdempotencyKey claims key for owner within tx before the request does
+// any work. It returns the earlier record when the key was already used for
+// the same request, and fails when it was used for a different one. A retry
+// racing the original request waits on the original's claim and then sees
+// its response, or gets the key itself if the original rolled back.
+func claimIdempotencyKey(ctx context.Context, tx *sql.Tx, idempotencyKeyRepo repository.IdempotencyKeyRepository, owner string, key string, req interface{}) (*model.IdempotencyKey, error) {
+	if len(key) > maxIdempotencyKeyLength {
+		appErr := errors.New("Idempotency-Key must be at most 255 characters")
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		appErr := errors.New("failed to hash request")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	sum := sha256.Sum256(body)
+	requestHash := hex.EncodeToString(sum[:])
+
+	claimed, err := idempotencyKeyRepo.Claim(ctx, tx, owner, key, requestHash)
+	if err != nil {
+		appErr := errors.New("failed to claim idempotency key")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if claimed {
+		return nil, nil
+	}
+
+	existing, err := idempotencyKeyRepo.FindByKey(ctx, tx, owner, key)
+	if err != nil || existing == nil {
+		appErr := errors.New("failed to find idempotency key")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if existing.RequestHash != requestHash {
+		appErr := errors.New("Idempotency-Key was already used with a different request")
+		return nil, model.NewError(model.ErrUnprocessable, appErr)
+	}
+	return existing, nil
+}
+
+// saveIdempotentResponse stores the response of a request made with key so
+// that it can be replayed.
+func saveIdempotentResponse(ctx context.Context, tx *sql.Tx, idempotencyKeyRepo repository.IdempotencyKeyRepository, owner string, key string, response interface{}) error {
+	body, err := json.Marshal(response)
+	if err != nil {
+		appErr := errors.New("failed to encode response")
+		return model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	if err := idempotencyKeyRepo.SaveResponse(ctx, tx, owner, key, body); err != nil {
+		appErr := errors.New("failed to save idempotency key")
+		return model.NewError(model.ErrInternalFailure, appErr)
+	}
+	return nil
+}
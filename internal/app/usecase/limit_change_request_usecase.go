@@ -0,0 +1,266 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/internal/app/repository"
+	"github.com/glennprays/xyz-fin/internal/app/service"
+	"github.com/glennprays/xyz-fin/pkg/money"
+)
+
+type LimitChangeRequestUsecase interface {
+	Create(ctx context.Context, principal model.Principal, req *model.CreateLimitChangeRequest) (*model.LimitChangeRequest, error)
+	GetByID(ctx context.Context, principal model.Principal, id int64) (*model.LimitChangeRequest, error)
+	List(ctx context.Context, principal model.Principal, status string) ([]model.LimitChangeRequest, error)
+	Approve(ctx context.Context, principal model.Principal, id int64, note string) (*model.LimitChangeRequest, error)
+	Reject(ctx context.Context, principal model.Principal, id int64, note string) (*model.LimitChangeRequest, error)
+}
+
+type limitChangeRequestUsecase struct {
+	db                     *sql.DB
+	limitChangeRequestRepo repository.LimitChangeRequestRepository
+	consumerRepo           repository.ConsumerRepository
+	consumerLimitRepo      repository.ConsumerLimitRepository
+}
+
+func NewLimitChangeRequestUsecase(
+	db *sql.DB,
+	limitChangeRequestRepo repository.LimitChangeRequestRepository,
+	consumerRepo repository.ConsumerRepository,
+	consumerLimitRepo repository.ConsumerLimitRepository,
+) LimitChangeRequestUsecase {
+	return &limitChangeRequestUsecase{
+		db:                     db,
+		limitChangeRequestRepo: limitChangeRequestRepo,
+		consumerRepo:           consumerRepo,
+		consumerLimitRepo:      consumerLimitRepo,
+	}
+}
+
+func (u *limitChangeRequestUsecase) Create(ctx context.Context, principal model.Principal, req *model.CreateLimitChangeRequest) (*model.LimitChangeRequest, error) {
+	if err := authorize(principal, service.ActionRequestLimitChange, service.Resource{OwnerNIK: req.ConsumerNIK}, "consumer not found"); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		appErr := errors.New("reason is required")
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+	source := req.Source
+	switch source {
+	case "":
+		source = model.LimitSourceManual
+	case model.LimitSourceManual, model.LimitSourcePromotion:
+	default:
+		appErr := fmt.Errorf("unsupported source %q", req.Source)
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+	if len(req.Items) == 0 {
+		appErr := errors.New("at least one tenor must be requested")
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+
+	seenTenors := make(map[int]bool, len(req.Items))
+	for _, item := range req.Items {
+		if item.Tenor <= 0 {
+			appErr := fmt.Errorf("invalid tenor %d", item.Tenor)
+			return nil, model.NewError(model.ErrBadRequest, appErr)
+		}
+		if item.RequestedAmount < 0 {
+			appErr := fmt.Errorf("requested amount for tenor %d must not be negative", item.Tenor)
+			return nil, model.NewError(model.ErrBadRequest, appErr)
+		}
+		if seenTenors[item.Tenor] {
+			appErr := fmt.Errorf("tenor %d is requested more than once", item.Tenor)
+			return nil, model.NewError(model.ErrBadRequest, appErr)
+		}
+		seenTenors[item.Tenor] = true
+	}
+
+	consumer, err := u.consumerRepo.FindByNIK(ctx, req.ConsumerNIK)
+	if err != nil {
+		appErr := errors.New("failed to find consumer")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if consumer == nil {
+		appErr := errors.New("consumer not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+
+	currentLimits, err := u.consumerLimitRepo.FindByNIK(ctx, consumer.NIK)
+	if err != nil {
+		appErr := errors.New("failed to find consumer limits")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	currentByTenor := make(map[int]money.Amount, len(currentLimits))
+	for _, limit := range currentLimits {
+		currentByTenor[limit.Tenor] = limit.LimitAmount
+	}
+
+	request := &model.LimitChangeRequest{
+		ConsumerNIK: consumer.NIK,
+		Reason:      req.Reason,
+		Source:      source,
+		Status:      model.LimitChangeStatusPending,
+		RequestedBy: principal.StaffUsername,
+	}
+	for _, item := range req.Items {
+		requestItem := model.LimitChangeRequestItem{
+			Tenor:           item.Tenor,
+			RequestedAmount: item.RequestedAmount,
+		}
+		if current, ok := currentByTenor[item.Tenor]; ok {
+			requestItem.CurrentAmount = &current
+		}
+		request.Items = append(request.Items, requestItem)
+	}
+
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		appErr := errors.New("failed to begin transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	defer tx.Rollback()
+
+	if err := u.limitChangeRequestRepo.Create(ctx, tx, request); err != nil {
+		appErr := errors.New("failed to save limit change request")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	if err := tx.Commit(); err != nil {
+		appErr := errors.New("failed to commit transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	return request, nil
+}
+
+func (u *limitChangeRequestUsecase) GetByID(ctx context.Context, principal model.Principal, id int64) (*model.LimitChangeRequest, error) {
+	request, err := u.limitChangeRequestRepo.FindByID(ctx, id)
+	if err != nil {
+		appErr := errors.New("failed to find limit change request")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if request == nil {
+		appErr := errors.New("limit change request not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+	if err := authorize(principal, service.ActionViewLimits, service.Resource{OwnerNIK: request.ConsumerNIK}, "limit change request not found"); err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// List is the checkers' queue and spans every consumer, so it is open to
+// whoever may decide requests rather than to anyone who may view limits.
+func (u *limitChangeRequestUsecase) List(ctx context.Context, principal model.Principal, status string) ([]model.LimitChangeRequest, error) {
+	if err := authorize(principal, service.ActionDecideLimitChange, service.Resource{}, "limit change requests not found"); err != nil {
+		return nil, err
+	}
+
+	switch status {
+	case "", model.LimitChangeStatusPending, model.LimitChangeStatusApproved, model.LimitChangeStatusRejected:
+	default:
+		appErr := fmt.Errorf("unsupported status %q", status)
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+
+	requests, err := u.limitChangeRequestRepo.FindByStatus(ctx, status)
+	if err != nil {
+		appErr := errors.New("failed to find limit change requests")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	return requests, nil
+}
+
+func (u *limitChangeRequestUsecase) Approve(ctx context.Context, principal model.Principal, id int64, note string) (*model.LimitChangeRequest, error) {
+	return u.decide(ctx, principal, id, note, model.LimitChangeStatusApproved)
+}
+
+func (u *limitChangeRequestUsecase) Reject(ctx context.Context, principal model.Principal, id int64, note string) (*model.LimitChangeRequest, error) {
+	return u.decide(ctx, principal, id, note, model.LimitChangeStatusRejected)
+}
+
+// decide records the checker's decision and, for approvals, applies the
+// requested limits in the same database transaction.
+func (u *limitChangeRequestUsecase) decide(ctx context.Context, principal model.Principal, id int64, note string, status string) (*model.LimitChangeRequest, error) {
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		appErr := errors.New("failed to begin transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	defer tx.Rollback()
+
+	request, err := u.limitChangeRequestRepo.FindAndLockByID(ctx, tx, id)
+	if err != nil {
+		appErr := errors.New("failed to find limit change request")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if request == nil {
+		appErr := errors.New("limit change request not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+	if err := authorize(principal, service.ActionDecideLimitChange, service.Resource{OwnerNIK: request.ConsumerNIK}, "limit change request not found"); err != nil {
+		return nil, err
+	}
+
+	if request.Status != model.LimitChangeStatusPending {
+		appErr := fmt.Errorf("limit change request is already %s", strings.ToLower(request.Status))
+		return nil, model.NewError(model.ErrConflict, appErr)
+	}
+	if request.RequestedBy == principal.StaffUsername {
+		appErr := errors.New("limit change request must be decided by a different staff user")
+		return nil, model.NewError(model.ErrForbidden, appErr)
+	}
+
+	if status == model.LimitChangeStatusApproved {
+		consumer, err := u.consumerRepo.FindAndLockByNIK(ctx, tx, request.ConsumerNIK)
+		if err != nil {
+			appErr := errors.New("failed to find consumer")
+			return nil, model.NewError(model.ErrInternalFailure, appErr)
+		}
+		if consumer == nil {
+			appErr := errors.New("consumer not found")
+			return nil, model.NewError(model.ErrNotFound, appErr)
+		}
+
+		for _, item := range request.Items {
+			err := u.consumerLimitRepo.Upsert(ctx, tx, &model.ConsumerLimit{
+				ConsumerNIK: consumer.NIK,
+				Tenor:       item.Tenor,
+				LimitAmount: item.RequestedAmount,
+			}, request.Source, fmt.Sprintf("limit_change_request:%d", request.ID))
+			if err != nil {
+				appErr := errors.New("failed to save consumer limits")
+				return nil, model.NewError(model.ErrInternalFailure, appErr)
+			}
+		}
+	}
+
+	decidedAt := time.Now()
+	request.Status = status
+	request.DecidedBy = &principal.StaffUsername
+	request.DecidedAt = &decidedAt
+	if note != "" {
+		request.DecisionNote = &note
+	}
+
+	if err := u.limitChangeRequestRepo.UpdateDecision(ctx, tx, request); err != nil {
+		appErr := errors.New("failed to save limit change decision")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	if err := tx.Commit(); err != nil {
+		appErr := errors.New("failed to commit transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	return request, nil
+}
@@ -0,0 +1,237 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/internal/app/repository"
+	"github.com/glennprays/xyz-fin/internal/app/service"
+)
+
+type LimitHoldUsecase interface {
+	Create(ctx context.Context, principal model.Principal, req *model.CreateLimitHoldRequest) (*model.LimitHold, error)
+	GetByID(ctx context.Context, principal model.Principal, id int64) (*model.LimitHold, error)
+	Release(ctx context.Context, principal model.Principal, id int64) (*model.LimitHold, error)
+	ExpireHolds(ctx context.Context) (int64, error)
+}
+
+type limitHoldUsecase struct {
+	db                *sql.DB
+	limitHoldRepo     repository.LimitHoldRepository
+	consumerRepo      repository.ConsumerRepository
+	consumerLimitRepo repository.ConsumerLimitRepository
+	transactionRepo   repository.TransactionRepository
+	productRepo       repository.ProductRepository
+	limitPolicies     service.LimitPolicyResolver
+	holdTTL           time.Duration
+}
+
+func NewLimitHoldUsecase(
+	db *sql.DB,
+	limitHoldRepo repository.LimitHoldRepository,
+	consumerRepo repository.ConsumerRepository,
+	consumerLimitRepo repository.ConsumerLimitRepository,
+	transactionRepo repository.TransactionRepository,
+	productRepo repository.ProductRepository,
+	limitPolicies service.LimitPolicyResolver,
+	holdTTL time.Duration,
+) LimitHoldUsecase {
+	return &limitHoldUsecase{
+		db:                db,
+		limitHoldRepo:     limitHoldRepo,
+		consumerRepo:      consumerRepo,
+		consumerLimitRepo: consumerLimitRepo,
+		transactionRepo:   transactionRepo,
+		productRepo:       productRepo,
+		limitPolicies:     limitPolicies,
+		holdTTL:           holdTTL,
+	}
+}
+
+// Create reserves limit for a consumer. A consumer holds their own limit; the
+// staff of a merchant hold it for a cart at their merchant, and the hold
+// records that merchant so no other merchant can capture or release it.
+func (u *limitHoldUsecase) Create(ctx context.Context, principal model.Principal, req *model.CreateLimitHoldRequest) (*model.LimitHold, error) {
+	if req.Amount <= 0 {
+		appErr := errors.New("amount must be greater than zero")
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+	if strings.TrimSpace(req.Reference) == "" {
+		appErr := errors.New("reference is required")
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+
+	principal, _, err := resolvePrincipal(ctx, u.consumerRepo, principal)
+	if err != nil {
+		return nil, err
+	}
+	var merchantID *int64
+	if principal.MerchantID != 0 {
+		merchantID = &principal.MerchantID
+	}
+	if err := authorize(principal, service.ActionHoldLimit, service.Resource{OwnerNIK: req.ConsumerNIK, MerchantID: principal.MerchantID}, "consumer not found"); err != nil {
+		return nil, err
+	}
+
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		appErr := errors.New("failed to begin transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	defer tx.Rollback()
+
+	consumer, err := u.consumerRepo.FindAndLockByNIK(ctx, tx, req.ConsumerNIK)
+	if err != nil {
+		appErr := errors.New("failed to find consumer")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if consumer == nil {
+		appErr := errors.New("consumer not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+
+	if _, err := checkProduct(ctx, u.productRepo, consumer, req.ProductCode, req.Amount, req.Tenor); err != nil {
+		return nil, err
+	}
+
+	consumerLimit, err := u.consumerLimitRepo.FindByNIKAndTenor(ctx, tx, consumer.NIK, req.Tenor)
+	if err != nil {
+		appErr := errors.New("failed to find consumer limits")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if consumerLimit == nil {
+		appErr := errors.New("consumer limits not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+
+	allLimits, err := u.consumerLimitRepo.FindByNIKInTx(ctx, tx, consumer.NIK)
+	if err != nil {
+		appErr := errors.New("failed to find consumer limits")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	usages, err := loadLimitUsage(ctx, tx, u.transactionRepo, u.limitHoldRepo, consumer.NIK)
+	if err != nil {
+		appErr := errors.New("failed to get limit usage")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	if _, err := service.CheckLimit(u.limitPolicies, req.ProductCode, *consumerLimit, allLimits, usages, req.Amount); err != nil {
+		appErr := errors.New("hold exceeds available limit")
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+
+	hold := &model.LimitHold{
+		ConsumerNIK: consumer.NIK,
+		MerchantID:  merchantID,
+		ProductCode: req.ProductCode,
+		Tenor:       req.Tenor,
+		Amount:      req.Amount,
+		Reference:   req.Reference,
+		Status:      model.LimitHoldStatusActive,
+	}
+	if err := u.limitHoldRepo.Create(ctx, tx, hold, u.holdTTL); err != nil {
+		appErr := errors.New("failed to save limit hold")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	if err := tx.Commit(); err != nil {
+		appErr := errors.New("failed to commit transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	return hold, nil
+}
+
+func (u *limitHoldUsecase) GetByID(ctx context.Context, principal model.Principal, id int64) (*model.LimitHold, error) {
+	hold, err := u.limitHoldRepo.FindByID(ctx, id)
+	if err != nil {
+		appErr := errors.New("failed to find limit hold")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if hold == nil {
+		appErr := errors.New("limit hold not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+
+	if err := u.checkOwner(ctx, principal, hold); err != nil {
+		return nil, err
+	}
+
+	return hold, nil
+}
+
+func (u *limitHoldUsecase) Release(ctx context.Context, principal model.Principal, id int64) (*model.LimitHold, error) {
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		appErr := errors.New("failed to begin transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	defer tx.Rollback()
+
+	hold, err := u.limitHoldRepo.FindAndLockByID(ctx, tx, id)
+	if err != nil {
+		appErr := errors.New("failed to find limit hold")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if hold == nil {
+		appErr := errors.New("limit hold not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+
+	if err := u.checkOwner(ctx, principal, hold); err != nil {
+		return nil, err
+	}
+
+	if hold.Status != model.LimitHoldStatusActive {
+		appErr := errors.New("limit hold is no longer active")
+		return nil, model.NewError(model.ErrConflict, appErr)
+	}
+
+	hold.Status = model.LimitHoldStatusReleased
+	if err := u.limitHoldRepo.UpdateStatus(ctx, tx, hold); err != nil {
+		appErr := errors.New("failed to update limit hold")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	if err := tx.Commit(); err != nil {
+		appErr := errors.New("failed to commit transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	return hold, nil
+}
+
+func (u *limitHoldUsecase) ExpireHolds(ctx context.Context) (int64, error) {
+	expired, err := u.limitHoldRepo.ExpireDue(ctx)
+	if err != nil {
+		appErr := errors.New("failed to expire limit holds")
+		return 0, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	return expired, nil
+}
+
+// checkOwner lets the consumer of a hold and the merchant that placed it act
+// on the hold.
+func (u *limitHoldUsecase) checkOwner(ctx context.Context, principal model.Principal, hold *model.LimitHold) error {
+	principal, _, err := resolvePrincipal(ctx, u.consumerRepo, principal)
+	if err != nil {
+		return err
+	}
+
+	return authorize(principal, service.ActionHoldLimit, holdResource(hold), "limit hold not found")
+}
+
+// holdResource describes a limit hold for the access policy.
+func holdResource(hold *model.LimitHold) service.Resource {
+	resource := service.Resource{OwnerNIK: hold.ConsumerNIK}
+	if hold.MerchantID != nil {
+		resource.MerchantID = *hold.MerchantID
+	}
+	return resource
+}
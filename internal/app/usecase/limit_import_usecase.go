@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/internal/app/repository"
+	"github.com/glennprays/xyz-fin/internal/app/service"
+)
+
+const defaultLimitImportBatchSize = 500
+
+type LimitImportUsecase interface {
+	Import(ctx context.Context, operator string, r io.Reader, dryRun bool) (*model.LimitImportReport, error)
+	WriteResult(w io.Writer, report *model.LimitImportReport) error
+}
+
+type limitImportUsecase struct {
+	db                *sql.DB
+	consumerRepo      repository.ConsumerRepository
+	consumerLimitRepo repository.ConsumerLimitRepository
+	parser            service.LimitImportParser
+	batchSize         int
+}
+
+func NewLimitImportUsecase(
+	db *sql.DB,
+	consumerRepo repository.ConsumerRepository,
+	consumerLimitRepo repository.ConsumerLimitRepository,
+	parser service.LimitImportParser,
+	batchSize int,
+) LimitImportUsecase {
+	if batchSize <= 0 {
+		batchSize = defaultLimitImportBatchSize
+	}
+	return &limitImportUsecase{
+		db:                db,
+		consumerRepo:      consumerRepo,
+		consumerLimitRepo: consumerLimitRepo,
+		parser:            parser,
+		batchSize:         batchSize,
+	}
+}
+
+// Import validates every row of a limit file and, unless dryRun is set,
+// applies the valid rows in batches. A failing batch is rolled back on its
+// own and its rows are reported as FAILED; other batches are unaffected.
+func (u *limitImportUsecase) Import(ctx context.Context, operator string, r io.Reader, dryRun bool) (*model.LimitImportReport, error) {
+	rows, err := u.parser.Parse(r)
+	if err != nil {
+		return nil, model.NewError(model.ErrBadRequest, fmt.Errorf("invalid limit file: %w", err))
+	}
+	if len(rows) == 0 {
+		appErr := errors.New("limit file has no rows")
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+
+	if err := u.checkConsumers(ctx, rows); err != nil {
+		log.Printf("failed to check consumers for limit import: %v", err)
+		appErr := errors.New("failed to validate limit file")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	report := &model.LimitImportReport{DryRun: dryRun}
+	if !dryRun {
+		report.Reference = fmt.Sprintf("limit_import:%s:%s", operator, time.Now().Format("20060102T150405"))
+		u.apply(ctx, rows, report.Reference)
+	}
+
+	report.Rows = rows
+	report.TotalRows = len(rows)
+	for _, row := range rows {
+		switch row.Status {
+		case model.LimitImportRowValid:
+			report.ValidRows++
+		case model.LimitImportRowApplied:
+			report.ValidRows++
+			report.AppliedRows++
+		case model.LimitImportRowFailed:
+			report.ValidRows++
+			report.FailedRows++
+		default:
+			report.InvalidRows++
+		}
+	}
+
+	return report, nil
+}
+
+func (u *limitImportUsecase) WriteResult(w io.Writer, report *model.LimitImportReport) error {
+	return u.parser.WriteResult(w, report.Rows)
+}
+
+// checkConsumers marks valid rows whose consumer does not exist as invalid.
+func (u *limitImportUsecase) checkConsumers(ctx context.Context, rows []model.LimitImportRow) error {
+	valid := validRowIndexes(rows)
+	for start := 0; start < len(valid); start += u.batchSize {
+		batch := valid[start:min(start+u.batchSize, len(valid))]
+
+		niks := make([]string, 0, len(batch))
+		for _, i := range batch {
+			niks = append(niks, rows[i].ConsumerNIK)
+		}
+
+		existing, err := u.consumerRepo.FindExistingNIKs(ctx, niks)
+		if err != nil {
+			return err
+		}
+
+		for _, i := range batch {
+			if !existing[rows[i].ConsumerNIK] {
+				rows[i].Status = model.LimitImportRowInvalid
+				rows[i].Error = "consumer not found"
+			}
+		}
+	}
+	return nil
+}
+
+func (u *limitImportUsecase) apply(ctx context.Context, rows []model.LimitImportRow, reference string) {
+	valid := validRowIndexes(rows)
+	for start := 0; start < len(valid); start += u.batchSize {
+		batch := valid[start:min(start+u.batchSize, len(valid))]
+
+		status, message := model.LimitImportRowApplied, ""
+		if err := u.applyBatch(ctx, rows, batch, reference); err != nil {
+			log.Printf("failed to apply limit import batch starting at line %d: %v", rows[batch[0]].Line, err)
+			status, message = model.LimitImportRowFailed, "batch rolled back"
+		}
+
+		for _, i := range batch {
+			rows[i].Status = status
+			rows[i].Error = message
+		}
+	}
+}
+
+// applyBatch upserts the limits of one batch in a single transaction. Like
+// every other limit writer it locks the consumers first, so a contract or hold
+// being checked against a limit never sees it change halfway. The consumers
+// are locked in NIK order so two imports cannot deadlock on each other.
+func (u *limitImportUsecase) applyBatch(ctx context.Context, rows []model.LimitImportRow, batch []int, reference string) error {
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	niks := make([]string, 0, len(batch))
+	for _, i := range batch {
+		niks = append(niks, rows[i].ConsumerNIK)
+	}
+	slices.Sort(niks)
+	for _, nik := range slices.Compact(niks) {
+		if _, err := u.consumerRepo.FindAndLockByNIK(ctx, tx, nik); err != nil {
+			return fmt.Errorf("lock consumer %s: %w", nik, err)
+		}
+	}
+
+	for _, i := range batch {
+		limit := &model.ConsumerLimit{
+			ConsumerNIK: rows[i].ConsumerNIK,
+			Tenor:       rows[i].Tenor,
+			LimitAmount: rows[i].LimitAmount,
+		}
+		if err := u.consumerLimitRepo.Upsert(ctx, tx, limit, model.LimitSourceImport, reference); err != nil {
+			return fmt.Errorf("line %d: %w", rows[i].Line, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func validRowIndexes(rows []model.LimitImportRow) []int {
+	var indexes []int
+	for i, row := range rows {
+		if row.Status == model.LimitImportRowValid {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
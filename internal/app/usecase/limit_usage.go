@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/internal/app/repository"
+)
+
+// loadLimitUsage collects everything that counts against a consumer's limit:
+// active and pending contracts plus unexpired holds.
+func loadLimitUsage(
+	ctx context.Context,
+	tx *sql.Tx,
+	transactionRepo repository.TransactionRepository,
+	limitHoldRepo repository.LimitHoldRepository,
+	nik string,
+) ([]model.TenorUsage, error) {
+	usages, err := transactionRepo.GetUsageByNIK(ctx, tx, nik)
+	if err != nil {
+		return nil, err
+	}
+
+	held, err := limitHoldRepo.GetHeldByNIK(ctx, tx, nik)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(usages, held...), nil
+}
@@ -0,0 +1,263 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/internal/app/repository"
+	"github.com/glennprays/xyz-fin/internal/app/service"
+)
+
+type MerchantUsecase interface {
+	Create(ctx context.Context, req *model.CreateMerchantRequest) (*model.Merchant, error)
+	GetByID(ctx context.Context, id int64) (*model.Merchant, error)
+	List(ctx context.Context, filter model.MerchantFilter) ([]model.Merchant, error)
+	UpdateStatus(ctx context.Context, id int64, req *model.MerchantStatusRequest) (*model.Merchant, error)
+	CreateOutlet(ctx context.Context, merchantID int64, req *model.CreateMerchantOutletRequest) (*model.MerchantOutlet, error)
+	UpdateOutletStatus(ctx context.Context, merchantID int64, outletID int64, req *model.MerchantStatusRequest) (*model.MerchantOutlet, error)
+}
+
+type merchantUsecase struct {
+	merchantRepo repository.MerchantRepository
+	outletRepo   repository.MerchantOutletRepository
+}
+
+func NewMerchantUsecase(merchantRepo repository.MerchantRepository, outletRepo repository.MerchantOutletRepository) MerchantUsecase {
+	return &merchantUsecase{
+		merchantRepo: merchantRepo,
+		outletRepo:   outletRepo,
+	}
+}
+
+func (u *merchantUsecase) Create(ctx context.Context, req *model.CreateMerchantRequest) (*model.Merchant, error) {
+	code := strings.TrimSpace(req.Code)
+	name := strings.TrimSpace(req.Name)
+	switch {
+	case code == "":
+		return nil, model.NewError(model.ErrBadRequest, errors.New("code is required"))
+	case name == "":
+		return nil, model.NewError(model.ErrBadRequest, errors.New("name is required"))
+	case req.SettlementAccount.BankCode == "" || req.SettlementAccount.AccountNumber == "" || req.SettlementAccount.AccountName == "":
+		return nil, model.NewError(model.ErrBadRequest, errors.New("settlement_account bank_code, account_number and account_name are required"))
+	}
+	if err := service.ValidateMDRRate(req.MDRRate); err != nil {
+		return nil, model.NewError(model.ErrBadRequest, err)
+	}
+
+	existing, err := u.merchantRepo.FindByCode(ctx, code)
+	if err != nil {
+		appErr := errors.New("failed to find merchant")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if existing != nil {
+		appErr := fmt.Errorf("merchant %s already exists", code)
+		return nil, model.NewError(model.ErrConflict, appErr)
+	}
+
+	merchant := &model.Merchant{
+		Code:              code,
+		Name:              name,
+		MDRRate:           req.MDRRate,
+		SettlementAccount: req.SettlementAccount,
+		Status:            model.MerchantStatusActive,
+	}
+	if err := u.merchantRepo.Create(ctx, merchant); err != nil {
+		appErr := errors.New("failed to save merchant")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	return merchant, nil
+}
+
+// GetByID returns a merchant with all of its outlets.
+func (u *merchantUsecase) GetByID(ctx context.Context, id int64) (*model.Merchant, error) {
+	merchant, err := u.findMerchant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	outlets, err := u.outletRepo.FindByMerchantID(ctx, id)
+	if err != nil {
+		appErr := errors.New("failed to find merchant outlets")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	merchant.Outlets = outlets
+
+	return merchant, nil
+}
+
+func (u *merchantUsecase) List(ctx context.Context, filter model.MerchantFilter) ([]model.Merchant, error) {
+	if filter.Status != "" && !isMerchantStatus(filter.Status) {
+		appErr := fmt.Errorf("unsupported status %q", filter.Status)
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+
+	merchants, err := u.merchantRepo.List(ctx, filter)
+	if err != nil {
+		appErr := errors.New("failed to list merchants")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	return merchants, nil
+}
+
+// UpdateStatus suspends, reactivates or terminates a merchant. A terminated
+// merchant stays terminated.
+func (u *merchantUsecase) UpdateStatus(ctx context.Context, id int64, req *model.MerchantStatusRequest) (*model.Merchant, error) {
+	if !isMerchantStatus(req.Status) {
+		appErr := fmt.Errorf("unsupported status %q", req.Status)
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+
+	merchant, err := u.findMerchant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if merchant.Status == model.MerchantStatusTerminated && req.Status != model.MerchantStatusTerminated {
+		appErr := errors.New("merchant is terminated")
+		return nil, model.NewError(model.ErrConflict, appErr)
+	}
+
+	if err := u.merchantRepo.UpdateStatus(ctx, id, req.Status); err != nil {
+		appErr := errors.New("failed to update merchant")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	merchant.Status = req.Status
+
+	return merchant, nil
+}
+
+func (u *merchantUsecase) CreateOutlet(ctx context.Context, merchantID int64, req *model.CreateMerchantOutletRequest) (*model.MerchantOutlet, error) {
+	code := strings.TrimSpace(req.Code)
+	name := strings.TrimSpace(req.Name)
+	switch {
+	case code == "":
+		return nil, model.NewError(model.ErrBadRequest, errors.New("code is required"))
+	case name == "":
+		return nil, model.NewError(model.ErrBadRequest, errors.New("name is required"))
+	}
+
+	merchant, err := u.findMerchant(ctx, merchantID)
+	if err != nil {
+		return nil, err
+	}
+	if merchant.Status == model.MerchantStatusTerminated {
+		appErr := errors.New("merchant is terminated")
+		return nil, model.NewError(model.ErrConflict, appErr)
+	}
+
+	outlets, err := u.outletRepo.FindByMerchantID(ctx, merchantID)
+	if err != nil {
+		appErr := errors.New("failed to find merchant outlets")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	for _, outlet := range outlets {
+		if outlet.Code == code {
+			appErr := fmt.Errorf("outlet %s already exists", code)
+			return nil, model.NewError(model.ErrConflict, appErr)
+		}
+	}
+
+	outlet := &model.MerchantOutlet{
+		MerchantID: merchantID,
+		Code:       code,
+		Name:       name,
+		Address:    strings.TrimSpace(req.Address),
+		Status:     model.OutletStatusActive,
+	}
+	if err := u.outletRepo.Create(ctx, outlet); err != nil {
+		appErr := errors.New("failed to save merchant outlet")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	return outlet, nil
+}
+
+func (u *merchantUsecase) UpdateOutletStatus(ctx context.Context, merchantID int64, outletID int64, req *model.MerchantStatusRequest) (*model.MerchantOutlet, error) {
+	if req.Status != model.OutletStatusActive && req.Status != model.OutletStatusClosed {
+		appErr := fmt.Errorf("unsupported status %q", req.Status)
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+
+	outlet, err := u.outletRepo.FindByID(ctx, outletID)
+	if err != nil {
+		appErr := errors.New("failed to find merchant outlet")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if outlet == nil || outlet.MerchantID != merchantID {
+		appErr := errors.New("merchant outlet not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+
+	if err := u.outletRepo.UpdateStatus(ctx, outletID, req.Status); err != nil {
+		appErr := errors.New("failed to update merchant outlet")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	outlet.Status = req.Status
+
+	return outlet, nil
+}
+
+func (u *merchantUsecase) findMerchant(ctx context.Context, id int64) (*model.Merchant, error) {
+	merchant, err := u.merchantRepo.FindByID(ctx, id)
+	if err != nil {
+		appErr := errors.New("failed to find merchant")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if merchant == nil {
+		appErr := errors.New("merchant not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+	return merchant, nil
+}
+
+func isMerchantStatus(status string) bool {
+	switch status {
+	case model.MerchantStatusActive, model.MerchantStatusSuspended, model.MerchantStatusTerminated:
+		return true
+	}
+	return false
+}
+
+// checkMerchantOrigin looks up the merchant and outlet a contract is opened
+// at and checks that both may still originate contracts. Both rows are share
+// locked in tx so neither can be suspended or closed before the contract is
+// saved.
+func checkMerchantOrigin(ctx context.Context, tx *sql.Tx, merchantRepo repository.MerchantRepository, outletRepo repository.MerchantOutletRepository, merchantID int64, outletID *int64) (*model.Merchant, *model.MerchantOutlet, error) {
+	if merchantID == 0 {
+		appErr := errors.New("merchant_id is required")
+		return nil, nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+
+	merchant, err := merchantRepo.FindAndShareLockByID(ctx, tx, merchantID)
+	if err != nil {
+		appErr := errors.New("failed to find merchant")
+		return nil, nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if merchant == nil {
+		appErr := errors.New("merchant not found")
+		return nil, nil, model.NewError(model.ErrNotFound, appErr)
+	}
+
+	var outlet *model.MerchantOutlet
+	if outletID != nil {
+		outlet, err = outletRepo.FindAndShareLockByID(ctx, tx, *outletID)
+		if err != nil {
+			appErr := errors.New("failed to find merchant outlet")
+			return nil, nil, model.NewError(model.ErrInternalFailure, appErr)
+		}
+		if outlet == nil {
+			appErr := errors.New("merchant outlet not found")
+			return nil, nil, model.NewError(model.ErrNotFound, appErr)
+		}
+	}
+
+	if err := service.CheckMerchantOrigin(*merchant, outlet); err != nil {
+		return nil, nil, model.NewError(model.ErrBadRequest, err)
+	}
+
+	return merchant, outlet, nil
+}
@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/internal/app/repository"
+	"github.com/glennprays/xyz-fin/internal/app/service"
+	"github.com/glennprays/xyz-fin/pkg/money"
+)
+
+type PaymentUsecase interface {
+	Post(ctx context.Context, staffUsername string, nomorKontrak string, req *model.PaymentRequest) (*model.Payment, error)
+	ListByNomorKontrak(ctx context.Context, nomorKontrak string) ([]model.Payment, error)
+}
+
+type paymentUsecase struct {
+	db              *sql.DB
+	transactionRepo repository.TransactionRepository
+	installmentRepo repository.InstallmentRepository
+	paymentRepo     repository.PaymentRepository
+	transitionRepo  repository.TransactionStatusTransitionRepository
+	allocator       *service.PaymentAllocator
+}
+
+func NewPaymentUsecase(
+	db *sql.DB,
+	transactionRepo repository.TransactionRepository,
+	installmentRepo repository.InstallmentRepository,
+	paymentRepo repository.PaymentRepository,
+	transitionRepo repository.TransactionStatusTransitionRepository,
+	allocator *service.PaymentAllocator,
+) PaymentUsecase {
+	return &paymentUsecase{
+		db:              db,
+		transactionRepo: transactionRepo,
+		installmentRepo: installmentRepo,
+		paymentRepo:     paymentRepo,
+		transitionRepo:  transitionRepo,
+		allocator:       allocator,
+	}
+}
+
+// Post records a payment and allocates it over the contract's installments,
+// oldest first. A partial payment leaves the installment PARTIAL; a payment
+// that settles the whole schedule pays the contract off, and whatever is
+// left over is kept on the payment as unapplied. The schedule, the contract
+// balances and the payment are written in one transaction while the
+// contract row is locked.
+func (u *paymentUsecase) Post(ctx context.Context, staffUsername string, nomorKontrak string, req *model.PaymentRequest) (*model.Payment, error) {
+	if err := checkNomorKontrak(nomorKontrak); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.Reference) == "" {
+		appErr := errors.New("reference is required")
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+	if req.Amount <= 0 {
+		appErr := errors.New("amount must be greater than zero")
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+
+	now := time.Now()
+	paidAt := now
+	if req.PaidAt != nil {
+		if req.PaidAt.After(now) {
+			appErr := errors.New("paid_at must not be in the future")
+			return nil, model.NewError(model.ErrBadRequest, appErr)
+		}
+		paidAt = *req.PaidAt
+	}
+
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		appErr := errors.New("failed to begin transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	defer tx.Rollback()
+
+	transaction, err := u.transactionRepo.FindAndLockByNomorKontrak(ctx, tx, nomorKontrak)
+	if err != nil {
+		appErr := errors.New("failed to find transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if transaction == nil {
+		appErr := errors.New("transaction not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+	if transaction.Status != model.TransactionStatusActive && transaction.Status != model.TransactionStatusDefaulted {
+		appErr := fmt.Errorf("payments cannot be posted to a %s contract", transaction.Status)
+		return nil, model.NewError(model.ErrConflict, appErr)
+	}
+
+	existing, err := u.paymentRepo.FindByReference(ctx, tx, nomorKontrak, req.Reference)
+	if err != nil {
+		appErr := errors.New("failed to find payment")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if existing != nil {
+		appErr := fmt.Errorf("payment %s was already posted", req.Reference)
+		return nil, model.NewError(model.ErrConflict, appErr)
+	}
+
+	installments, err := u.installmentRepo.FindAndLockByNomorKontrak(ctx, tx, nomorKontrak)
+	if err != nil {
+		appErr := errors.New("failed to find installment schedule")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	var outstanding money.Amount
+	for _, installment := range installments {
+		outstanding += service.InstallmentOutstanding(installment)
+	}
+	if outstanding == 0 {
+		appErr := errors.New("contract has nothing left to pay")
+		return nil, model.NewError(model.ErrConflict, appErr)
+	}
+
+	allocations, unapplied := u.allocator.Allocate(req.Amount, installments)
+
+	touched := make(map[int]bool, len(allocations))
+	var principal money.Amount
+	for _, allocation := range allocations {
+		touched[allocation.InstallmentNumber] = true
+		if allocation.Component == model.PaymentComponentPrincipal {
+			principal += allocation.Amount
+		}
+	}
+	for i := range installments {
+		installment := &installments[i]
+		if !touched[installment.InstallmentNumber] {
+			continue
+		}
+		if installment.Status == model.InstallmentStatusPaid {
+			installment.PaidAt = &paidAt
+		}
+		if err := u.installmentRepo.UpdatePaid(ctx, tx, installment); err != nil {
+			appErr := errors.New("failed to update installment")
+			return nil, model.NewError(model.ErrInternalFailure, appErr)
+		}
+	}
+
+	payment := &model.Payment{
+		NomorKontrak:    nomorKontrak,
+		Reference:       req.Reference,
+		Amount:          req.Amount,
+		AllocatedAmount: req.Amount - unapplied,
+		UnappliedAmount: unapplied,
+		PaidAt:          paidAt,
+		PostedBy:        staffUsername,
+		Allocations:     allocations,
+	}
+	if err := u.paymentRepo.Create(ctx, tx, payment); err != nil {
+		appErr := errors.New("failed to save payment")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	if err := u.transactionRepo.ApplyPayment(ctx, tx, nomorKontrak, principal, payment.AllocatedAmount); err != nil {
+		appErr := errors.New("failed to update transaction balances")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	if payment.AllocatedAmount == outstanding {
+		reason := fmt.Sprintf("paid in full by payment %s", payment.Reference)
+		_, err := transitionContract(ctx, tx, u.transactionRepo, u.transitionRepo, transaction, model.TransactionStatusPaidOff, reason, staffUsername)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		appErr := errors.New("failed to commit transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	return payment, nil
+}
+
+func (u *paymentUsecase) ListByNomorKontrak(ctx context.Context, nomorKontrak string) ([]model.Payment, error) {
+	if err := checkNomorKontrak(nomorKontrak); err != nil {
+		return nil, err
+	}
+
+	transaction, err := u.transactionRepo.FindByNomorKontrak(ctx, nomorKontrak)
+	if err != nil {
+		appErr := errors.New("failed to find transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if transaction == nil {
+		appErr := errors.New("transaction not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+
+	payments, err := u.paymentRepo.FindByNomorKontrak(ctx, nomorKontrak)
+	if err != nil {
+		appErr := errors.New("failed to find payments")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	return payments, nil
+}
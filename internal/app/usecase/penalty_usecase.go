@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/internal/app/repository"
+	"github.com/glennprays/xyz-fin/internal/app/service"
+)
+
+type PenaltyUsecase interface {
+	AccrueOverdue(ctx context.Context, asOf time.Time) (int, error)
+	ListByNomorKontrak(ctx context.Context, principal model.Principal, nomorKontrak string) ([]model.PenaltyCharge, error)
+}
+
+type penaltyUsecase struct {
+	db                *sql.DB
+	transactionRepo   repository.TransactionRepository
+	installmentRepo   repository.InstallmentRepository
+	productRepo       repository.ProductRepository
+	penaltyChargeRepo repository.PenaltyChargeRepository
+	calculator        *service.PenaltyCalculator
+}
+
+func NewPenaltyUsecase(
+	db *sql.DB,
+	transactionRepo repository.TransactionRepository,
+	installmentRepo repository.InstallmentRepository,
+	productRepo repository.ProductRepository,
+	penaltyChargeRepo repository.PenaltyChargeRepository,
+	calculator *service.PenaltyCalculator,
+) PenaltyUsecase {
+	return &penaltyUsecase{
+		db:                db,
+		transactionRepo:   transactionRepo,
+		installmentRepo:   installmentRepo,
+		productRepo:       productRepo,
+		penaltyChargeRepo: penaltyChargeRepo,
+		calculator:        calculator,
+	}
+}
+
+// AccrueOverdue charges penalties on every overdue installment up to asOf
+// and returns the number of charges made. Each contract is handled in its
+// own transaction under the same row lock as payments, so a payment is never
+// allocated against a penalty that is being recalculated. A contract that
+// fails is logged and skipped so it does not hold up the rest of the batch;
+// the failures are returned joined next to the charges that were made.
+// Running it more than once a day is harmless.
+func (u *penaltyUsecase) AccrueOverdue(ctx context.Context, asOf time.Time) (int, error) {
+	nomorKontraks, err := u.installmentRepo.FindNomorKontrakWithOverdue(ctx, asOf)
+	if err != nil {
+		appErr := errors.New("failed to find overdue installments")
+		return 0, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	products := make(map[string]*model.Product)
+	charged := 0
+	var errs []error
+	for _, nomorKontrak := range nomorKontraks {
+		count, err := u.accrueContract(ctx, nomorKontrak, asOf, products)
+		if err != nil {
+			log.Printf("failed to accrue penalties for contract %s: %v", nomorKontrak, err)
+			errs = append(errs, fmt.Errorf("contract %s: %w", nomorKontrak, err))
+			continue
+		}
+		charged += count
+	}
+
+	return charged, errors.Join(errs...)
+}
+
+func (u *penaltyUsecase) accrueContract(ctx context.Context, nomorKontrak string, asOf time.Time, products map[string]*model.Product) (int, error) {
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		appErr := errors.New("failed to begin transaction")
+		return 0, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	defer tx.Rollback()
+
+	transaction, err := u.transactionRepo.FindAndLockByNomorKontrak(ctx, tx, nomorKontrak)
+	if err != nil {
+		appErr := errors.New("failed to find transaction")
+		return 0, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if transaction == nil || transaction.ProductCode == "" ||
+		(transaction.Status != model.TransactionStatusActive && transaction.Status != model.TransactionStatusDefaulted) {
+		return 0, nil
+	}
+
+	product, ok := products[transaction.ProductCode]
+	if !ok {
+		product, err = u.productRepo.FindByCode(ctx, transaction.ProductCode)
+		if err != nil {
+			appErr := errors.New("failed to find product")
+			return 0, model.NewError(model.ErrInternalFailure, appErr)
+		}
+		if product == nil {
+			appErr := fmt.Errorf("product %s of contract %s not found", transaction.ProductCode, nomorKontrak)
+			return 0, model.NewError(model.ErrInternalFailure, appErr)
+		}
+		products[transaction.ProductCode] = product
+	}
+
+	installments, err := u.installmentRepo.FindAndLockByNomorKontrak(ctx, tx, nomorKontrak)
+	if err != nil {
+		appErr := errors.New("failed to find installment schedule")
+		return 0, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	charged := 0
+	for i := range installments {
+		installment := &installments[i]
+		charge, overdue := u.calculator.Accrue(*product, installment, asOf)
+		if !overdue {
+			continue
+		}
+		if err := u.installmentRepo.UpdatePenalty(ctx, tx, installment); err != nil {
+			appErr := errors.New("failed to update installment")
+			return 0, model.NewError(model.ErrInternalFailure, appErr)
+		}
+		if charge == nil {
+			continue
+		}
+		if err := u.penaltyChargeRepo.Create(ctx, tx, charge); err != nil {
+			appErr := errors.New("failed to save penalty charge")
+			return 0, model.NewError(model.ErrInternalFailure, appErr)
+		}
+		charged++
+	}
+
+	if err := tx.Commit(); err != nil {
+		appErr := errors.New("failed to commit transaction")
+		return 0, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	return charged, nil
+}
+
+func (u *penaltyUsecase) ListByNomorKontrak(ctx context.Context, principal model.Principal, nomorKontrak string) ([]model.PenaltyCharge, error) {
+	if err := checkNomorKontrak(nomorKontrak); err != nil {
+		return nil, err
+	}
+
+	transaction, err := u.transactionRepo.FindByNomorKontrak(ctx, nomorKontrak)
+	if err != nil {
+		appErr := errors.New("failed to find transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if transaction == nil {
+		appErr := errors.New("transaction not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+	if err := authorize(principal, service.ActionViewPenalties, contractResource(transaction), "transaction not found"); err != nil {
+		return nil, err
+	}
+
+	charges, err := u.penaltyChargeRepo.FindByNomorKontrak(ctx, nomorKontrak)
+	if err != nil {
+		appErr := errors.New("failed to find penalty charges")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	return charges, nil
+}
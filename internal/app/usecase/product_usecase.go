@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/internal/app/repository"
+	"github.com/glennprays/xyz-fin/internal/app/service"
+	"github.com/glennprays/xyz-fin/pkg/money"
+)
+
+type ProductUsecase interface {
+	List(ctx context.Context) ([]model.Product, error)
+	GetByCode(ctx context.Context, code string) (*model.Product, error)
+}
+
+type productUsecase struct {
+	productRepo repository.ProductRepository
+}
+
+func NewProductUsecase(productRepo repository.ProductRepository) ProductUsecase {
+	return &productUsecase{productRepo: productRepo}
+}
+
+func (u *productUsecase) List(ctx context.Context) ([]model.Product, error) {
+	products, err := u.productRepo.FindActive(ctx)
+	if err != nil {
+		appErr := errors.New("failed to find products")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if products == nil {
+		products = []model.Product{}
+	}
+	return products, nil
+}
+
+func (u *productUsecase) GetByCode(ctx context.Context, code string) (*model.Product, error) {
+	product, err := u.productRepo.FindByCode(ctx, code)
+	if err != nil {
+		appErr := errors.New("failed to find product")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if product == nil || !product.IsActive {
+		appErr := errors.New("product not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+	return product, nil
+}
+
+// checkProduct loads the requested product and validates the amount, tenor
+// and consumer against it.
+func checkProduct(ctx context.Context, productRepo repository.ProductRepository, consumer *model.Consumer, code string, otr money.Amount, tenor int) (*model.Product, error) {
+	if code == "" {
+		appErr := errors.New("product_code is required")
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+
+	product, err := productRepo.FindByCode(ctx, code)
+	if err != nil {
+		appErr := errors.New("failed to find product")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if product == nil {
+		appErr := errors.New("product not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+
+	if err := service.CheckProduct(product, consumer, otr, tenor, time.Now()); err != nil {
+		return nil, model.NewError(model.ErrBadRequest, err)
+	}
+
+	return product, nil
+}
@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/internal/app/repository"
+	"github.com/glennprays/xyz-fin/internal/app/service"
+	"github.com/glennprays/xyz-fin/pkg/money"
+)
+
+type RateCardUsecase interface {
+	Create(ctx context.Context, staffUsername string, req *model.CreateRateCardRequest) (*model.RateCard, error)
+	GetByID(ctx context.Context, id int64) (*model.RateCard, error)
+	List(ctx context.Context, filter model.RateCardFilter) ([]model.RateCard, error)
+}
+
+type rateCardUsecase struct {
+	db           *sql.DB
+	rateCardRepo repository.RateCardRepository
+	productRepo  repository.ProductRepository
+}
+
+func NewRateCardUsecase(db *sql.DB, rateCardRepo repository.RateCardRepository, productRepo repository.ProductRepository) RateCardUsecase {
+	return &rateCardUsecase{
+		db:           db,
+		rateCardRepo: rateCardRepo,
+		productRepo:  productRepo,
+	}
+}
+
+// Create adds a new version of the rate card for the product, tenor and
+// grade. The version in force until then is closed when the new one takes
+// effect, which may be scheduled for later but never back-dated.
+func (u *rateCardUsecase) Create(ctx context.Context, staffUsername string, req *model.CreateRateCardRequest) (*model.RateCard, error) {
+	switch req.RiskGrade {
+	case model.RiskGradeA, model.RiskGradeB, model.RiskGradeC:
+	default:
+		appErr := fmt.Errorf("unsupported risk_grade %q", req.RiskGrade)
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+	if req.InterestRate < 0 {
+		appErr := errors.New("interest_rate must not be negative")
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+	if err := service.ValidateFeeTiers(req.AdminFeeType, req.FeeTiers); err != nil {
+		return nil, model.NewError(model.ErrBadRequest, err)
+	}
+
+	now := time.Now()
+	effectiveFrom := now
+	if req.EffectiveFrom != nil {
+		if req.EffectiveFrom.Before(now) {
+			appErr := errors.New("effective_from must not be in the past")
+			return nil, model.NewError(model.ErrBadRequest, appErr)
+		}
+		effectiveFrom = *req.EffectiveFrom
+	}
+
+	product, err := u.productRepo.FindByCode(ctx, req.ProductCode)
+	if err != nil {
+		appErr := errors.New("failed to find product")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if product == nil {
+		appErr := errors.New("product not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+	if !slices.Contains(product.Tenors, req.Tenor) {
+		appErr := fmt.Errorf("tenor %d is not offered for product %s", req.Tenor, product.Code)
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		appErr := errors.New("failed to begin transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	defer tx.Rollback()
+
+	latest, err := u.rateCardRepo.FindLatestAndLock(ctx, tx, product.Code, req.Tenor, req.RiskGrade)
+	if err != nil {
+		appErr := errors.New("failed to find rate card")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	version := 1
+	if latest != nil {
+		if !effectiveFrom.After(latest.EffectiveFrom) {
+			appErr := fmt.Errorf("effective_from must be after version %d takes effect", latest.Version)
+			return nil, model.NewError(model.ErrConflict, appErr)
+		}
+		if latest.EffectiveTo == nil || latest.EffectiveTo.After(effectiveFrom) {
+			if err := u.rateCardRepo.Close(ctx, tx, latest.ID, effectiveFrom); err != nil {
+				appErr := errors.New("failed to close rate card")
+				return nil, model.NewError(model.ErrInternalFailure, appErr)
+			}
+		}
+		version = latest.Version + 1
+	}
+
+	card := &model.RateCard{
+		ProductCode:   product.Code,
+		Tenor:         req.Tenor,
+		RiskGrade:     req.RiskGrade,
+		Version:       version,
+		InterestRate:  req.InterestRate,
+		AdminFeeType:  req.AdminFeeType,
+		FeeTiers:      req.FeeTiers,
+		EffectiveFrom: effectiveFrom,
+		CreatedBy:     &staffUsername,
+	}
+	if err := u.rateCardRepo.Create(ctx, tx, card); err != nil {
+		appErr := errors.New("failed to create rate card")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	if err := tx.Commit(); err != nil {
+		appErr := errors.New("failed to commit transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	return card, nil
+}
+
+func (u *rateCardUsecase) GetByID(ctx context.Context, id int64) (*model.RateCard, error) {
+	card, err := u.rateCardRepo.FindByID(ctx, id)
+	if err != nil {
+		appErr := errors.New("failed to find rate card")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if card == nil {
+		appErr := errors.New("rate card not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+	return card, nil
+}
+
+func (u *rateCardUsecase) List(ctx context.Context, filter model.RateCardFilter) ([]model.RateCard, error) {
+	cards, err := u.rateCardRepo.List(ctx, filter)
+	if err != nil {
+		appErr := errors.New("failed to find rate cards")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	return cards, nil
+}
+
+// priceContract prices a contract with the rate card in force for the
+// product, tenor and the consumer's risk grade, using the product's interest
+// method.
+func priceContract(ctx context.Context, tx *sql.Tx, rateCardRepo repository.RateCardRepository, product *model.Product, consumer *model.Consumer, otr money.Amount, tenor int, at time.Time) (*model.Pricing, error) {
+	card, err := rateCardRepo.FindEffective(ctx, tx, product.Code, tenor, consumer.RiskGrade, at)
+	if err != nil {
+		appErr := errors.New("failed to find rate card")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if card == nil {
+		appErr := fmt.Errorf("no rate card in force for product %s, tenor %d and risk grade %s", product.Code, tenor, consumer.RiskGrade)
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+
+	pricing, err := service.PriceContract(card, product.InterestMethod, otr, tenor)
+	if err != nil {
+		return nil, model.NewError(model.ErrInternalFailure, err)
+	}
+	return &pricing, nil
+}
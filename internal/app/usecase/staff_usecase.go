@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/glennprays/xyz-fin/internal/app/model"
+	"github.com/glennprays/xyz-fin/internal/app/repository"
+	"github.com/glennprays/xyz-fin/pkg/auth"
+	"github.com/glennprays/xyz-fin/pkg/hasher"
+)
+
+// minStaffPasswordLength is the shortest password a provisioned staff
+// account may have.
+const minStaffPasswordLength = 12
+
+type StaffUsecase interface {
+	Login(ctx context.Context, username string, password string) (*model.AuthResponse, error)
+	Create(ctx context.Context, req *model.CreateStaffRequest) (*model.Staff, error)
+}
+
+type staffUsecase struct {
+	repo         repository.StaffRepository
+	merchantRepo repository.MerchantRepository
+	jwtManager   *auth.JWTManager
+	hasher       hasher.Argon2idHasher
+}
+
+func NewStaffUsecase(repo repository.StaffRepository, merchantRepo repository.MerchantRepository, jwtManager *auth.JWTManager, hasher hasher.Argon2idHasher) StaffUsecase {
+	return &staffUsecase{
+		repo:         repo,
+		merchantRepo: merchantRepo,
+		jwtManager:   jwtManager,
+		hasher:       hasher,
+	}
+}
+
+func (u *staffUsecase) Login(ctx context.Context, username string, password string) (*model.AuthResponse, error) {
+	staff, err := u.repo.FindByUsername(ctx, username)
+	if err != nil {
+		appErr := errors.New("failed to find staff")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if staff == nil {
+		appErr := errors.New("staff not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+
+	if !u.hasher.Check(password, staff.PasswordHash) {
+		appErr := errors.New("invalid password")
+		return nil, model.NewError(model.ErrUnauthorized, appErr)
+	}
+
+	var merchantID int64
+	if staff.MerchantID != nil {
+		merchantID = *staff.MerchantID
+	}
+
+	accessToken, refreshToken, err := u.jwtManager.GenerateStaffTokens(staff.Username, staff.Role, merchantID)
+	if err != nil {
+		appErr := fmt.Errorf("failed to generate authentication tokens: %w", err)
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	return &model.AuthResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// Create provisions a staff account. Staff accounts are never seeded by the
+// migrations, so every environment gets its own credentials.
+func (u *staffUsecase) Create(ctx context.Context, req *model.CreateStaffRequest) (*model.Staff, error) {
+	username := strings.TrimSpace(req.Username)
+	fullName := strings.TrimSpace(req.FullName)
+	merchantCode := strings.TrimSpace(req.MerchantCode)
+	switch {
+	case username == "":
+		return nil, model.NewError(model.ErrBadRequest, errors.New("username is required"))
+	case fullName == "":
+		return nil, model.NewError(model.ErrBadRequest, errors.New("full name is required"))
+	case len(req.Password) < minStaffPasswordLength:
+		appErr := fmt.Errorf("password must be at least %d characters", minStaffPasswordLength)
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+
+	switch req.Role {
+	case model.StaffRoleAdmin, model.StaffRoleCreditAnalyst:
+		if merchantCode != "" {
+			appErr := fmt.Errorf("a %s is not tied to a merchant", req.Role)
+			return nil, model.NewError(model.ErrBadRequest, appErr)
+		}
+	case model.StaffRoleMerchant:
+		if merchantCode == "" {
+			appErr := errors.New("merchant staff need a merchant")
+			return nil, model.NewError(model.ErrBadRequest, appErr)
+		}
+	default:
+		appErr := fmt.Errorf("unsupported role %q", req.Role)
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+
+	existing, err := u.repo.FindByUsername(ctx, username)
+	if err != nil {
+		appErr := errors.New("failed to find staff")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if existing != nil {
+		appErr := fmt.Errorf("staff %s already exists", username)
+		return nil, model.NewError(model.ErrConflict, appErr)
+	}
+
+	staff := &model.Staff{
+		Username: username,
+		FullName: fullName,
+		Role:     req.Role,
+	}
+
+	if merchantCode != "" {
+		merchant, err := u.merchantRepo.FindByCode(ctx, merchantCode)
+		if err != nil {
+			appErr := errors.New("failed to find merchant")
+			return nil, model.NewError(model.ErrInternalFailure, appErr)
+		}
+		if merchant == nil {
+			appErr := fmt.Errorf("merchant %s not found", merchantCode)
+			return nil, model.NewError(model.ErrNotFound, appErr)
+		}
+		staff.MerchantID = &merchant.ID
+	}
+
+	staff.PasswordHash, err = u.hasher.Hash(req.Password)
+	if err != nil {
+		appErr := errors.New("failed to hash password")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	if err := u.repo.Create(ctx, staff); err != nil {
+		appErr := errors.New("failed to save staff")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	return staff, nil
+}
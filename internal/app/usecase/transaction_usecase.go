@@ -3,54 +3,119 @@ package usecase
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/glennprays/xyz-fin/internal/app/model"
 	"github.com/glennprays/xyz-fin/internal/app/repository"
 	"github.com/glennprays/xyz-fin/internal/app/service"
+	"github.com/glennprays/xyz-fin/pkg/money"
+	"github.com/glennprays/xyz-fin/pkg/pagination"
+	"github.com/glennprays/xyz-fin/pkg/quote"
 )
 
 type TransactionUsecase interface {
-	CreateTransaction(ctx context.Context, phoneNumber string, req *model.TransactionRequest) (*model.TransactionResponse, error)
+	CreateTransaction(ctx context.Context, phoneNumber string, idempotencyKey string, req *model.TransactionRequest) (*model.TransactionResponse, error)
+	CaptureHold(ctx context.Context, principal model.Principal, holdID int64, req *model.CaptureLimitHoldRequest) (*model.TransactionResponse, error)
+	GetSchedule(ctx context.Context, phoneNumber string, nomorKontrak string) (*model.InstallmentSchedule, error)
+	ListForConsumer(ctx context.Context, phoneNumber string, req *model.TransactionHistoryRequest) (*model.TransactionHistoryResponse, error)
+	ListForMerchant(ctx context.Context, principal model.Principal, req *model.TransactionHistoryRequest) (*model.TransactionHistoryResponse, error)
+	GetDetail(ctx context.Context, principal model.Principal, nomorKontrak string) (*model.TransactionDetail, error)
+	Simulate(ctx context.Context, phoneNumber string, req *model.QuoteRequest) (*model.Quote, error)
 }
 
 type transactionUsecase struct {
 	db                 *sql.DB
-	transactionService service.TransactionService
+	contractNumbers    *service.ContractNumberGenerator
 	transactionRepo    repository.TransactionRepository
 	consumerRepo       repository.ConsumerRepository
 	consumerLimitRepo  repository.ConsumerLimitRepository
+	limitHoldRepo      repository.LimitHoldRepository
+	productRepo        repository.ProductRepository
+	rateCardRepo       repository.RateCardRepository
+	installmentRepo    repository.InstallmentRepository
+	transitionRepo     repository.TransactionStatusTransitionRepository
+	idempotencyKeyRepo repository.IdempotencyKeyRepository
+	paymentRepo        repository.PaymentRepository
+	merchantRepo       repository.MerchantRepository
+	outletRepo         repository.MerchantOutletRepository
+	limitPolicies      service.LimitPolicyResolver
+	quoteSigner        *quote.Signer
 }
 
 func NewTransactionUsecase(
 	db *sql.DB,
-	transactionService service.TransactionService,
+	contractNumbers *service.ContractNumberGenerator,
 	transactionRepo repository.TransactionRepository,
 	consumerRepo repository.ConsumerRepository,
 	consumerLimitRepo repository.ConsumerLimitRepository,
+	limitHoldRepo repository.LimitHoldRepository,
+	productRepo repository.ProductRepository,
+	rateCardRepo repository.RateCardRepository,
+	installmentRepo repository.InstallmentRepository,
+	transitionRepo repository.TransactionStatusTransitionRepository,
+	idempotencyKeyRepo repository.IdempotencyKeyRepository,
+	paymentRepo repository.PaymentRepository,
+	merchantRepo repository.MerchantRepository,
+	outletRepo repository.MerchantOutletRepository,
+	limitPolicies service.LimitPolicyResolver,
+	quoteSigner *quote.Signer,
 ) TransactionUsecase {
 	return &transactionUsecase{
 		db:                 db,
-		transactionService: transactionService,
+		contractNumbers:    contractNumbers,
 		transactionRepo:    transactionRepo,
 		consumerRepo:       consumerRepo,
 		consumerLimitRepo:  consumerLimitRepo,
+		limitHoldRepo:      limitHoldRepo,
+		productRepo:        productRepo,
+		rateCardRepo:       rateCardRepo,
+		installmentRepo:    installmentRepo,
+		transitionRepo:     transitionRepo,
+		idempotencyKeyRepo: idempotencyKeyRepo,
+		paymentRepo:        paymentRepo,
+		merchantRepo:       merchantRepo,
+		outletRepo:         outletRepo,
+		limitPolicies:      limitPolicies,
+		quoteSigner:        quoteSigner,
 	}
 }
 
-func (u *transactionUsecase) CreateTransaction(ctx context.Context, phoneNumber string, req *model.TransactionRequest) (*model.TransactionResponse, error) {
+// CreateTransaction opens a contract. With an idempotency key, a retry of
+// the same request replays the response of the first one instead of opening
+// a second contract; the key is only kept if the contract is.
+func (u *transactionUsecase) CreateTransaction(ctx context.Context, phoneNumber string, idempotencyKey string, req *model.TransactionRequest) (*model.TransactionResponse, error) {
+	principal, _, err := consumerPrincipal(ctx, u.consumerRepo, phoneNumber)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(principal, service.ActionCreateContract, service.Resource{OwnerNIK: req.ConsumerNIK}, "consumer not found"); err != nil {
+		return nil, err
+	}
+
 	tx, err := u.db.BeginTx(ctx, nil)
 	if err != nil {
 		appErr := errors.New("failed to begin transaction")
 		return nil, model.NewError(model.ErrInternalFailure, appErr)
 	}
-	defer func() {
+	defer tx.Rollback()
+
+	if idempotencyKey != "" {
+		previous, err := claimIdempotencyKey(ctx, tx, u.idempotencyKeyRepo, phoneNumber, idempotencyKey, req)
 		if err != nil {
-			tx.Rollback()
-		} else {
-			err = tx.Commit()
+			return nil, err
 		}
-	}()
+		if previous != nil {
+			var response model.TransactionResponse
+			if err := json.Unmarshal(previous.ResponseBody, &response); err != nil {
+				appErr := errors.New("failed to decode stored response")
+				return nil, model.NewError(model.ErrInternalFailure, appErr)
+			}
+			return &response, nil
+		}
+	}
 
 	consumer, err := u.consumerRepo.FindAndLockByNIK(ctx, tx, req.ConsumerNIK)
 	if err != nil {
@@ -62,44 +127,163 @@ func (u *transactionUsecase) CreateTransaction(ctx context.Context, phoneNumber
 		return nil, model.NewError(model.ErrNotFound, appErr)
 	}
 
-	if req.ConsumerNIK != consumer.NIK {
-		appErr := errors.New("consumer NIK does not match")
-		return nil, model.NewError(model.ErrBadRequest, appErr)
+	transaction, err := u.openContract(ctx, tx, consumer, req)
+	if err != nil {
+		return nil, err
+	}
+
+	response := toTransactionResponse(transaction)
+	if idempotencyKey != "" {
+		if err := saveIdempotentResponse(ctx, tx, u.idempotencyKeyRepo, phoneNumber, idempotencyKey, response); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		appErr := errors.New("failed to commit transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
 	}
 
-	consumerLimits, err := u.consumerLimitRepo.FindByNIKAndTenor(ctx, tx, req.ConsumerNIK, req.Tenor)
+	return response, nil
+}
+
+// CaptureHold converts an active limit hold into a contract. The hold is
+// closed before the limit check so its own amount is not counted twice. A
+// hold placed by a merchant can only be captured by that merchant's staff or
+// the consumer, and the contract is always opened at that merchant.
+func (u *transactionUsecase) CaptureHold(ctx context.Context, principal model.Principal, holdID int64, req *model.CaptureLimitHoldRequest) (*model.TransactionResponse, error) {
+	tx, err := u.db.BeginTx(ctx, nil)
 	if err != nil {
-		appErr := errors.New("failed to find consumer limits")
+		appErr := errors.New("failed to begin transaction")
 		return nil, model.NewError(model.ErrInternalFailure, appErr)
 	}
-	if consumerLimits == nil {
-		appErr := errors.New("consumer limits not found")
+	defer tx.Rollback()
+
+	hold, err := u.limitHoldRepo.FindByID(ctx, holdID)
+	if err != nil {
+		appErr := errors.New("failed to find limit hold")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if hold == nil {
+		appErr := errors.New("limit hold not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+
+	principal, _, err = resolvePrincipal(ctx, u.consumerRepo, principal)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(principal, service.ActionHoldLimit, holdResource(hold), "limit hold not found"); err != nil {
+		return nil, err
+	}
+
+	merchantID := req.MerchantID
+	if hold.MerchantID != nil {
+		if merchantID != 0 && merchantID != *hold.MerchantID {
+			appErr := errors.New("limit hold was placed by another merchant")
+			return nil, model.NewError(model.ErrBadRequest, appErr)
+		}
+		merchantID = *hold.MerchantID
+	}
+
+	// Lock the consumer before the hold, the same order every limit writer uses.
+	consumer, err := u.consumerRepo.FindAndLockByNIK(ctx, tx, hold.ConsumerNIK)
+	if err != nil {
+		appErr := errors.New("failed to find consumer")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if consumer == nil {
+		appErr := errors.New("limit hold not found")
 		return nil, model.NewError(model.ErrNotFound, appErr)
 	}
 
-	activeSUM, err := u.transactionRepo.GetActiveTransactionSumByNIK(ctx, tx, consumer.NIK)
+	hold, err = u.limitHoldRepo.FindAndLockByID(ctx, tx, holdID)
 	if err != nil {
-		appErr := errors.New("failed to get active transaction sum")
+		appErr := errors.New("failed to find limit hold")
 		return nil, model.NewError(model.ErrInternalFailure, appErr)
 	}
+	if hold == nil {
+		appErr := errors.New("limit hold not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+	if hold.Status != model.LimitHoldStatusActive || hold.Expired {
+		appErr := errors.New("limit hold is no longer active")
+		return nil, model.NewError(model.ErrConflict, appErr)
+	}
 
-	if activeSUM+req.OTR > consumerLimits.LimitAmount {
-		appErr := errors.New("transaction exceeds limit")
-		return nil, model.NewError(model.ErrBadRequest, appErr)
+	hold.Status = model.LimitHoldStatusCaptured
+	if err := u.limitHoldRepo.UpdateStatus(ctx, tx, hold); err != nil {
+		appErr := errors.New("failed to update limit hold")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	transaction, err := u.openContract(ctx, tx, consumer, &model.TransactionRequest{
+		ProductCode: hold.ProductCode,
+		ConsumerNIK: hold.ConsumerNIK,
+		OTR:         hold.Amount,
+		Tenor:       hold.Tenor,
+		NamaAsset:   req.NamaAsset,
+		MerchantID:  merchantID,
+		OutletID:    req.OutletID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	hold.NomorKontrak = &transaction.NomorKontrak
+	if err := u.limitHoldRepo.UpdateStatus(ctx, tx, hold); err != nil {
+		appErr := errors.New("failed to update limit hold")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	if err := tx.Commit(); err != nil {
+		appErr := errors.New("failed to commit transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	return toTransactionResponse(transaction), nil
+}
+
+// openContract checks the request against the merchant, the product and the
+// consumer's limit and saves a new contract with its installment schedule.
+// The caller must hold the consumer row lock in tx.
+func (u *transactionUsecase) openContract(ctx context.Context, tx *sql.Tx, consumer *model.Consumer, req *model.TransactionRequest) (*model.Transaction, error) {
+	merchant, _, err := checkMerchantOrigin(ctx, tx, u.merchantRepo, u.outletRepo, req.MerchantID, req.OutletID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	product, pricing, err := u.assessContract(ctx, tx, consumer, req, now)
+	if err != nil {
+		return nil, err
+	}
+
+	sequence, err := u.transactionRepo.NextContractSequence(ctx, tx)
+	if err != nil {
+		appErr := errors.New("failed to generate nomor_kontrak")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	nomorKontrak, err := u.contractNumbers.Generate(product.ContractPrefix, now, sequence)
+	if err != nil {
+		appErr := errors.New("failed to generate nomor_kontrak")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
 	}
 
-	transactionID := u.transactionService.GenerateTransactionID()
-	adminFee := req.OTR * 0.03
-	jumlahBunga := req.OTR * 0.05
 	transaction := &model.Transaction{
-		NomorKontrak:  transactionID,
-		ConsumerNIK:   req.ConsumerNIK,
+		NomorKontrak:  nomorKontrak,
+		ConsumerNIK:   consumer.NIK,
 		OTR:           req.OTR,
-		AdminFee:      adminFee,
-		JumlahBunga:   jumlahBunga,
+		AdminFee:      pricing.AdminFee,
+		JumlahBunga:   pricing.JumlahBunga,
 		JumlahCicilan: req.Tenor,
 		NamaAsset:     req.NamaAsset,
-		Status:        "ACTIVE",
+		Status:        model.TransactionStatusPendingDisbursement,
+		ProductCode:   product.Code,
+		RateCardID:    &pricing.RateCardID,
+		MerchantID:    &merchant.ID,
+		OutletID:      req.OutletID,
+		MDRAmount:     service.CalculateMDR(req.OTR, merchant.MDRRate),
 	}
 	err = u.transactionRepo.Save(ctx, tx, transaction)
 	if err != nil {
@@ -107,8 +291,401 @@ func (u *transactionUsecase) CreateTransaction(ctx context.Context, phoneNumber
 		return nil, model.NewError(model.ErrInternalFailure, appErr)
 	}
 
-	transactionResponse := &model.TransactionResponse{
+	installments := service.BuildInstallmentSchedule(*pricing, now)
+	for i := range installments {
+		installments[i].NomorKontrak = transaction.NomorKontrak
+	}
+	if err := u.installmentRepo.SaveAll(ctx, tx, installments); err != nil {
+		appErr := errors.New("failed to save installment schedule")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	err = u.transitionRepo.Create(ctx, tx, &model.TransactionStatusTransition{
+		NomorKontrak: transaction.NomorKontrak,
+		ToStatus:     transaction.Status,
+		Reason:       "contract opened",
+		ChangedBy:    consumer.NIK,
+	})
+	if err != nil {
+		appErr := errors.New("failed to record status transition")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	return transaction, nil
+}
+
+// assessContract runs the product, limit and pricing checks for a contract
+// without writing anything. A quote ID in the request prices the contract
+// with the quoted rate card instead of the one in force now.
+func (u *transactionUsecase) assessContract(ctx context.Context, tx *sql.Tx, consumer *model.Consumer, req *model.TransactionRequest, now time.Time) (*model.Product, *model.Pricing, error) {
+	product, err := checkProduct(ctx, u.productRepo, consumer, req.ProductCode, req.OTR, req.Tenor)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	consumerLimits, err := u.consumerLimitRepo.FindByNIKAndTenor(ctx, tx, consumer.NIK, req.Tenor)
+	if err != nil {
+		appErr := errors.New("failed to find consumer limits")
+		return nil, nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if consumerLimits == nil {
+		appErr := errors.New("consumer limits not found")
+		return nil, nil, model.NewError(model.ErrNotFound, appErr)
+	}
+
+	allLimits, err := u.consumerLimitRepo.FindByNIKInTx(ctx, tx, consumer.NIK)
+	if err != nil {
+		appErr := errors.New("failed to find consumer limits")
+		return nil, nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	usages, err := loadLimitUsage(ctx, tx, u.transactionRepo, u.limitHoldRepo, consumer.NIK)
+	if err != nil {
+		appErr := errors.New("failed to get limit usage")
+		return nil, nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	if _, err := service.CheckLimit(u.limitPolicies, req.ProductCode, *consumerLimits, allLimits, usages, req.OTR); err != nil {
+		appErr := errors.New("transaction exceeds limit")
+		return nil, nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+
+	var pricing *model.Pricing
+	if req.QuoteID != "" {
+		pricing, err = u.quotedPricing(ctx, consumer, product, req)
+	} else {
+		pricing, err = priceContract(ctx, tx, u.rateCardRepo, product, consumer, req.OTR, req.Tenor, now)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return product, pricing, nil
+}
+
+// quotedPricing prices the contract with the rate card a quote was issued
+// with, so a rate change after the quote does not alter the offer. The quote
+// must still be valid and match the request exactly.
+func (u *transactionUsecase) quotedPricing(ctx context.Context, consumer *model.Consumer, product *model.Product, req *model.TransactionRequest) (*model.Pricing, error) {
+	claims, err := u.quoteSigner.Verify(req.QuoteID)
+	if err != nil {
+		if errors.Is(err, quote.ErrExpired) {
+			appErr := errors.New("quote has expired")
+			return nil, model.NewError(model.ErrBadRequest, appErr)
+		}
+		appErr := errors.New("invalid quote_id")
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+	if claims.ConsumerNIK != consumer.NIK || claims.ProductCode != product.Code || claims.OTR != req.OTR || claims.Tenor != req.Tenor {
+		appErr := errors.New("quote does not match the transaction")
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+
+	card, err := u.rateCardRepo.FindByID(ctx, claims.RateCardID)
+	if err != nil {
+		appErr := errors.New("failed to find rate card")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if card == nil {
+		appErr := errors.New("invalid quote_id")
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+
+	pricing, err := service.PriceContract(card, product.InterestMethod, req.OTR, req.Tenor)
+	if err != nil {
+		return nil, model.NewError(model.ErrInternalFailure, err)
+	}
+	if pricing.AdminFee != claims.AdminFee || pricing.JumlahBunga != claims.JumlahBunga {
+		appErr := errors.New("quote can no longer be honored")
+		return nil, model.NewError(model.ErrConflict, appErr)
+	}
+
+	return &pricing, nil
+}
+
+// Simulate runs the same checks as CreateTransaction in a read-only
+// transaction and returns a signed quote instead of opening a contract.
+func (u *transactionUsecase) Simulate(ctx context.Context, phoneNumber string, req *model.QuoteRequest) (*model.Quote, error) {
+	consumer, err := u.consumerRepo.FindByPhoneNumber(ctx, phoneNumber)
+	if err != nil {
+		appErr := errors.New("failed to find consumer")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if consumer == nil {
+		appErr := errors.New("consumer not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+
+	tx, err := u.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		appErr := errors.New("failed to begin transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	product, pricing, err := u.assessContract(ctx, tx, consumer, &model.TransactionRequest{
+		ProductCode: req.ProductCode,
+		ConsumerNIK: consumer.NIK,
+		OTR:         req.OTR,
+		Tenor:       req.Tenor,
+	}, now)
+	if err != nil {
+		return nil, err
+	}
+
+	installments := service.BuildInstallmentSchedule(*pricing, now)
+	quoteID, expiresAt := u.quoteSigner.Sign(quote.Claims{
+		ConsumerNIK: consumer.NIK,
+		ProductCode: product.Code,
+		OTR:         req.OTR,
+		Tenor:       req.Tenor,
+		RateCardID:  pricing.RateCardID,
+		AdminFee:    pricing.AdminFee,
+		JumlahBunga: pricing.JumlahBunga,
+	})
+
+	return &model.Quote{
+		QuoteID:            quoteID,
+		ExpiresAt:          expiresAt,
+		ProductCode:        product.Code,
+		OTR:                req.OTR,
+		Tenor:              req.Tenor,
+		RateCardID:         pricing.RateCardID,
+		InterestMethod:     pricing.InterestMethod,
+		InterestRate:       pricing.InterestRate,
+		AdminFee:           pricing.AdminFee,
+		JumlahBunga:        pricing.JumlahBunga,
+		MonthlyInstallment: installments[0].TotalAmount,
+		TotalPayable:       req.OTR + pricing.JumlahBunga + pricing.AdminFee,
+		APR:                pricing.APR,
+		EIR:                pricing.EIR,
+		Installments:       installments,
+	}, nil
+}
+
+// GetSchedule returns the installment schedule of one of the consumer's
+// contracts. Contracts of other consumers are reported as not found.
+func (u *transactionUsecase) GetSchedule(ctx context.Context, phoneNumber string, nomorKontrak string) (*model.InstallmentSchedule, error) {
+	if err := checkNomorKontrak(nomorKontrak); err != nil {
+		return nil, err
+	}
+
+	principal, _, err := consumerPrincipal(ctx, u.consumerRepo, phoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction, err := u.transactionRepo.FindByNomorKontrak(ctx, nomorKontrak)
+	if err != nil {
+		appErr := errors.New("failed to find transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if transaction == nil {
+		appErr := errors.New("transaction not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+	if err := authorize(principal, service.ActionViewContract, contractResource(transaction), "transaction not found"); err != nil {
+		return nil, err
+	}
+
+	installments, err := u.installmentRepo.FindByNomorKontrak(ctx, nomorKontrak)
+	if err != nil {
+		appErr := errors.New("failed to find installment schedule")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if len(installments) == 0 {
+		appErr := errors.New("installment schedule not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+
+	schedule := &model.InstallmentSchedule{
+		NomorKontrak: nomorKontrak,
+		Installments: installments,
+	}
+	for _, installment := range installments {
+		schedule.TotalPrincipal += installment.Principal
+		schedule.TotalInterest += installment.Interest
+		schedule.TotalAdminFee += installment.AdminFee
+		schedule.TotalAmount += installment.TotalAmount
+	}
+	return schedule, nil
+}
+
+// ListForConsumer returns one page of the consumer's contract history, newest
+// first unless another order is asked for.
+func (u *transactionUsecase) ListForConsumer(ctx context.Context, phoneNumber string, req *model.TransactionHistoryRequest) (*model.TransactionHistoryResponse, error) {
+	consumer, err := u.consumerRepo.FindByPhoneNumber(ctx, phoneNumber)
+	if err != nil {
+		appErr := errors.New("failed to find consumer")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if consumer == nil {
+		appErr := errors.New("consumer not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+
+	return u.listTransactions(ctx, model.TransactionHistoryFilter{ConsumerNIK: consumer.NIK}, req)
+}
+
+// ListForMerchant returns one page of the contracts originated by the
+// merchant of the calling staff member, newest first unless another order is
+// asked for.
+func (u *transactionUsecase) ListForMerchant(ctx context.Context, principal model.Principal, req *model.TransactionHistoryRequest) (*model.TransactionHistoryResponse, error) {
+	if err := authorize(principal, service.ActionListContracts, service.Resource{MerchantID: principal.MerchantID}, "merchant not found"); err != nil {
+		return nil, err
+	}
+
+	return u.listTransactions(ctx, model.TransactionHistoryFilter{MerchantID: principal.MerchantID}, req)
+}
+
+// listTransactions applies the request's filters, order and cursor to filter,
+// which already scopes the search to a consumer or a merchant.
+func (u *transactionUsecase) listTransactions(ctx context.Context, filter model.TransactionHistoryFilter, req *model.TransactionHistoryRequest) (*model.TransactionHistoryResponse, error) {
+	filter.Status = req.Status
+	filter.CreatedFrom = req.CreatedFrom
+	filter.Asset = req.Asset
+	filter.SortBy = req.SortBy
+	filter.SortOrder = req.SortOrder
+	filter.Limit = pagination.NormalizeLimit(req.Limit)
+
+	if req.CreatedTo != nil {
+		createdTo := req.CreatedTo.AddDate(0, 0, 1)
+		filter.CreatedTo = &createdTo
+	}
+
+	if filter.Status != "" && !service.IsContractStatus(filter.Status) {
+		appErr := fmt.Errorf("unsupported status %q", req.Status)
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+
+	switch filter.SortBy {
+	case "":
+		filter.SortBy = model.TransactionSortByCreatedAt
+	case model.TransactionSortByCreatedAt, model.TransactionSortByOTR:
+	default:
+		appErr := fmt.Errorf("unsupported sort_by %q", req.SortBy)
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+
+	switch filter.SortOrder {
+	case "":
+		filter.SortOrder = model.SortOrderDesc
+	case model.SortOrderAsc, model.SortOrderDesc:
+	default:
+		appErr := fmt.Errorf("unsupported sort_order %q", req.SortOrder)
+		return nil, model.NewError(model.ErrBadRequest, appErr)
+	}
+
+	cursor, err := pagination.Decode(req.Cursor)
+	if err != nil {
+		return nil, model.NewError(model.ErrBadRequest, err)
+	}
+	if cursor != nil {
+		if filter.SortBy == model.TransactionSortByOTR {
+			_, err = money.Parse(cursor.SortValue)
+		} else {
+			_, err = time.Parse(consumerCursorTimeLayout, cursor.SortValue)
+		}
+		if err != nil {
+			return nil, model.NewError(model.ErrBadRequest, pagination.ErrInvalidCursor)
+		}
+	}
+	filter.Cursor = cursor
+
+	// Fetch one extra row to know whether another page exists.
+	requested := filter.Limit
+	filter.Limit++
+
+	transactions, err := u.transactionRepo.Search(ctx, filter)
+	if err != nil {
+		appErr := errors.New("failed to list transactions")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	response := &model.TransactionHistoryResponse{Data: transactions}
+	if len(transactions) > requested {
+		response.Data = transactions[:requested]
+		last := response.Data[requested-1]
+		next := pagination.Cursor{Key: last.NomorKontrak, SortValue: last.CreatedAt.Format(consumerCursorTimeLayout)}
+		if filter.SortBy == model.TransactionSortByOTR {
+			next.SortValue = last.OTR.String()
+		}
+		response.NextCursor = pagination.Encode(next)
+	}
+
+	return response, nil
+}
+
+// GetDetail returns a contract with its schedule summary, payments and status
+// history. Consumers only see their own contracts and merchants the ones they
+// originated; other contracts are reported as not found.
+func (u *transactionUsecase) GetDetail(ctx context.Context, principal model.Principal, nomorKontrak string) (*model.TransactionDetail, error) {
+	if err := checkNomorKontrak(nomorKontrak); err != nil {
+		return nil, err
+	}
+
+	principal, _, err := resolvePrincipal(ctx, u.consumerRepo, principal)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction, err := u.transactionRepo.FindByNomorKontrak(ctx, nomorKontrak)
+	if err != nil {
+		appErr := errors.New("failed to find transaction")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+	if transaction == nil {
+		appErr := errors.New("transaction not found")
+		return nil, model.NewError(model.ErrNotFound, appErr)
+	}
+	if err := authorize(principal, service.ActionViewContract, contractResource(transaction), "transaction not found"); err != nil {
+		return nil, err
+	}
+
+	installments, err := u.installmentRepo.FindByNomorKontrak(ctx, nomorKontrak)
+	if err != nil {
+		appErr := errors.New("failed to find installment schedule")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	payments, err := u.paymentRepo.FindByNomorKontrak(ctx, nomorKontrak)
+	if err != nil {
+		appErr := errors.New("failed to find payments")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	transitions, err := u.transitionRepo.FindByNomorKontrak(ctx, nomorKontrak)
+	if err != nil {
+		appErr := errors.New("failed to find status history")
+		return nil, model.NewError(model.ErrInternalFailure, appErr)
+	}
+
+	detail := &model.TransactionDetail{
+		Transaction:   *transaction,
+		Schedule:      service.SummarizeSchedule(installments, time.Now()),
+		Payments:      payments,
+		StatusHistory: transitions,
+	}
+	if principal.IsStaff() {
+		detail.MDRAmount = &transaction.MDRAmount
+	}
+	return detail, nil
+}
+
+// contractResource describes a contract for the access policy.
+func contractResource(transaction *model.Transaction) service.Resource {
+	resource := service.Resource{OwnerNIK: transaction.ConsumerNIK}
+	if transaction.MerchantID != nil {
+		resource.MerchantID = *transaction.MerchantID
+	}
+	return resource
+}
+
+func toTransactionResponse(transaction *model.Transaction) *model.TransactionResponse {
+	return &model.TransactionResponse{
 		NomorKontrak:  transaction.NomorKontrak,
+		ProductCode:   transaction.ProductCode,
 		ConsumerNIK:   transaction.ConsumerNIK,
 		OTR:           transaction.OTR,
 		AdminFee:      transaction.AdminFee,
@@ -116,7 +693,8 @@ func (u *transactionUsecase) CreateTransaction(ctx context.Context, phoneNumber
 		JumlahBunga:   transaction.JumlahBunga,
 		NamaAsset:     transaction.NamaAsset,
 		Status:        transaction.Status,
+		RateCardID:    transaction.RateCardID,
+		MerchantID:    transaction.MerchantID,
+		OutletID:      transaction.OutletID,
 	}
-
-	return transactionResponse, nil
 }
@@ -21,3 +21,31 @@ func GetUserPhoneNumberFromContext(c *gin.Context) (string, error) {
 
 	return phoneNumberStr, nil
 }
+
+func GetStaffUsernameFromContext(c *gin.Context) (string, error) {
+	username, exists := c.Get(middleware.ContextStaffUsername)
+	if !exists {
+		return "", model.NewError(model.ErrUnauthorized, errors.New("staff username not found in context (unauthorized)"))
+	}
+
+	usernameStr, ok := username.(string)
+	if !ok {
+		return "", model.NewError(model.ErrUnauthorized, errors.New("staff username is not a string"))
+	}
+
+	return usernameStr, nil
+}
+
+// GetPrincipalFromContext returns the authenticated caller, consumer or staff.
+func GetPrincipalFromContext(c *gin.Context) (model.Principal, error) {
+	principal := model.Principal{
+		PhoneNumber:   c.GetString(middleware.ContextUserPhoneNumber),
+		StaffUsername: c.GetString(middleware.ContextStaffUsername),
+		Role:          c.GetString(middleware.ContextUserRole),
+		MerchantID:    c.GetInt64(middleware.ContextMerchantID),
+	}
+	if principal.PhoneNumber == "" && principal.StaffUsername == "" {
+		return model.Principal{}, model.NewError(model.ErrUnauthorized, errors.New("principal not found in context (unauthorized)"))
+	}
+	return principal, nil
+}
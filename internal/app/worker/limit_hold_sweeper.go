@@ -0,0 +1,45 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/glennprays/xyz-fin/internal/app/usecase"
+)
+
+// LimitHoldSweeper periodically marks limit holds past their expiry as
+// expired so they no longer show up as active.
+type LimitHoldSweeper struct {
+	limitHoldUsecase usecase.LimitHoldUsecase
+	interval         time.Duration
+}
+
+func NewLimitHoldSweeper(limitHoldUsecase usecase.LimitHoldUsecase, interval time.Duration) *LimitHoldSweeper {
+	return &LimitHoldSweeper{
+		limitHoldUsecase: limitHoldUsecase,
+		interval:         interval,
+	}
+}
+
+func (w *LimitHoldSweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("limit hold sweeper stopped.")
+			return
+		case <-ticker.C:
+			expired, err := w.limitHoldUsecase.ExpireHolds(ctx)
+			if err != nil {
+				log.Printf("limit hold sweeper: %v", err)
+				continue
+			}
+			if expired > 0 {
+				log.Printf("limit hold sweeper: expired %d holds", expired)
+			}
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/glennprays/xyz-fin/internal/app/usecase"
+)
+
+// PenaltyAccrualJob periodically charges late-payment penalties on overdue
+// installments. Penalties accrue per calendar day, so running it more often
+// than daily only picks up new overdue installments sooner.
+type PenaltyAccrualJob struct {
+	penaltyUsecase usecase.PenaltyUsecase
+	interval       time.Duration
+}
+
+func NewPenaltyAccrualJob(penaltyUsecase usecase.PenaltyUsecase, interval time.Duration) *PenaltyAccrualJob {
+	return &PenaltyAccrualJob{
+		penaltyUsecase: penaltyUsecase,
+		interval:       interval,
+	}
+}
+
+func (w *PenaltyAccrualJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("penalty accrual job stopped.")
+			return
+		case <-ticker.C:
+			charged, err := w.penaltyUsecase.AccrueOverdue(ctx, time.Now())
+			if err != nil {
+				log.Printf("penalty accrual job: %v", err)
+			}
+			if charged > 0 {
+				log.Printf("penalty accrual job: made %d penalty charges", charged)
+			}
+		}
+	}
+}
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS staffs;
+
+DROP INDEX IF EXISTS idx_consumers_kyc_status_created_at;
+DROP INDEX IF EXISTS idx_consumers_created_at_nik;
+DROP INDEX IF EXISTS idx_consumers_full_name_prefix;
+
+ALTER TABLE consumers DROP COLUMN IF EXISTS kyc_status;
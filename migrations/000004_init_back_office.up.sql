@@ -0,0 +1,19 @@
+ALTER TABLE consumers ADD COLUMN kyc_status VARCHAR(20) NOT NULL DEFAULT 'PENDING';
+
+UPDATE consumers SET kyc_status = 'VERIFIED' WHERE nik IN ('1111', '2222');
+
+CREATE INDEX idx_consumers_full_name_prefix ON consumers (LOWER(full_name) text_pattern_ops);
+CREATE INDEX idx_consumers_created_at_nik ON consumers (created_at, nik);
+CREATE INDEX idx_consumers_kyc_status_created_at ON consumers (kyc_status, created_at, nik);
+
+CREATE TABLE staffs (
+    id BIGSERIAL PRIMARY KEY,
+    username VARCHAR(100) UNIQUE NOT NULL,
+    password_hash TEXT NOT NULL,
+    full_name VARCHAR(255) NOT NULL,
+    role VARCHAR(30) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TRIGGER update_staffs_timestamp BEFORE UPDATE ON staffs FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS limit_change_request_items;
+DROP TABLE IF EXISTS limit_change_requests;
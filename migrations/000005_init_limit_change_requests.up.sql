@@ -0,0 +1,26 @@
+CREATE TABLE limit_change_requests (
+    id BIGSERIAL PRIMARY KEY,
+    consumer_nik VARCHAR(16) REFERENCES consumers(nik) ON DELETE CASCADE NOT NULL,
+    reason TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    requested_by VARCHAR(100) REFERENCES staffs(username) NOT NULL,
+    decided_by VARCHAR(100) REFERENCES staffs(username),
+    decision_note TEXT,
+    decided_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_limit_change_requests_maker_checker CHECK (decided_by IS NULL OR decided_by <> requested_by)
+);
+
+CREATE INDEX idx_limit_change_requests_status ON limit_change_requests (status, created_at);
+CREATE INDEX idx_limit_change_requests_consumer_nik ON limit_change_requests (consumer_nik);
+
+CREATE TABLE limit_change_request_items (
+    request_id BIGINT REFERENCES limit_change_requests(id) ON DELETE CASCADE NOT NULL,
+    tenor INT NOT NULL,
+    current_amount NUMERIC(15, 2),
+    requested_amount NUMERIC(15, 2) NOT NULL,
+    PRIMARY KEY (request_id, tenor)
+);
+
+CREATE TRIGGER update_limit_change_requests_timestamp BEFORE UPDATE ON limit_change_requests FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
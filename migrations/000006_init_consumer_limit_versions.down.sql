@@ -0,0 +1,3 @@
+ALTER TABLE limit_change_requests DROP COLUMN IF EXISTS source;
+
+DROP TABLE IF EXISTS consumer_limit_versions;
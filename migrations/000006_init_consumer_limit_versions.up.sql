@@ -0,0 +1,20 @@
+CREATE TABLE consumer_limit_versions (
+    id BIGSERIAL PRIMARY KEY,
+    consumer_nik VARCHAR(16) REFERENCES consumers(nik) ON DELETE CASCADE NOT NULL,
+    tenor INT NOT NULL,
+    limit_amount NUMERIC(15, 2) NOT NULL,
+    source VARCHAR(20) NOT NULL,
+    source_reference VARCHAR(100),
+    valid_from TIMESTAMP NOT NULL,
+    valid_to TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_consumer_limit_versions_validity CHECK (valid_to IS NULL OR valid_to >= valid_from)
+);
+
+CREATE INDEX idx_consumer_limit_versions_lookup ON consumer_limit_versions (consumer_nik, tenor, valid_from);
+CREATE UNIQUE INDEX idx_consumer_limit_versions_current ON consumer_limit_versions (consumer_nik, tenor) WHERE valid_to IS NULL;
+
+INSERT INTO consumer_limit_versions (consumer_nik, tenor, limit_amount, source, valid_from)
+SELECT consumer_nik, tenor, limit_amount, 'MANUAL', created_at FROM consumer_limits;
+
+ALTER TABLE limit_change_requests ADD COLUMN source VARCHAR(20) NOT NULL DEFAULT 'MANUAL';
@@ -0,0 +1,18 @@
+CREATE TABLE limit_holds (
+    id BIGSERIAL PRIMARY KEY,
+    consumer_nik VARCHAR(16) REFERENCES consumers(nik) ON DELETE CASCADE NOT NULL,
+    product_code VARCHAR(50) NOT NULL DEFAULT '',
+    tenor INT NOT NULL,
+    amount NUMERIC(15, 2) NOT NULL,
+    reference VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE',
+    nomor_kontrak VARCHAR(100) REFERENCES transactions(nomor_kontrak),
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_limit_holds_consumer_nik_status ON limit_holds (consumer_nik, status);
+CREATE INDEX idx_limit_holds_active_expires_at ON limit_holds (expires_at) WHERE status = 'ACTIVE';
+
+CREATE TRIGGER update_limit_holds_timestamp BEFORE UPDATE ON limit_holds FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
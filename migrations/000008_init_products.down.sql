@@ -0,0 +1,3 @@
+ALTER TABLE transactions DROP COLUMN IF EXISTS product_code;
+
+DROP TABLE IF EXISTS products;
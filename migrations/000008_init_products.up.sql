@@ -0,0 +1,25 @@
+CREATE TABLE products (
+    code VARCHAR(50) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    tenors INT[] NOT NULL,
+    min_otr NUMERIC(15, 2) NOT NULL,
+    max_otr NUMERIC(15, 2) NOT NULL,
+    min_age INT NOT NULL DEFAULT 21,
+    min_income NUMERIC(15, 2) NOT NULL DEFAULT 0,
+    require_verified_kyc BOOLEAN NOT NULL DEFAULT TRUE,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (cardinality(tenors) > 0),
+    CHECK (min_otr >= 0 AND max_otr >= min_otr)
+);
+
+CREATE TRIGGER update_products_timestamp BEFORE UPDATE ON products FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
+
+INSERT INTO products (code, name, tenors, min_otr, max_otr, min_age, min_income) VALUES
+    ('paylater', 'PayLater', '{1,2,3,6}', 50000, 5000000, 21, 3000000),
+    ('white_goods', 'White Goods', '{3,6}', 1000000, 25000000, 21, 3000000),
+    ('motorcycle', 'Motorcycle', '{3,6}', 5000000, 40000000, 21, 4000000),
+    ('cash_loan', 'Cash Loan', '{1,2,3}', 500000, 10000000, 21, 5000000);
+
+ALTER TABLE transactions ADD COLUMN product_code VARCHAR(50) REFERENCES products(code);
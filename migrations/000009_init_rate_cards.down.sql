@@ -0,0 +1,6 @@
+ALTER TABLE transactions DROP COLUMN IF EXISTS rate_card_id;
+
+DROP TABLE IF EXISTS rate_card_fee_tiers;
+DROP TABLE IF EXISTS rate_cards;
+
+ALTER TABLE consumers DROP COLUMN IF EXISTS risk_grade;
@@ -0,0 +1,56 @@
+ALTER TABLE consumers ADD COLUMN risk_grade VARCHAR(1) NOT NULL DEFAULT 'B' CHECK (risk_grade IN ('A', 'B', 'C'));
+
+CREATE TABLE rate_cards (
+    id BIGSERIAL PRIMARY KEY,
+    product_code VARCHAR(50) REFERENCES products(code) NOT NULL,
+    tenor INT NOT NULL,
+    risk_grade VARCHAR(1) NOT NULL CHECK (risk_grade IN ('A', 'B', 'C')),
+    version INT NOT NULL,
+    interest_rate NUMERIC(9, 6) NOT NULL CHECK (interest_rate >= 0),
+    admin_fee_type VARCHAR(10) NOT NULL CHECK (admin_fee_type IN ('FLAT', 'TIERED')),
+    effective_from TIMESTAMP NOT NULL,
+    effective_to TIMESTAMP,
+    created_by VARCHAR(50),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (product_code, tenor, risk_grade, version),
+    CONSTRAINT chk_rate_cards_validity CHECK (effective_to IS NULL OR effective_to >= effective_from)
+);
+
+CREATE INDEX idx_rate_cards_lookup ON rate_cards (product_code, tenor, risk_grade, effective_from);
+
+CREATE TABLE rate_card_fee_tiers (
+    rate_card_id BIGINT REFERENCES rate_cards(id) ON DELETE CASCADE NOT NULL,
+    min_otr NUMERIC(15, 2) NOT NULL CHECK (min_otr >= 0),
+    fee_amount NUMERIC(15, 2) NOT NULL DEFAULT 0 CHECK (fee_amount >= 0),
+    fee_rate NUMERIC(9, 6) NOT NULL DEFAULT 0 CHECK (fee_rate >= 0),
+    PRIMARY KEY (rate_card_id, min_otr)
+);
+
+-- Interest is a flat monthly rate on the OTR. Grade A consumers get a
+-- discount and grade C a surcharge on the product's base rate.
+INSERT INTO rate_cards (product_code, tenor, risk_grade, version, interest_rate, admin_fee_type, effective_from, created_by)
+SELECT p.code, t.tenor, g.grade, 1, base.rate + g.adjustment, base.fee_type, NOW(), 'system'
+FROM products p
+CROSS JOIN LATERAL unnest(p.tenors) AS t(tenor)
+JOIN (VALUES
+    ('paylater', 0.025, 'FLAT'),
+    ('white_goods', 0.0175, 'TIERED'),
+    ('motorcycle', 0.015, 'TIERED'),
+    ('cash_loan', 0.03, 'FLAT')
+) AS base(code, rate, fee_type) ON base.code = p.code
+CROSS JOIN (VALUES ('A', -0.0025), ('B', 0), ('C', 0.005)) AS g(grade, adjustment);
+
+INSERT INTO rate_card_fee_tiers (rate_card_id, min_otr, fee_amount, fee_rate)
+SELECT rc.id, tier.min_otr, tier.fee_amount, tier.fee_rate
+FROM rate_cards rc
+JOIN (VALUES
+    ('paylater', 0, 0, 0.03),
+    ('cash_loan', 0, 0, 0.05),
+    ('white_goods', 0, 150000, 0),
+    ('white_goods', 5000000, 250000, 0),
+    ('white_goods', 15000000, 0, 0.02),
+    ('motorcycle', 0, 500000, 0),
+    ('motorcycle', 20000000, 0, 0.025)
+) AS tier(product_code, min_otr, fee_amount, fee_rate) ON tier.product_code = rc.product_code;
+
+ALTER TABLE transactions ADD COLUMN rate_card_id BIGINT REFERENCES rate_cards(id);
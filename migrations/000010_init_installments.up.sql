@@ -0,0 +1,17 @@
+CREATE TABLE installments (
+    nomor_kontrak VARCHAR(100) REFERENCES transactions(nomor_kontrak) ON DELETE CASCADE NOT NULL,
+    installment_number INT NOT NULL,
+    due_date DATE NOT NULL,
+    principal NUMERIC(15, 2) NOT NULL,
+    interest NUMERIC(15, 2) NOT NULL,
+    admin_fee NUMERIC(15, 2) NOT NULL,
+    total_amount NUMERIC(15, 2) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (nomor_kontrak, installment_number),
+    CONSTRAINT chk_installments_total CHECK (total_amount = principal + interest + admin_fee)
+);
+
+CREATE INDEX idx_installments_due_date ON installments (due_date);
+
+CREATE TRIGGER update_installments_timestamp BEFORE UPDATE ON installments FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
@@ -0,0 +1,5 @@
+ALTER TABLE products ADD COLUMN interest_method VARCHAR(20) NOT NULL DEFAULT 'FLAT'
+    CHECK (interest_method IN ('FLAT', 'ANNUITY', 'DECLINING_BALANCE'));
+
+UPDATE products SET interest_method = 'ANNUITY' WHERE code IN ('white_goods', 'motorcycle');
+UPDATE products SET interest_method = 'DECLINING_BALANCE' WHERE code = 'cash_loan';
@@ -0,0 +1,17 @@
+DROP TABLE IF EXISTS payment_allocations;
+DROP TABLE IF EXISTS payments;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS total_paid,
+    DROP COLUMN IF EXISTS outstanding_principal;
+
+ALTER TABLE installments
+    DROP CONSTRAINT IF EXISTS chk_installments_paid,
+    DROP CONSTRAINT IF EXISTS chk_installments_status,
+    DROP COLUMN IF EXISTS paid_at,
+    DROP COLUMN IF EXISTS status,
+    DROP COLUMN IF EXISTS paid_penalty,
+    DROP COLUMN IF EXISTS paid_admin_fee,
+    DROP COLUMN IF EXISTS paid_interest,
+    DROP COLUMN IF EXISTS paid_principal,
+    DROP COLUMN IF EXISTS penalty;
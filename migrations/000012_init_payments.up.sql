@@ -0,0 +1,47 @@
+ALTER TABLE installments
+    ADD COLUMN penalty NUMERIC(15, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN paid_principal NUMERIC(15, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN paid_interest NUMERIC(15, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN paid_admin_fee NUMERIC(15, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN paid_penalty NUMERIC(15, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN status VARCHAR(10) NOT NULL DEFAULT 'UNPAID',
+    ADD COLUMN paid_at TIMESTAMP,
+    ADD CONSTRAINT chk_installments_status CHECK (status IN ('UNPAID', 'PARTIAL', 'PAID')),
+    ADD CONSTRAINT chk_installments_paid CHECK (
+        paid_principal BETWEEN 0 AND principal
+        AND paid_interest BETWEEN 0 AND interest
+        AND paid_admin_fee BETWEEN 0 AND admin_fee
+        AND paid_penalty BETWEEN 0 AND penalty
+    );
+
+ALTER TABLE transactions
+    ADD COLUMN outstanding_principal NUMERIC(15, 2),
+    ADD COLUMN total_paid NUMERIC(15, 2) NOT NULL DEFAULT 0;
+
+UPDATE transactions SET outstanding_principal = otr;
+
+ALTER TABLE transactions ALTER COLUMN outstanding_principal SET NOT NULL;
+
+CREATE TABLE payments (
+    id BIGSERIAL PRIMARY KEY,
+    nomor_kontrak VARCHAR(100) REFERENCES transactions(nomor_kontrak) ON DELETE CASCADE NOT NULL,
+    reference VARCHAR(100) NOT NULL,
+    amount NUMERIC(15, 2) NOT NULL,
+    allocated_amount NUMERIC(15, 2) NOT NULL,
+    unapplied_amount NUMERIC(15, 2) NOT NULL,
+    paid_at TIMESTAMP NOT NULL,
+    posted_by VARCHAR(100) REFERENCES staffs(username) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (nomor_kontrak, reference),
+    CONSTRAINT chk_payments_amount CHECK (amount > 0 AND amount = allocated_amount + unapplied_amount)
+);
+
+CREATE TABLE payment_allocations (
+    payment_id BIGINT REFERENCES payments(id) ON DELETE CASCADE NOT NULL,
+    installment_number INT NOT NULL,
+    component VARCHAR(10) NOT NULL,
+    amount NUMERIC(15, 2) NOT NULL,
+    PRIMARY KEY (payment_id, installment_number, component),
+    CONSTRAINT chk_payment_allocations_component CHECK (component IN ('PENALTY', 'ADMIN_FEE', 'INTEREST', 'PRINCIPAL')),
+    CONSTRAINT chk_payment_allocations_amount CHECK (amount > 0)
+);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS transaction_status_transitions;
+
+ALTER TABLE transactions
+    DROP CONSTRAINT IF EXISTS chk_transactions_status,
+    ALTER COLUMN status SET DEFAULT 'ACTIVE';
+
+UPDATE transactions SET status = 'PENDING' WHERE status = 'PENDING_DISBURSEMENT';
@@ -0,0 +1,23 @@
+UPDATE transactions SET status = 'PENDING_DISBURSEMENT' WHERE status = 'PENDING';
+
+ALTER TABLE transactions
+    ALTER COLUMN status SET DEFAULT 'PENDING_DISBURSEMENT',
+    ADD CONSTRAINT chk_transactions_status CHECK (status IN (
+        'PENDING_DISBURSEMENT', 'ACTIVE', 'PAID_OFF', 'CANCELLED', 'DEFAULTED', 'WRITTEN_OFF', 'RESTRUCTURED'
+    ));
+
+CREATE TABLE transaction_status_transitions (
+    id BIGSERIAL PRIMARY KEY,
+    nomor_kontrak VARCHAR(100) REFERENCES transactions(nomor_kontrak) ON DELETE CASCADE NOT NULL,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    reason TEXT NOT NULL,
+    changed_by VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_transaction_status_transitions_nomor_kontrak ON transaction_status_transitions (nomor_kontrak, id);
+
+INSERT INTO transaction_status_transitions (nomor_kontrak, from_status, to_status, reason, changed_by, created_at)
+SELECT nomor_kontrak, NULL, status, 'recorded when the status log was introduced', 'system', created_at
+FROM transactions;
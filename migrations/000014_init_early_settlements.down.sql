@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS early_settlements;
+
+UPDATE installments SET status = 'PAID' WHERE status = 'SETTLED';
+
+ALTER TABLE installments
+    DROP CONSTRAINT chk_installments_status,
+    ADD CONSTRAINT chk_installments_status CHECK (status IN ('UNPAID', 'PARTIAL', 'PAID'));
@@ -0,0 +1,22 @@
+ALTER TABLE installments
+    DROP CONSTRAINT chk_installments_status,
+    ADD CONSTRAINT chk_installments_status CHECK (status IN ('UNPAID', 'PARTIAL', 'PAID', 'SETTLED'));
+
+CREATE TABLE early_settlements (
+    nomor_kontrak VARCHAR(100) PRIMARY KEY REFERENCES transactions(nomor_kontrak) ON DELETE CASCADE,
+    reference VARCHAR(100) NOT NULL,
+    remaining_principal NUMERIC(15, 2) NOT NULL,
+    outstanding_penalty NUMERIC(15, 2) NOT NULL,
+    outstanding_admin_fee NUMERIC(15, 2) NOT NULL,
+    accrued_interest NUMERIC(15, 2) NOT NULL,
+    unearned_interest NUMERIC(15, 2) NOT NULL,
+    interest_rebate NUMERIC(15, 2) NOT NULL,
+    early_termination_fee NUMERIC(15, 2) NOT NULL,
+    total_payoff NUMERIC(15, 2) NOT NULL,
+    settled_by VARCHAR(100) REFERENCES staffs(username) NOT NULL,
+    settled_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_early_settlements_total CHECK (
+        total_payoff = remaining_principal + outstanding_penalty + outstanding_admin_fee
+            + accrued_interest + unearned_interest - interest_rebate + early_termination_fee
+    )
+);
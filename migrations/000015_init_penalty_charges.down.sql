@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_installments_open_due_date;
+DROP TABLE IF EXISTS penalty_charges;
+
+ALTER TABLE installments
+    DROP COLUMN IF EXISTS penalty_accrued_to,
+    DROP COLUMN IF EXISTS overdue_since;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS penalty_cap_rate,
+    DROP COLUMN IF EXISTS penalty_daily_rate,
+    DROP COLUMN IF EXISTS grace_period_days;
@@ -0,0 +1,29 @@
+ALTER TABLE products
+    ADD COLUMN grace_period_days INT NOT NULL DEFAULT 3 CHECK (grace_period_days >= 0),
+    ADD COLUMN penalty_daily_rate NUMERIC(9, 6) NOT NULL DEFAULT 0.001 CHECK (penalty_daily_rate >= 0),
+    ADD COLUMN penalty_cap_rate NUMERIC(9, 6) NOT NULL DEFAULT 0.1 CHECK (penalty_cap_rate >= 0);
+
+UPDATE products SET grace_period_days = 0, penalty_daily_rate = 0.0015 WHERE code = 'paylater';
+UPDATE products SET grace_period_days = 5, penalty_daily_rate = 0.0005 WHERE code = 'motorcycle';
+
+-- penalty_accrued_to is the last date penalties were charged for, so a
+-- missed or repeated run of the accrual job neither skips nor doubles days.
+ALTER TABLE installments
+    ADD COLUMN overdue_since DATE,
+    ADD COLUMN penalty_accrued_to DATE;
+
+CREATE TABLE penalty_charges (
+    id BIGSERIAL PRIMARY KEY,
+    nomor_kontrak VARCHAR(100) NOT NULL,
+    installment_number INT NOT NULL,
+    charge_date DATE NOT NULL,
+    days INT NOT NULL CHECK (days > 0),
+    base_amount NUMERIC(15, 2) NOT NULL CHECK (base_amount > 0),
+    daily_rate NUMERIC(9, 6) NOT NULL CHECK (daily_rate >= 0),
+    amount NUMERIC(15, 2) NOT NULL CHECK (amount > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (nomor_kontrak, installment_number, charge_date),
+    FOREIGN KEY (nomor_kontrak, installment_number) REFERENCES installments(nomor_kontrak, installment_number) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_installments_open_due_date ON installments (due_date) WHERE status IN ('UNPAID', 'PARTIAL');
@@ -0,0 +1,10 @@
+-- A key is claimed and its response stored in the same transaction as the
+-- request's own writes, so a committed key always has a response.
+CREATE TABLE idempotency_keys (
+    owner VARCHAR(100) NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    response_body JSONB,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (owner, idempotency_key)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE products DROP COLUMN IF EXISTS contract_prefix;
+
+DROP SEQUENCE IF EXISTS contract_number_seq;
@@ -0,0 +1,10 @@
+CREATE SEQUENCE contract_number_seq AS BIGINT START 1;
+
+ALTER TABLE products ADD COLUMN contract_prefix VARCHAR(4) UNIQUE CHECK (contract_prefix ~ '^[A-Z]{2,4}$');
+
+UPDATE products SET contract_prefix = 'PL' WHERE code = 'paylater';
+UPDATE products SET contract_prefix = 'WG' WHERE code = 'white_goods';
+UPDATE products SET contract_prefix = 'MC' WHERE code = 'motorcycle';
+UPDATE products SET contract_prefix = 'CL' WHERE code = 'cash_loan';
+
+ALTER TABLE products ALTER COLUMN contract_prefix SET NOT NULL;
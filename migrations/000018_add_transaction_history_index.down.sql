@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_transactions_consumer_nik_created_at;
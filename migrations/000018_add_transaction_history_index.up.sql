@@ -0,0 +1 @@
+CREATE INDEX idx_transactions_consumer_nik_created_at ON transactions (consumer_nik, created_at, nomor_kontrak);
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS merchant_notifications;
+DROP TABLE IF EXISTS contract_cancellations;
+
+UPDATE installments SET status = 'UNPAID' WHERE status = 'CANCELLED';
+
+ALTER TABLE installments
+    DROP CONSTRAINT chk_installments_status,
+    ADD CONSTRAINT chk_installments_status CHECK (status IN ('UNPAID', 'PARTIAL', 'PAID', 'SETTLED'));
@@ -0,0 +1,24 @@
+ALTER TABLE installments
+    DROP CONSTRAINT chk_installments_status,
+    ADD CONSTRAINT chk_installments_status CHECK (status IN ('UNPAID', 'PARTIAL', 'PAID', 'SETTLED', 'CANCELLED'));
+
+CREATE TABLE contract_cancellations (
+    nomor_kontrak VARCHAR(100) PRIMARY KEY REFERENCES transactions(nomor_kontrak) ON DELETE CASCADE,
+    reason TEXT NOT NULL,
+    reversed_admin_fee NUMERIC(15, 2) NOT NULL,
+    reversed_interest NUMERIC(15, 2) NOT NULL,
+    released_limit NUMERIC(15, 2) NOT NULL,
+    cancelled_by VARCHAR(100) NOT NULL,
+    cancelled_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE merchant_notifications (
+    id BIGSERIAL PRIMARY KEY,
+    nomor_kontrak VARCHAR(100) NOT NULL REFERENCES transactions(nomor_kontrak) ON DELETE CASCADE,
+    event VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMP
+);
+
+CREATE INDEX idx_merchant_notifications_undelivered ON merchant_notifications (id) WHERE delivered_at IS NULL;
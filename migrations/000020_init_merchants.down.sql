@@ -0,0 +1,17 @@
+DELETE FROM staffs WHERE role = 'merchant';
+
+ALTER TABLE staffs
+    DROP CONSTRAINT IF EXISTS chk_staffs_merchant,
+    DROP COLUMN IF EXISTS merchant_id;
+
+ALTER TABLE merchant_notifications DROP COLUMN IF EXISTS merchant_id;
+
+DROP INDEX IF EXISTS idx_transactions_merchant_id_created_at;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS mdr_amount,
+    DROP COLUMN IF EXISTS outlet_id,
+    DROP COLUMN IF EXISTS merchant_id;
+
+DROP TABLE IF EXISTS merchant_outlets;
+DROP TABLE IF EXISTS merchants;
@@ -0,0 +1,49 @@
+CREATE TABLE merchants (
+    id BIGSERIAL PRIMARY KEY,
+    code VARCHAR(50) UNIQUE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    mdr_rate NUMERIC(9, 6) NOT NULL CHECK (mdr_rate >= 0 AND mdr_rate < 1),
+    settlement_bank_code VARCHAR(20) NOT NULL,
+    settlement_account_number VARCHAR(50) NOT NULL,
+    settlement_account_name VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('ACTIVE', 'SUSPENDED', 'TERMINATED')),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TRIGGER update_merchants_timestamp BEFORE UPDATE ON merchants FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
+
+CREATE TABLE merchant_outlets (
+    id BIGSERIAL PRIMARY KEY,
+    merchant_id BIGINT NOT NULL REFERENCES merchants(id),
+    code VARCHAR(50) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    address TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('ACTIVE', 'CLOSED')),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (merchant_id, code)
+);
+
+CREATE TRIGGER update_merchant_outlets_timestamp BEFORE UPDATE ON merchant_outlets FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
+
+-- Contracts opened before merchants were recorded keep a NULL merchant.
+ALTER TABLE transactions
+    ADD COLUMN merchant_id BIGINT REFERENCES merchants(id),
+    ADD COLUMN outlet_id BIGINT REFERENCES merchant_outlets(id),
+    ADD COLUMN mdr_amount NUMERIC(15, 2) NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_transactions_merchant_id_created_at ON transactions (merchant_id, created_at, nomor_kontrak);
+
+ALTER TABLE merchant_notifications ADD COLUMN merchant_id BIGINT REFERENCES merchants(id);
+
+-- Merchant staff sign in with the staff login and only see their merchant.
+ALTER TABLE staffs
+    ADD COLUMN merchant_id BIGINT REFERENCES merchants(id),
+    ADD CONSTRAINT chk_staffs_merchant CHECK ((role = 'merchant') = (merchant_id IS NOT NULL));
+
+INSERT INTO merchants (code, name, mdr_rate, settlement_bank_code, settlement_account_number, settlement_account_name) VALUES
+('ELEKTRO', 'Toko Elektro Jaya', 0.015, '014', '1234567890', 'PT Elektro Jaya');
+
+INSERT INTO merchant_outlets (merchant_id, code, name, address)
+SELECT id, 'JKT-01', 'Elektro Jaya Kelapa Gading', 'Jl. Boulevard Raya, Jakarta Utara' FROM merchants WHERE code = 'ELEKTRO';
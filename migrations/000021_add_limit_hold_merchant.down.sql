@@ -0,0 +1 @@
+ALTER TABLE limit_holds DROP COLUMN IF EXISTS merchant_id;
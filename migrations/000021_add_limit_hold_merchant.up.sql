@@ -0,0 +1,4 @@
+-- Holds placed by a merchant's staff record the merchant, which is the only
+-- merchant that may capture or release them. Holds placed by the consumer
+-- keep a NULL merchant.
+ALTER TABLE limit_holds ADD COLUMN merchant_id BIGINT REFERENCES merchants(id);
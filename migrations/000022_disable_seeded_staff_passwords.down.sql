@@ -0,0 +1 @@
+-- The published password is not restored.
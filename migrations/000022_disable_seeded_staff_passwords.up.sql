@@ -0,0 +1,7 @@
+-- Earlier versions of 000004 and 000020 seeded staff accounts with a
+-- published password. Databases migrated with them still have those accounts,
+-- and other tables reference them, so their password is disabled instead of
+-- the rows being deleted. Set a new password with cmd/staff-create or, in
+-- development, misc/develop/seed_staff.sql.
+UPDATE staffs SET password_hash = '!'
+WHERE password_hash = '$argon2id$v=19$m=65536,t=3,p=4$BEMOnl7KwywiS5LHLqtmJg$Cm5gGNJjzFo0navnegsRAJIFvPd/nI577+STF/t2z8E';
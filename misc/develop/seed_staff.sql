@@ -0,0 +1,12 @@
+-- Staff accounts for local development only; never run this against a
+-- shared environment. Every account has the password Password@123. Run it
+-- after the migrations with `make seed-dev`.
+INSERT INTO staffs (username, password_hash, full_name, role) VALUES
+('admin', '$argon2id$v=19$m=65536,t=3,p=4$BEMOnl7KwywiS5LHLqtmJg$Cm5gGNJjzFo0navnegsRAJIFvPd/nI577+STF/t2z8E', 'Administrator', 'admin'),
+('analyst', '$argon2id$v=19$m=65536,t=3,p=4$BEMOnl7KwywiS5LHLqtmJg$Cm5gGNJjzFo0navnegsRAJIFvPd/nI577+STF/t2z8E', 'Credit Analyst', 'credit_analyst')
+ON CONFLICT (username) DO UPDATE SET password_hash = EXCLUDED.password_hash;
+
+INSERT INTO staffs (username, password_hash, full_name, role, merchant_id)
+SELECT 'merchant', '$argon2id$v=19$m=65536,t=3,p=4$BEMOnl7KwywiS5LHLqtmJg$Cm5gGNJjzFo0navnegsRAJIFvPd/nI577+STF/t2z8E', 'Elektro Jaya Cashier', 'merchant', id
+FROM merchants WHERE code = 'ELEKTRO'
+ON CONFLICT (username) DO UPDATE SET password_hash = EXCLUDED.password_hash;
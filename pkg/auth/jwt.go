@@ -23,8 +23,15 @@ type JWTManager struct {
 	issuer               string
 }
 
+const (
+	RoleConsumer = "consumer"
+)
+
 type AuthClaims struct {
-	PhoneNumber string `json:"phone_number"`
+	PhoneNumber   string `json:"phone_number,omitempty"`
+	StaffUsername string `json:"staff_username,omitempty"`
+	Role          string `json:"role,omitempty"`
+	MerchantID    int64  `json:"merchant_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -39,28 +46,41 @@ func NewJWTManager(accessSecret, refreshSecret, issuer string, accessDuration, r
 }
 
 func (m *JWTManager) GenerateTokens(phoneNumber string) (accessToken string, refreshToken string, err error) {
-	accessClaims := AuthClaims{
+	return m.generateTokens(phoneNumber, AuthClaims{
 		PhoneNumber: phoneNumber,
-		RegisteredClaims: jwt.RegisteredClaims{
-			Issuer:    m.issuer,
-			Subject:   phoneNumber,
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.accessTokenDuration)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
+		Role:        RoleConsumer,
+	})
+}
+
+// GenerateStaffTokens issues tokens for a staff member. merchantID is zero
+// for staff that do not belong to a merchant.
+func (m *JWTManager) GenerateStaffTokens(username, role string, merchantID int64) (accessToken string, refreshToken string, err error) {
+	return m.generateTokens(username, AuthClaims{
+		StaffUsername: username,
+		Role:          role,
+		MerchantID:    merchantID,
+	})
+}
+
+func (m *JWTManager) generateTokens(subject string, claims AuthClaims) (accessToken string, refreshToken string, err error) {
+	accessClaims := claims
+	accessClaims.RegisteredClaims = jwt.RegisteredClaims{
+		Issuer:    m.issuer,
+		Subject:   subject,
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.accessTokenDuration)),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
 	}
 	accessToken, err = jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims).SignedString([]byte(m.accessSecretKey))
 	if err != nil {
 		return "", "", fmt.Errorf("failed to sign access token: %w", err)
 	}
 
-	refreshClaims := AuthClaims{
-		PhoneNumber: phoneNumber,
-		RegisteredClaims: jwt.RegisteredClaims{
-			Issuer:    m.issuer,
-			Subject:   phoneNumber,
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.refreshTokenDuration)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
+	refreshClaims := claims
+	refreshClaims.RegisteredClaims = jwt.RegisteredClaims{
+		Issuer:    m.issuer,
+		Subject:   subject,
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.refreshTokenDuration)),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
 	}
 	refreshToken, err = jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims).SignedString([]byte(m.refreshSecretKey))
 	if err != nil {
@@ -99,9 +119,12 @@ func (m *JWTManager) validateToken(tokenString string, secretKey string) (*AuthC
 		if claims.Issuer != m.issuer {
 			return nil, fmt.Errorf("%w: invalid issuer", ErrTokenInvalid)
 		}
-		if claims.PhoneNumber == "" {
+		if claims.PhoneNumber == "" && claims.StaffUsername == "" {
 			return nil, ErrMissingClaims
 		}
+		if claims.Role == "" {
+			claims.Role = RoleConsumer
+		}
 		return claims, nil
 	}
 
@@ -0,0 +1,42 @@
+// Package checkdigit computes Luhn mod 36 check characters for identifiers
+// made of digits and upper-case letters. The check character catches every
+// single-character error and most transpositions of adjacent characters; like
+// every Luhn variant it misses swapping the first and last characters of the
+// alphabet, "0Z" and "Z0".
+package checkdigit
+
+import (
+	"errors"
+	"strings"
+)
+
+const alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+var ErrInvalidCharacter = errors.New("identifier may only contain digits and upper-case letters")
+
+// Compute returns the check character for payload.
+func Compute(payload string) (byte, error) {
+	n := len(alphabet)
+	sum := 0
+	factor := 2
+	for i := len(payload) - 1; i >= 0; i-- {
+		value := strings.IndexByte(alphabet, payload[i])
+		if value < 0 {
+			return 0, ErrInvalidCharacter
+		}
+		addend := factor * value
+		sum += addend/n + addend%n
+		factor = 3 - factor
+	}
+	return alphabet[(n-sum%n)%n], nil
+}
+
+// Valid reports whether the last character of s is the check character of
+// the rest.
+func Valid(s string) bool {
+	if len(s) < 2 {
+		return false
+	}
+	check, err := Compute(s[:len(s)-1])
+	return err == nil && check == s[len(s)-1]
+}
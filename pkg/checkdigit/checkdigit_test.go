@@ -0,0 +1,77 @@
+package checkdigit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompute(t *testing.T) {
+	tests := []struct {
+		payload  string
+		expected byte
+	}{
+		{payload: "0", expected: '0'},
+		{payload: "1", expected: 'Y'},
+		{payload: "A", expected: 'G'},
+		{payload: "WG00125041000000012", expected: 'Z'},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.payload, func(t *testing.T) {
+			check, err := Compute(tt.payload)
+			require.NoError(t, err)
+			assert.Equal(t, string(tt.expected), string(check))
+			assert.True(t, Valid(tt.payload+string(check)))
+		})
+	}
+}
+
+func TestCompute_InvalidCharacter(t *testing.T) {
+	_, err := Compute("wg-001")
+	assert.ErrorIs(t, err, ErrInvalidCharacter)
+}
+
+func TestValid_DetectsSingleErrorsAndTranspositions(t *testing.T) {
+	payload := "WG00125041000000012"
+	check, err := Compute(payload)
+	require.NoError(t, err)
+	valid := payload + string(check)
+
+	for i := 0; i < len(valid); i++ {
+		for j := 0; j < len(alphabet); j++ {
+			if alphabet[j] == valid[i] {
+				continue
+			}
+			mutated := valid[:i] + string(alphabet[j]) + valid[i+1:]
+			assert.False(t, Valid(mutated), "substitution at %d accepted: %s", i, mutated)
+		}
+	}
+
+	for i := 0; i+1 < len(valid); i++ {
+		if valid[i] == valid[i+1] {
+			continue
+		}
+		swapped := valid[:i] + string(valid[i+1]) + string(valid[i]) + valid[i+2:]
+		assert.False(t, Valid(swapped), "transposition at %d accepted: %s", i, swapped)
+	}
+}
+
+// Luhn mod 36 cannot tell "0Z" from "Z0": both pairs add up to the same
+// amount whichever of the two positions is doubled.
+func TestCompute_ZeroZTranspositionIsUndetected(t *testing.T) {
+	original, err := Compute("PL001251019000000Z0")
+	require.NoError(t, err)
+	swapped, err := Compute("PL0012510190000000Z")
+	require.NoError(t, err)
+
+	assert.Equal(t, original, swapped)
+	assert.Equal(t, byte('3'), original)
+}
+
+func TestValid_Malformed(t *testing.T) {
+	assert.False(t, Valid(""))
+	assert.False(t, Valid("7"))
+	assert.False(t, Valid("wg1"))
+}
@@ -0,0 +1,74 @@
+package loancalc
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/glennprays/xyz-fin/pkg/money"
+)
+
+// Disclosure holds the cost-of-credit figures shown to the consumer. They
+// are derived from the monthly internal rate of return of the cash flows:
+// the amount financed paid out today against the monthly payments,
+// including any fees spread over the installments.
+type Disclosure struct {
+	// MonthlyRate is the monthly internal rate of return.
+	MonthlyRate money.Rate `json:"monthly_rate"`
+	// APR is the nominal annual rate, twelve times the monthly rate.
+	APR money.Rate `json:"apr"`
+	// EIR is the effective annual rate with monthly compounding.
+	EIR money.Rate `json:"eir"`
+}
+
+const (
+	irrMaxMonthlyRate = 1.0
+	irrIterations     = 200
+)
+
+// Disclose solves for the monthly rate at which the payments repay the
+// amount financed. The rate has no closed form, so it is found by bisection
+// in floating point; the figures are disclosure estimates rounded to six
+// decimals and are never used to compute amounts owed.
+func Disclose(amountFinanced money.Amount, payments []money.Amount) Disclosure {
+	var totalPayment money.Amount
+	for _, payment := range payments {
+		totalPayment += payment
+	}
+	if amountFinanced <= 0 || totalPayment <= amountFinanced {
+		return Disclosure{}
+	}
+
+	financed := float64(amountFinanced.Sen())
+	presentValue := func(rate float64) float64 {
+		var value float64
+		for i, payment := range payments {
+			value += float64(payment.Sen()) / math.Pow(1+rate, float64(i+1))
+		}
+		return value
+	}
+
+	low, high := 0.0, irrMaxMonthlyRate
+	for i := 0; i < irrIterations; i++ {
+		mid := (low + high) / 2
+		if presentValue(mid) > financed {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	monthly := (low + high) / 2
+
+	return Disclosure{
+		MonthlyRate: rateOf(monthly),
+		APR:         rateOf(monthly * 12),
+		EIR:         rateOf(math.Pow(1+monthly, 12) - 1),
+	}
+}
+
+func rateOf(value float64) money.Rate {
+	rate, err := money.ParseRate(strconv.FormatFloat(value, 'f', 6, 64))
+	if err != nil {
+		return 0
+	}
+	return rate
+}
@@ -0,0 +1,175 @@
+// Package loancalc computes repayment plans for the supported interest
+// methods and the APR and EIR figures disclosed to consumers. All amounts are
+// rounded to whole rupiah; the last period absorbs whatever rounding left
+// over so a plan always repays the principal exactly.
+package loancalc
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/glennprays/xyz-fin/pkg/money"
+)
+
+const (
+	// MethodFlat charges the monthly rate on the original principal for
+	// every period and spreads principal and interest evenly.
+	MethodFlat = "FLAT"
+	// MethodAnnuity charges the monthly rate on the outstanding balance and
+	// keeps the payment constant, so the principal part grows over time.
+	MethodAnnuity = "ANNUITY"
+	// MethodDecliningBalance repays the same principal every period and
+	// charges the monthly rate on the outstanding balance, so payments
+	// slide down over time.
+	MethodDecliningBalance = "DECLINING_BALANCE"
+)
+
+var ErrUnknownMethod = errors.New("unknown interest method")
+
+// Period is one monthly payment. Balance is the principal still outstanding
+// after the payment.
+type Period struct {
+	Number    int
+	Principal money.Amount
+	Interest  money.Amount
+	Payment   money.Amount
+	Balance   money.Amount
+}
+
+type Plan struct {
+	Method        string
+	Periods       []Period
+	TotalInterest money.Amount
+	TotalPayment  money.Amount
+}
+
+type Calculator interface {
+	Method() string
+	// Plan repays principal over tenor months at the given monthly rate.
+	Plan(principal money.Amount, rate money.Rate, tenor int) Plan
+}
+
+func New(method string) (Calculator, error) {
+	switch method {
+	case MethodFlat:
+		return flatCalculator{}, nil
+	case MethodAnnuity:
+		return annuityCalculator{}, nil
+	case MethodDecliningBalance:
+		return decliningBalanceCalculator{}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownMethod, method)
+	}
+}
+
+// Split divides total into parts equal shares rounded down to whole rupiah,
+// with the remainder on the last share.
+func Split(total money.Amount, parts int) []money.Amount {
+	share := total.Div(int64(parts), money.RoundDown).Round(money.Rupiah, money.RoundDown)
+
+	amounts := make([]money.Amount, parts)
+	for i := range amounts {
+		amounts[i] = share
+	}
+	amounts[parts-1] = total - share.MulInt(int64(parts-1))
+	return amounts
+}
+
+type flatCalculator struct{}
+
+func (flatCalculator) Method() string {
+	return MethodFlat
+}
+
+func (flatCalculator) Plan(principal money.Amount, rate money.Rate, tenor int) Plan {
+	totalInterest := principal.MulInt(int64(tenor)).Mul(rate, money.RoundHalfUp).Round(money.Rupiah, money.RoundHalfUp)
+	principalParts := Split(principal, tenor)
+	interestParts := Split(totalInterest, tenor)
+
+	return buildPlan(MethodFlat, principal, tenor, func(number int, _ money.Amount) (money.Amount, money.Amount) {
+		return principalParts[number-1], interestParts[number-1]
+	})
+}
+
+type annuityCalculator struct{}
+
+func (annuityCalculator) Method() string {
+	return MethodAnnuity
+}
+
+func (annuityCalculator) Plan(principal money.Amount, rate money.Rate, tenor int) Plan {
+	payment := annuityPayment(principal, rate, tenor)
+
+	return buildPlan(MethodAnnuity, principal, tenor, func(number int, balance money.Amount) (money.Amount, money.Amount) {
+		interest := interestOn(balance, rate)
+		principalPart := payment - interest
+		if number == tenor || principalPart > balance {
+			principalPart = balance
+		}
+		return principalPart, interest
+	})
+}
+
+// annuityPayment returns P * r * (1+r)^n / ((1+r)^n - 1) rounded half up to
+// whole rupiah, computed exactly on the rate's integer representation.
+func annuityPayment(principal money.Amount, rate money.Rate, tenor int) money.Amount {
+	if rate == 0 {
+		return principal.Div(int64(tenor), money.RoundDown).Round(money.Rupiah, money.RoundDown)
+	}
+
+	scale := big.NewInt(int64(money.One))
+	n := big.NewInt(int64(tenor))
+	growth := new(big.Int).Exp(new(big.Int).Add(scale, big.NewInt(int64(rate))), n, nil)
+	base := new(big.Int).Exp(scale, n, nil)
+
+	num := new(big.Int).Mul(big.NewInt(int64(rate)), growth)
+	den := new(big.Int).Mul(scale, new(big.Int).Sub(growth, base))
+	return principal.MulFrac(num, den, money.RoundHalfUp).Round(money.Rupiah, money.RoundHalfUp)
+}
+
+type decliningBalanceCalculator struct{}
+
+func (decliningBalanceCalculator) Method() string {
+	return MethodDecliningBalance
+}
+
+func (decliningBalanceCalculator) Plan(principal money.Amount, rate money.Rate, tenor int) Plan {
+	principalParts := Split(principal, tenor)
+
+	return buildPlan(MethodDecliningBalance, principal, tenor, func(number int, balance money.Amount) (money.Amount, money.Amount) {
+		return principalParts[number-1], interestOn(balance, rate)
+	})
+}
+
+func interestOn(balance money.Amount, rate money.Rate) money.Amount {
+	return balance.Mul(rate, money.RoundHalfUp).Round(money.Rupiah, money.RoundHalfUp)
+}
+
+// buildPlan walks the periods and tracks the outstanding balance. next
+// returns the principal and interest parts of a period given the balance
+// before it.
+func buildPlan(method string, principal money.Amount, tenor int, next func(number int, balance money.Amount) (principalPart, interest money.Amount)) Plan {
+	plan := Plan{
+		Method:  method,
+		Periods: make([]Period, 0, tenor),
+	}
+
+	balance := principal
+	for number := 1; number <= tenor; number++ {
+		principalPart, interest := next(number, balance)
+		balance -= principalPart
+
+		period := Period{
+			Number:    number,
+			Principal: principalPart,
+			Interest:  interest,
+			Payment:   principalPart + interest,
+			Balance:   balance,
+		}
+		plan.Periods = append(plan.Periods, period)
+		plan.TotalInterest += period.Interest
+		plan.TotalPayment += period.Payment
+	}
+	return plan
+}
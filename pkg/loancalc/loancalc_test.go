@@ -0,0 +1,158 @@
+package loancalc
+
+import (
+	"testing"
+
+	"github.com/glennprays/xyz-fin/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rupiah(values ...int64) []money.Amount {
+	amounts := make([]money.Amount, 0, len(values))
+	for _, value := range values {
+		amounts = append(amounts, money.FromRupiah(value))
+	}
+	return amounts
+}
+
+func TestCalculator_Plan(t *testing.T) {
+	tests := []struct {
+		name              string
+		method            string
+		principal         money.Amount
+		rate              string
+		tenor             int
+		expectedPayments  []money.Amount
+		expectedInterests []money.Amount
+	}{
+		{
+			name:              "flat spreads interest evenly",
+			method:            MethodFlat,
+			principal:         money.FromRupiah(1200000),
+			rate:              "0.02",
+			tenor:             3,
+			expectedPayments:  rupiah(424000, 424000, 424000),
+			expectedInterests: rupiah(24000, 24000, 24000),
+		},
+		{
+			name:              "flat puts the remainder on the last period",
+			method:            MethodFlat,
+			principal:         money.FromRupiah(1000000),
+			rate:              "0.025",
+			tenor:             3,
+			expectedPayments:  rupiah(358333, 358333, 358334),
+			expectedInterests: rupiah(25000, 25000, 25000),
+		},
+		{
+			name:              "annuity keeps the payment constant",
+			method:            MethodAnnuity,
+			principal:         money.FromRupiah(1000000),
+			rate:              "0.02",
+			tenor:             6,
+			expectedPayments:  rupiah(178526, 178526, 178526, 178526, 178526, 178524),
+			expectedInterests: rupiah(20000, 16829, 13596, 10297, 6932, 3500),
+		},
+		{
+			name:              "annuity without interest",
+			method:            MethodAnnuity,
+			principal:         money.FromRupiah(1000000),
+			rate:              "0",
+			tenor:             3,
+			expectedPayments:  rupiah(333333, 333333, 333334),
+			expectedInterests: rupiah(0, 0, 0),
+		},
+		{
+			name:              "declining balance slides the payment down",
+			method:            MethodDecliningBalance,
+			principal:         money.FromRupiah(1200000),
+			rate:              "0.02",
+			tenor:             3,
+			expectedPayments:  rupiah(424000, 416000, 408000),
+			expectedInterests: rupiah(24000, 16000, 8000),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calculator, err := New(tt.method)
+			require.NoError(t, err)
+			assert.Equal(t, tt.method, calculator.Method())
+
+			plan := calculator.Plan(tt.principal, money.MustParseRate(tt.rate), tt.tenor)
+			require.Len(t, plan.Periods, tt.tenor)
+
+			var payments, interests []money.Amount
+			var principal, totalInterest, totalPayment money.Amount
+			for i, period := range plan.Periods {
+				assert.Equal(t, i+1, period.Number)
+				assert.Equal(t, period.Principal+period.Interest, period.Payment)
+				payments = append(payments, period.Payment)
+				interests = append(interests, period.Interest)
+				principal += period.Principal
+				totalInterest += period.Interest
+				totalPayment += period.Payment
+			}
+
+			assert.Equal(t, tt.expectedPayments, payments)
+			assert.Equal(t, tt.expectedInterests, interests)
+			assert.Equal(t, tt.principal, principal)
+			assert.Equal(t, money.Zero, plan.Periods[tt.tenor-1].Balance)
+			assert.Equal(t, totalInterest, plan.TotalInterest)
+			assert.Equal(t, totalPayment, plan.TotalPayment)
+		})
+	}
+}
+
+func TestNew_UnknownMethod(t *testing.T) {
+	calculator, err := New("BALLOON")
+	assert.ErrorIs(t, err, ErrUnknownMethod)
+	assert.Nil(t, calculator)
+}
+
+func TestSplit(t *testing.T) {
+	assert.Equal(t, rupiah(333333, 333333, 333334), Split(money.FromRupiah(1000000), 3))
+	assert.Equal(t, []money.Amount{money.FromRupiah(500000), money.MustParse("500000.50")}, Split(money.MustParse("1000000.50"), 2))
+	assert.Equal(t, rupiah(0, 0, 0), Split(money.Zero, 3))
+}
+
+func TestDisclose(t *testing.T) {
+	tests := []struct {
+		name           string
+		amountFinanced money.Amount
+		payments       []money.Amount
+		expected       Disclosure
+	}{
+		{
+			name:           "single period",
+			amountFinanced: money.FromRupiah(1000000),
+			payments:       rupiah(1100000),
+			expected: Disclosure{
+				MonthlyRate: money.MustParseRate("0.1"),
+				APR:         money.MustParseRate("1.2"),
+				EIR:         money.MustParseRate("2.138428"),
+			},
+		},
+		{
+			name:           "rounded annuity payments disclose just under the contract rate",
+			amountFinanced: money.FromRupiah(1000000),
+			payments:       rupiah(178526, 178526, 178526, 178526, 178526, 178524),
+			expected: Disclosure{
+				MonthlyRate: money.MustParseRate("0.02"),
+				APR:         money.MustParseRate("0.239997"),
+				EIR:         money.MustParseRate("0.268239"),
+			},
+		},
+		{
+			name:           "no cost of credit",
+			amountFinanced: money.FromRupiah(1000000),
+			payments:       rupiah(500000, 500000),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Disclose(tt.amountFinanced, tt.payments))
+		})
+	}
+}
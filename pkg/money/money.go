@@ -0,0 +1,396 @@
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidAmount = errors.New("invalid money amount")
+	ErrInvalidRate   = errors.New("invalid rate")
+	ErrOverflow      = errors.New("money amount out of range")
+)
+
+// RoundingMode decides how a result that falls between two representable
+// values is rounded.
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds to the nearest value, ties away from zero.
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds to the nearest value, ties to the even neighbour.
+	RoundHalfEven
+	// RoundDown rounds toward zero.
+	RoundDown
+	// RoundUp rounds away from zero.
+	RoundUp
+)
+
+// Amount is an exact rupiah amount held as an integer number of sen
+// (1/100 rupiah), the precision of the NUMERIC(15, 2) columns. Amounts can be
+// added, subtracted and compared with the usual operators; scaling by a
+// fraction goes through Mul, Div or DivRate with an explicit rounding mode.
+type Amount int64
+
+const (
+	Zero Amount = 0
+	Sen  Amount = 1
+	// Rupiah is one whole rupiah.
+	Rupiah Amount = 100
+)
+
+const amountDecimals = 2
+
+// FromRupiah returns the amount for a whole number of rupiah.
+func FromRupiah(rupiah int64) Amount {
+	return Amount(rupiah) * Rupiah
+}
+
+// Parse reads a decimal string such as "1500000" or "1500000.50". More than
+// two fraction digits are rejected rather than rounded.
+func Parse(s string) (Amount, error) {
+	sen, exact, err := parseDecimal(s, amountDecimals, RoundHalfUp)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	if !exact {
+		return 0, fmt.Errorf("%w: %q has more than %d decimals", ErrInvalidAmount, s, amountDecimals)
+	}
+	return Amount(sen), nil
+}
+
+// MustParse is like Parse but panics on invalid input. It is meant for
+// constants.
+func MustParse(s string) Amount {
+	amount, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return amount
+}
+
+func Min(a, b Amount) Amount {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func Max(a, b Amount) Amount {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// Sen returns the amount as an integer number of sen.
+func (a Amount) Sen() int64 {
+	return int64(a)
+}
+
+func (a Amount) String() string {
+	return formatDecimal(int64(a), amountDecimals, false)
+}
+
+// MulInt multiplies the amount by a whole number.
+func (a Amount) MulInt(n int64) Amount {
+	return a * Amount(n)
+}
+
+// Mul scales the amount by rate, rounding the result to whole sen.
+func (a Amount) Mul(rate Rate, mode RoundingMode) Amount {
+	num := new(big.Int).Mul(big.NewInt(int64(a)), big.NewInt(int64(rate)))
+	return Amount(divRound(num, big.NewInt(rateScale), mode))
+}
+
+// Div divides the amount into n parts, rounding the result to whole sen.
+func (a Amount) Div(n int64, mode RoundingMode) Amount {
+	return Amount(divRound(big.NewInt(int64(a)), big.NewInt(n), mode))
+}
+
+// DivRate divides the amount by rate, rounding the result to whole sen.
+func (a Amount) DivRate(rate Rate, mode RoundingMode) Amount {
+	num := new(big.Int).Mul(big.NewInt(int64(a)), big.NewInt(rateScale))
+	return Amount(divRound(num, big.NewInt(int64(rate)), mode))
+}
+
+// MulFrac scales the amount by the exact fraction num/den, rounding the
+// result to whole sen. It is meant for factors that do not fit a Rate, such
+// as compound interest terms.
+func (a Amount) MulFrac(num, den *big.Int, mode RoundingMode) Amount {
+	product := new(big.Int).Mul(big.NewInt(int64(a)), num)
+	return Amount(divRound(product, den, mode))
+}
+
+// Round rounds the amount to a multiple of unit, e.g. Rupiah for whole
+// rupiah or FromRupiah(50000) for limit steps.
+func (a Amount) Round(unit Amount, mode RoundingMode) Amount {
+	if unit <= 0 {
+		return a
+	}
+	return Amount(divRound(big.NewInt(int64(a)), big.NewInt(int64(unit)), mode)) * unit
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+// UnmarshalJSON accepts a decimal string or a JSON number. Numbers are read
+// from their literal text, never through float64.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	text := string(data)
+	if text == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	}
+	amount, err := Parse(text)
+	if err != nil {
+		return err
+	}
+	*a = amount
+	return nil
+}
+
+// Scan reads NUMERIC values. Computed columns with more than two decimals
+// are rounded half up to whole sen.
+func (a *Amount) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return a.scanText(string(v))
+	case string:
+		return a.scanText(v)
+	case int64:
+		*a = FromRupiah(v)
+		return nil
+	case float64:
+		return a.scanText(strconv.FormatFloat(v, 'f', -1, 64))
+	case nil:
+		return fmt.Errorf("%w: cannot scan NULL, use sql.Null[money.Amount]", ErrInvalidAmount)
+	default:
+		return fmt.Errorf("%w: cannot scan %T", ErrInvalidAmount, src)
+	}
+}
+
+func (a *Amount) scanText(text string) error {
+	sen, _, err := parseDecimal(text, amountDecimals, RoundHalfUp)
+	if err != nil {
+		return fmt.Errorf("%w: %q", ErrInvalidAmount, text)
+	}
+	*a = Amount(sen)
+	return nil
+}
+
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
+
+// Rate is an exact decimal factor such as a fee percentage, an interest rate
+// or a ratio, held in millionths.
+type Rate int64
+
+const rateScale = 1_000_000
+
+const rateDecimals = 6
+
+// One is the rate 1.
+const One Rate = rateScale
+
+// ParseRate reads a decimal string such as "0.03". More than six fraction
+// digits are rejected.
+func ParseRate(s string) (Rate, error) {
+	micros, exact, err := parseDecimal(s, rateDecimals, RoundHalfUp)
+	if err != nil || !exact {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidRate, s)
+	}
+	return Rate(micros), nil
+}
+
+// MustParseRate is like ParseRate but panics on invalid input. It is meant
+// for constants.
+func MustParseRate(s string) Rate {
+	rate, err := ParseRate(s)
+	if err != nil {
+		panic(err)
+	}
+	return rate
+}
+
+// String returns the rate as a decimal without trailing zeros.
+func (r Rate) String() string {
+	return formatDecimal(int64(r), rateDecimals, true)
+}
+
+// Percent returns the rate as a percentage without trailing zeros, e.g.
+// "3" for 0.03.
+func (r Rate) Percent() string {
+	return formatDecimal(int64(r), rateDecimals-2, true)
+}
+
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	text := string(data)
+	if text == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	}
+	rate, err := ParseRate(text)
+	if err != nil {
+		return err
+	}
+	*r = rate
+	return nil
+}
+
+func (r *Rate) Scan(src interface{}) error {
+	var text string
+	switch v := src.(type) {
+	case []byte:
+		text = string(v)
+	case string:
+		text = v
+	case int64:
+		*r = Rate(v) * One
+		return nil
+	case float64:
+		text = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Errorf("%w: cannot scan %T", ErrInvalidRate, src)
+	}
+	micros, _, err := parseDecimal(text, rateDecimals, RoundHalfUp)
+	if err != nil {
+		return fmt.Errorf("%w: %q", ErrInvalidRate, text)
+	}
+	*r = Rate(micros)
+	return nil
+}
+
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+// parseDecimal converts a decimal string to an integer scaled by
+// 10^decimals. exact reports whether no digits had to be rounded away.
+func parseDecimal(s string, decimals int, mode RoundingMode) (value int64, exact bool, err error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, false, ErrInvalidAmount
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	whole, fraction, _ := strings.Cut(s, ".")
+	if whole == "" && fraction == "" {
+		return 0, false, ErrInvalidAmount
+	}
+	for _, part := range []string{whole, fraction} {
+		if strings.Trim(part, "0123456789") != "" {
+			return 0, false, ErrInvalidAmount
+		}
+	}
+
+	digits := whole + fraction
+	if digits == "" {
+		digits = "0"
+	}
+	num, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return 0, false, ErrInvalidAmount
+	}
+	if negative {
+		num.Neg(num)
+	}
+
+	shift := decimals - len(fraction)
+	if shift >= 0 {
+		num.Mul(num, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(shift)), nil))
+		exact = true
+	} else {
+		den := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-shift)), nil)
+		exact = new(big.Int).Rem(num, den).Sign() == 0
+		num = big.NewInt(divRound(num, den, mode))
+	}
+
+	if !num.IsInt64() {
+		return 0, false, ErrOverflow
+	}
+	return num.Int64(), exact, nil
+}
+
+func formatDecimal(value int64, decimals int, trimZeros bool) string {
+	sign := ""
+	magnitude := new(big.Int).SetInt64(value)
+	if magnitude.Sign() < 0 {
+		sign = "-"
+		magnitude.Neg(magnitude)
+	}
+
+	digits := magnitude.String()
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+	whole, fraction := digits[:len(digits)-decimals], digits[len(digits)-decimals:]
+	if trimZeros {
+		fraction = strings.TrimRight(fraction, "0")
+	}
+	if fraction == "" {
+		return sign + whole
+	}
+	return sign + whole + "." + fraction
+}
+
+// divRound divides num by den and rounds the quotient with mode. The result
+// must fit in an int64; amounts stored in NUMERIC(15, 2) always do.
+func divRound(num, den *big.Int, mode RoundingMode) int64 {
+	if den.Sign() == 0 {
+		panic("money: division by zero")
+	}
+
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Sign() != 0 {
+		// The sign of the exact result decides which direction is "away from zero".
+		direction := int64(num.Sign() * den.Sign())
+
+		twiceRem := new(big.Int).Abs(rem)
+		twiceRem.Lsh(twiceRem, 1)
+		half := twiceRem.Cmp(new(big.Int).Abs(den))
+
+		roundAway := false
+		switch mode {
+		case RoundHalfUp:
+			roundAway = half >= 0
+		case RoundHalfEven:
+			roundAway = half > 0 || (half == 0 && quo.Bit(0) == 1)
+		case RoundUp:
+			roundAway = true
+		case RoundDown:
+			roundAway = false
+		}
+		if roundAway {
+			quo.Add(quo, big.NewInt(direction))
+		}
+	}
+
+	if !quo.IsInt64() {
+		panic(ErrOverflow)
+	}
+	return quo.Int64()
+}
@@ -0,0 +1,137 @@
+package money
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Amount
+		wantErr  bool
+	}{
+		{input: "1500000", expected: 150000000},
+		{input: "1500000.5", expected: 150000050},
+		{input: "1500000.05", expected: 150000005},
+		{input: "-12.34", expected: -1234},
+		{input: ".5", expected: 50},
+		{input: " 10 ", expected: 1000},
+		{input: "0.001", wantErr: true},
+		{input: "1e6", wantErr: true},
+		{input: "", wantErr: true},
+		{input: "abc", wantErr: true},
+		{input: "99999999999999999999", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			amount, err := Parse(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, amount)
+		})
+	}
+}
+
+func TestAmount_String(t *testing.T) {
+	assert.Equal(t, "1500000.00", FromRupiah(1500000).String())
+	assert.Equal(t, "0.05", Amount(5).String())
+	assert.Equal(t, "-12.34", Amount(-1234).String())
+	assert.Equal(t, "0.00", Zero.String())
+}
+
+func TestAmount_Mul(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   Amount
+		rate     string
+		mode     RoundingMode
+		expected Amount
+	}{
+		{name: "exact", amount: FromRupiah(1000000), rate: "0.03", mode: RoundHalfUp, expected: FromRupiah(30000)},
+		{name: "half up rounds tie away from zero", amount: 5, rate: "0.5", mode: RoundHalfUp, expected: 3},
+		{name: "half up negative", amount: -5, rate: "0.5", mode: RoundHalfUp, expected: -3},
+		{name: "half even rounds tie to even", amount: 5, rate: "0.5", mode: RoundHalfEven, expected: 2},
+		{name: "half even rounds tie to even upward", amount: 7, rate: "0.5", mode: RoundHalfEven, expected: 4},
+		{name: "down truncates", amount: 999, rate: "0.03", mode: RoundDown, expected: 29},
+		{name: "up rounds away", amount: 999, rate: "0.03", mode: RoundUp, expected: 30},
+		{name: "no float drift", amount: MustParse("0.10"), rate: "3", mode: RoundHalfUp, expected: MustParse("0.30")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.amount.Mul(MustParseRate(tt.rate), tt.mode))
+		})
+	}
+}
+
+func TestAmount_DivAndRound(t *testing.T) {
+	assert.Equal(t, Amount(33333333), FromRupiah(1000000).Div(3, RoundHalfUp))
+	assert.Equal(t, Amount(33333334), FromRupiah(1000000).Div(3, RoundUp))
+	assert.Equal(t, FromRupiah(1000000), FromRupiah(1050000).DivRate(MustParseRate("1.05"), RoundDown))
+	assert.Equal(t, FromRupiah(8550000), MustParse("8571428.57").Round(FromRupiah(50000), RoundDown))
+	assert.Equal(t, FromRupiah(334), MustParse("333.50").Round(Rupiah, RoundHalfUp))
+	assert.Equal(t, FromRupiah(333), MustParse("333.49").Round(Rupiah, RoundHalfUp))
+	assert.Equal(t, MustParse("666666.67"), FromRupiah(1000000).MulFrac(big.NewInt(2), big.NewInt(3), RoundHalfUp))
+	assert.Equal(t, MustParse("666666.66"), FromRupiah(1000000).MulFrac(big.NewInt(2), big.NewInt(3), RoundDown))
+}
+
+func TestAmount_JSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		OTR Amount `json:"otr"`
+	}{OTR: MustParse("1500000.5")})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"otr":"1500000.50"}`, string(data))
+
+	var req struct {
+		OTR   Amount `json:"otr"`
+		Limit Amount `json:"limit"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"otr":"1500000.50","limit":2000000.1}`), &req))
+	assert.Equal(t, MustParse("1500000.50"), req.OTR)
+	assert.Equal(t, MustParse("2000000.10"), req.Limit)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"otr":"1.001"}`), &req))
+}
+
+func TestAmount_Scan(t *testing.T) {
+	var amount Amount
+
+	require.NoError(t, amount.Scan([]byte("1500000.00")))
+	assert.Equal(t, FromRupiah(1500000), amount)
+
+	require.NoError(t, amount.Scan([]byte("166666.666666666667")))
+	assert.Equal(t, MustParse("166666.67"), amount)
+
+	require.NoError(t, amount.Scan(int64(0)))
+	assert.Equal(t, Zero, amount)
+
+	require.NoError(t, amount.Scan(2500000.5))
+	assert.Equal(t, MustParse("2500000.50"), amount)
+
+	assert.Error(t, amount.Scan(nil))
+
+	value, err := FromRupiah(30000).Value()
+	require.NoError(t, err)
+	assert.Equal(t, "30000.00", value)
+}
+
+func TestRate(t *testing.T) {
+	rate, err := ParseRate("0.055")
+	require.NoError(t, err)
+	assert.Equal(t, "0.055", rate.String())
+	assert.Equal(t, "5.5", rate.Percent())
+	assert.Equal(t, "30", MustParseRate("0.3").Percent())
+	assert.Equal(t, "1", One.String())
+
+	_, err = ParseRate("0.0000001")
+	assert.Error(t, err)
+}
@@ -0,0 +1,81 @@
+// Package quote signs loan quotes so a later request can prove which offer
+// it was given without the server storing it.
+package quote
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/glennprays/xyz-fin/pkg/money"
+)
+
+var (
+	ErrInvalid = errors.New("quote is invalid")
+	ErrExpired = errors.New("quote has expired")
+)
+
+// Claims are the terms a quote commits to.
+type Claims struct {
+	ConsumerNIK string       `json:"nik"`
+	ProductCode string       `json:"product"`
+	OTR         money.Amount `json:"otr"`
+	Tenor       int          `json:"tenor"`
+	RateCardID  int64        `json:"rate_card"`
+	AdminFee    money.Amount `json:"admin_fee"`
+	JumlahBunga money.Amount `json:"interest"`
+	ExpiresAt   int64        `json:"exp"`
+}
+
+type Signer struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewSigner(secret string, ttl time.Duration) *Signer {
+	return &Signer{secret: []byte(secret), ttl: ttl}
+}
+
+// Sign returns the quote ID for claims, valid for the signer's TTL from now.
+func (s *Signer) Sign(claims Claims) (string, time.Time) {
+	expiresAt := time.Now().Add(s.ttl).Truncate(time.Second)
+	claims.ExpiresAt = expiresAt.Unix()
+
+	payload, _ := json.Marshal(claims)
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + s.signature(encoded), expiresAt
+}
+
+// Verify checks the signature and expiry of a quote ID and returns its
+// claims.
+func (s *Signer) Verify(id string) (*Claims, error) {
+	encoded, signature, ok := strings.Cut(id, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(s.signature(encoded))) {
+		return nil, ErrInvalid
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalid
+	}
+
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrInvalid
+	}
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return nil, ErrExpired
+	}
+
+	return &claims, nil
+}
+
+func (s *Signer) signature(encoded string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
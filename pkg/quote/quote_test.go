@@ -0,0 +1,62 @@
+package quote
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/glennprays/xyz-fin/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_RoundTrip(t *testing.T) {
+	signer := NewSigner("secret", 15*time.Minute)
+	claims := Claims{
+		ConsumerNIK: "1234567890123456",
+		ProductCode: "white_goods",
+		OTR:         money.FromRupiah(3000000),
+		Tenor:       6,
+		RateCardID:  14,
+		AdminFee:    money.FromRupiah(150000),
+		JumlahBunga: money.FromRupiah(189735),
+	}
+
+	id, expiresAt := signer.Sign(claims)
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), expiresAt, 2*time.Second)
+
+	verified, err := signer.Verify(id)
+	require.NoError(t, err)
+
+	claims.ExpiresAt = expiresAt.Unix()
+	assert.Equal(t, claims, *verified)
+}
+
+func TestSigner_Verify(t *testing.T) {
+	signer := NewSigner("secret", 15*time.Minute)
+	id, _ := signer.Sign(Claims{ConsumerNIK: "1111", OTR: money.FromRupiah(1000000)})
+	payload, signature, _ := strings.Cut(id, ".")
+
+	forged, _ := NewSigner("other", 15*time.Minute).Sign(Claims{ConsumerNIK: "1111", OTR: money.FromRupiah(1000000)})
+	expired, _ := NewSigner("secret", -time.Minute).Sign(Claims{ConsumerNIK: "1111"})
+
+	tests := []struct {
+		name     string
+		id       string
+		expected error
+	}{
+		{name: "empty", id: "", expected: ErrInvalid},
+		{name: "missing signature", id: payload, expected: ErrInvalid},
+		{name: "tampered payload", id: payload + "x." + signature, expected: ErrInvalid},
+		{name: "other secret", id: forged, expected: ErrInvalid},
+		{name: "expired", id: expired, expected: ErrExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := signer.Verify(tt.id)
+			assert.ErrorIs(t, err, tt.expected)
+			assert.Nil(t, claims)
+		})
+	}
+}